@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/service"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/infrastructure/config"
+	"ddd-user-service/internal/logging"
+	"fmt"
+	"log/slog"
+)
+
+// ensureAdmin creates the configured administrator in the default tenant,
+// or the first served one when there is no default.
+func ensureAdmin(ctx context.Context, users *service.UserService, cfg *config.AdminConfig, tenants *config.TenantConfig, logger *slog.Logger) error {
+	if cfg.Email == "" {
+		return nil
+	}
+
+	tenant := tenants.Default
+	if tenant == "" {
+		tenant = tenants.Allowed[0]
+	}
+
+	created, err := users.EnsureAdmin(tenancy.WithTenant(ctx, tenant), dto.CreateUserRequest{
+		Name:     cfg.Name,
+		Email:    cfg.Email,
+		Username: cfg.Username,
+		Password: cfg.Password,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create administrator: %w", err)
+	}
+	if created {
+		logger.Info("administrator created", slog.String(logging.KeyTenantID, tenant.String()), slog.String("email", cfg.Email))
+	}
+	return nil
+}
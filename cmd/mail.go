@@ -0,0 +1,44 @@
+package main
+
+import (
+	appmail "ddd-user-service/internal/application/mail"
+	"ddd-user-service/internal/infrastructure/config"
+	"ddd-user-service/internal/infrastructure/mail"
+)
+
+// newMailer builds the outbound mail pipeline: templates are rendered
+// synchronously, then messages are queued for delivery through the
+// configured transport.
+func newMailer(cfg *config.MailConfig, suppressions appmail.SuppressionList) (appmail.Mailer, *appmail.Queue, error) {
+	var transport appmail.Mailer
+	switch cfg.Transport {
+	case config.MailTransportSMTP:
+		transport = mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:       cfg.SMTPHost,
+			Port:       cfg.SMTPPort,
+			Username:   cfg.SMTPUsername,
+			Password:   cfg.SMTPPassword,
+			From:       cfg.From,
+			RequireTLS: cfg.SMTPRequireTLS,
+		})
+	case config.MailTransportFile:
+		fileMailer, err := mail.NewFileMailer(cfg.FileDir, cfg.From)
+		if err != nil {
+			return nil, nil, err
+		}
+		transport = fileMailer
+	default:
+		transport = mail.NewLogMailer()
+	}
+
+	queue := appmail.NewQueue(transport, suppressions, appmail.QueueOptions{
+		Workers:        cfg.QueueWorkers,
+		BufferSize:     cfg.QueueSize,
+		MaxAttempts:    cfg.MaxAttempts,
+		InitialBackoff: cfg.InitialBackoff,
+		MaxBackoff:     cfg.MaxBackoff,
+	})
+
+	renderer := appmail.NewTemplateRenderer(mail.Templates(), cfg.DefaultLocale)
+	return appmail.NewTemplatingMailer(renderer, queue), queue, nil
+}
@@ -1,45 +1,366 @@
 package main
 
 import (
+	"context"
+	"ddd-user-service/internal/application/audit"
+	"ddd-user-service/internal/application/avatar"
+	"ddd-user-service/internal/application/event"
+	"ddd-user-service/internal/application/lockout"
+	"ddd-user-service/internal/application/passkey"
+	"ddd-user-service/internal/application/password"
+	"ddd-user-service/internal/application/policy"
 	"ddd-user-service/internal/application/service"
+	"ddd-user-service/internal/application/token"
+	"ddd-user-service/internal/domain"
 	"ddd-user-service/internal/infrastructure/config"
 	"ddd-user-service/internal/infrastructure/repository"
+	"ddd-user-service/internal/infrastructure/tracing"
 	"ddd-user-service/internal/interfaces/http/handler"
+	"ddd-user-service/internal/interfaces/http/middleware"
+	"ddd-user-service/internal/interfaces/http/problem"
 	"ddd-user-service/internal/interfaces/http/router"
-	"log"
+	"ddd-user-service/internal/logging"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
+const shutdownTimeout = 10 * time.Second
+
 func main() {
-	log.Println("Attempting to connect to MongoDB...")
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	logConfig := config.NewLoggingConfig()
+	logger, err := logging.New(os.Stdout, logging.Options{
+		Level:           logConfig.Level,
+		Format:          logConfig.Format,
+		RedactPII:       logConfig.RedactPII,
+		ComponentLevels: logConfig.ComponentLevels,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set up logging: %w", err)
+	}
+	slog.SetDefault(logger)
 
-	var userHandler *handler.UserHandler
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
+	shutdownTracing, err := tracing.Setup(ctx, config.NewTracingConfig())
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("Failed to shut down tracing", logging.KeyError, err)
+		}
+	}()
+
+	// The rules decide how stored emails are keyed, so they are set before
+	// any repository is opened.
+	identifierConfig, err := config.NewIdentifierConfig()
+	if err != nil {
+		return fmt.Errorf("invalid identifier configuration: %w", err)
+	}
+	domain.SetEmailRules(identifierConfig.Email)
+	domain.SetUsernameRules(identifierConfig.Username)
+
+	logger.Info("Attempting to connect to MongoDB...")
+
+	var store *storage
 	mongoConfig := config.NewMongoConfig()
+	mongoConfig.Monitor = tracing.NewMongoCommandMonitor()
 	db, err := mongoConfig.Connect()
 	if err != nil {
-		log.Printf("Failed to connect to MongoDB: %v", err)
-		log.Println("Falling back to in-memory repository")
-		userRepo := repository.NewMemoryUserRepository()
-		userService := service.NewUserService(userRepo)
-		userHandler = handler.NewUserHandler(userService)
+		logger.Warn("Failed to connect to MongoDB, falling back to in-memory repository", logging.KeyError, err)
+		store = newMemoryStorage()
 	} else {
-		log.Println("✅ Connected to MongoDB successfully - using persistent storage!")
-		userRepo := repository.NewMongoUserRepository(db)
-		userService := service.NewUserService(userRepo)
-		userHandler = handler.NewUserHandler(userService)
+		logger.Info("Connected to MongoDB - using persistent storage", "database", mongoConfig.Database)
+		if store, err = newMongoStorage(db); err != nil {
+			return err
+		}
+	}
+
+	auditConfig, err := config.NewAuditConfig()
+	if err != nil {
+		return fmt.Errorf("invalid audit configuration: %w", err)
+	}
+	if auditConfig.Ephemeral {
+		logger.Warn("AUDIT_HMAC_KEY is not set; the audit log will not verify after a restart")
+	}
+
+	// Changes are audited beneath logging and tracing, so the extra reads
+	// the audit needs show up in both.
+	auditedUsers := repository.NewAuditingUserRepository(store.users, audit.NewRecorder(store.audit, auditConfig.HMACKey))
+	userRepo := repository.NewTracingUserRepository(repository.NewLoggingUserRepository(auditedUsers))
+
+	mailConfig, err := config.NewMailConfig()
+	if err != nil {
+		return err
+	}
+	mailer, mailQueue, err := newMailer(mailConfig, store.suppressions)
+	if err != nil {
+		return fmt.Errorf("failed to set up mail: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := mailQueue.Close(ctx); err != nil {
+			logger.Error("Mail queue did not drain before shutdown", logging.KeyError, err)
+		}
+	}()
+
+	registrationConfig, err := config.NewRegistrationConfig()
+	if err != nil {
+		return err
+	}
+
+	verificationConfig, err := config.NewEmailVerificationConfig()
+	if err != nil {
+		return fmt.Errorf("invalid email verification configuration: %w", err)
+	}
+	if verificationConfig.Ephemeral {
+		logger.Warn("EMAIL_VERIFICATION_SECRET is not set; verification tokens will not survive a restart")
+	}
+
+	passwordConfig := config.NewPasswordConfig()
+	hasher := password.NewBcryptHasher(passwordConfig.HashCost)
+	events := event.NewDispatcher()
+
+	orgConfig, err := config.NewOrganizationConfig()
+	if err != nil {
+		return fmt.Errorf("invalid organization configuration: %w", err)
+	}
+	orgService := service.NewOrganizationService(store.organizations, userRepo, service.OrganizationOptions{
+		LastOwnerPolicy: orgConfig.LastOwnerPolicy,
+	})
+	events.Subscribe(domain.EventUserDeleted, orgService.RemoveUserOnEvent)
+	orgHandler := handler.NewOrganizationHandler(orgService)
+
+	policyConfig, err := config.NewPolicyConfig()
+	if err != nil {
+		return fmt.Errorf("invalid policy configuration: %w", err)
+	}
+	policyEngine, err := policy.NewEngine(store.policies, userRepo, policy.Config{
+		ReservedUsernames: policyConfig.ReservedUsernames,
+		BlockedWords:      policyConfig.BlockedWords,
+		BlockedDomains:    policyConfig.BlockedDomains,
+		DetectConfusables: policyConfig.DetectConfusables,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid policy configuration: %w", err)
+	}
+	policyHandler := handler.NewPolicyHandler(service.NewPolicyService(policyEngine))
+	auditHandler := handler.NewAuditHandler(service.NewAuditService(store.audit, auditConfig.HMACKey))
+	attributeHandler := handler.NewAttributeHandler(service.NewAttributeService(store.attributes, userRepo, store.attributeIndexes))
+
+	userService := service.NewUserService(
+		userRepo,
+		mailer,
+		token.NewSigner(verificationConfig.Secret),
+		hasher,
+		events,
+		orgService,
+		policyEngine,
+		store.attributes,
+		verificationConfig.TTL,
+	)
+	userHandler := handler.NewUserHandler(userService, registrationConfig.Mode == config.RegistrationModePrivate)
+
+	tenantConfig, err := config.NewTenantConfig()
+	if err != nil {
+		return fmt.Errorf("invalid tenant configuration: %w", err)
+	}
+
+	adminConfig, err := config.NewAdminConfig()
+	if err != nil {
+		return fmt.Errorf("invalid admin configuration: %w", err)
+	}
+	if err := ensureAdmin(ctx, userService, adminConfig, tenantConfig, logger); err != nil {
+		return err
+	}
+
+	avatarConfig, err := config.NewAvatarConfig()
+	if err != nil {
+		return fmt.Errorf("invalid avatar configuration: %w", err)
+	}
+	blobs, err := newBlobStore(avatarConfig, db)
+	if err != nil {
+		return fmt.Errorf("failed to set up avatar storage: %w", err)
+	}
+	avatarService := service.NewAvatarService(
+		service.AvatarDependencies{
+			Users: userRepo,
+			Blobs: blobs,
+			Processor: avatar.NewProcessor(avatar.Options{
+				MaxBytes:  avatarConfig.MaxBytes,
+				MaxPixels: avatarConfig.MaxPixels,
+				Sizes:     avatarConfig.Sizes,
+			}),
+		},
+		service.AvatarOptions{
+			BaseURL: avatarConfig.BaseURL,
+		},
+	)
+	events.Subscribe(domain.EventUserDeleted, avatarService.RemoveOnEvent)
+	avatarHandler := handler.NewAvatarHandler(avatarService)
+
+	invitationService := service.NewInvitationService(
+		service.InvitationDependencies{
+			Invitations:   store.invitations,
+			Users:         userRepo,
+			Organizations: store.organizations,
+			Attributes:    store.attributes,
+			Hasher:        hasher,
+			Mailer:        mailer,
+			Policy:        policyEngine,
+		},
+		service.InvitationOptions{
+			TTL: config.NewInvitationConfig().TTL,
+		},
+	)
+	inviteHandler := handler.NewInvitationHandler(invitationService)
+
+	authConfig, err := config.NewAuthConfig()
+	if err != nil {
+		return fmt.Errorf("invalid auth configuration: %w", err)
+	}
+	if authConfig.Ephemeral {
+		logger.Warn("AUTH_TOKEN_SECRET is not set; MFA challenges will not survive a restart")
 	}
 
-	r := router.SetupRouter(userHandler)
+	sessionConfig := config.NewSessionConfig()
+	sessionService := service.NewSessionService(store.sessions, userRepo, service.SessionOptions{
+		IdleTimeout:     sessionConfig.IdleTimeout,
+		AbsoluteTimeout: sessionConfig.AbsoluteTimeout,
+	})
+	events.Subscribe(domain.EventPasswordChanged, sessionService.RevokeOnEvent)
+	events.Subscribe(domain.EventUserSuspended, sessionService.RevokeOnEvent)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+
+	apiKeyService := service.NewAPIKeyService(store.apiKeys, userRepo, service.APIKeyOptions{
+		RotationOverlap: config.NewAPIKeyConfig().RotationOverlap,
+	})
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+
+	lockoutConfig, err := config.NewLockoutConfig()
+	if err != nil {
+		return fmt.Errorf("invalid lockout configuration: %w", err)
+	}
+
+	webAuthnConfig := config.NewWebAuthnConfig()
+	relyingParty, err := passkey.NewRelyingParty(webAuthnConfig.RPID, webAuthnConfig.RPName, webAuthnConfig.Origins)
+	if err != nil {
+		return fmt.Errorf("invalid WebAuthn configuration: %w", err)
+	}
+
+	authService := service.NewAuthService(
+		service.AuthDependencies{
+			Users:        userRepo,
+			ResetTokens:  store.passwordResets,
+			Ceremonies:   store.ceremonies,
+			Sessions:     sessionService,
+			Lockout:      lockout.NewGuard(store.loginAttempts, lockoutConfig.Account, lockoutConfig.IP),
+			Hasher:       hasher,
+			Tokens:       token.NewSigner(authConfig.Secret),
+			RelyingParty: relyingParty,
+			Mailer:       mailer,
+			Events:       events,
+		},
+		service.AuthOptions{
+			MFAChallengeTTL:  authConfig.MFAChallengeTTL,
+			MFARequiredRoles: authConfig.MFARequiredRoles,
+			TOTPIssuer:       authConfig.TOTPIssuer,
+			ResetTTL:         passwordConfig.ResetTTL,
+			CeremonyTTL:      webAuthnConfig.CeremonyTTL,
+		},
+	)
+	authHandler := handler.NewAuthHandler(authService)
+
+	rateLimitConfig, err := config.NewRateLimitConfig()
+	if err != nil {
+		return fmt.Errorf("invalid rate limit configuration: %w", err)
+	}
+	var rateLimiter *middleware.RateLimiter
+	if rateLimitConfig.Enabled {
+		rateLimiter = middleware.NewRateLimiter(repository.NewMemoryRateLimitStore(), middleware.RateLimitPolicies{
+			PerIP:   rateLimitConfig.PerIP,
+			PerKey:  rateLimitConfig.PerKey,
+			PerUser: rateLimitConfig.PerUser,
+			Routes:  rateLimitConfig.Routes,
+		}, problem.Reject)
+	}
+
+	httpConfig, err := config.NewHTTPConfig()
+	if err != nil {
+		return fmt.Errorf("invalid HTTP configuration: %w", err)
+	}
+
+	r := router.SetupRouter(router.Dependencies{
+		UserHandler:         userHandler,
+		AuthHandler:         authHandler,
+		SessionHandler:      sessionHandler,
+		APIKeyHandler:       apiKeyHandler,
+		OrgHandler:          orgHandler,
+		InviteHandler:       inviteHandler,
+		PolicyHandler:       policyHandler,
+		AttributeHandler:    attributeHandler,
+		AuditHandler:        auditHandler,
+		AvatarHandler:       avatarHandler,
+		AccessTokenVerifier: sessionService,
+		APIKeyVerifier:      apiKeyService,
+		Logger:              logger,
+		IdempotencyStore:    store.idempotency,
+		IdempotencyTTL:      config.NewIdempotencyConfig().TTL,
+		RateLimiter:         rateLimiter,
+		Tenants: middleware.TenantOptions{
+			Default:    tenantConfig.Default,
+			BaseDomain: tenantConfig.BaseDomain,
+			Allowed:    tenantConfig.Allowed,
+		},
+		TrustedProxies: httpConfig.TrustedProxies,
+	})
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	log.Printf("Server starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	server := &http.Server{
+		Addr:    ":" + port,
+		Handler: r,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("Server starting", "port", port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to start server: %w", err)
+		}
+	case <-ctx.Done():
+		logger.Info("Shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to shut down server: %w", err)
+		}
 	}
 
+	return nil
 }
@@ -0,0 +1,101 @@
+package main
+
+import (
+	appblob "ddd-user-service/internal/application/blob"
+	"ddd-user-service/internal/application/idempotency"
+	"ddd-user-service/internal/application/lockout"
+	"ddd-user-service/internal/application/mail"
+	"ddd-user-service/internal/application/passkey"
+	"ddd-user-service/internal/application/policy"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/infrastructure/blob"
+	"ddd-user-service/internal/infrastructure/config"
+	"ddd-user-service/internal/infrastructure/repository"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// storage groups the adapters chosen at start up: MongoDB when it is
+// reachable, in-memory otherwise.
+type storage struct {
+	users          domain.UserRepository
+	passwordResets domain.PasswordResetRepository
+	ceremonies     passkey.CeremonyStore
+	sessions       domain.SessionRepository
+	loginAttempts  lockout.Store
+	apiKeys        domain.APIKeyRepository
+	organizations  domain.OrganizationRepository
+	invitations    domain.InvitationRepository
+	policies       policy.Store
+	idempotency    idempotency.Store
+	suppressions   mail.SuppressionList
+	attributes     domain.AttributeSchemaRepository
+	audit          domain.AuditRepository
+
+	// attributeIndexes is nil when the user store keeps no indexes.
+	attributeIndexes domain.AttributeIndexer
+}
+
+func newMemoryStorage() *storage {
+	return &storage{
+		users:          repository.NewMemoryUserRepository(),
+		passwordResets: repository.NewMemoryPasswordResetRepository(),
+		ceremonies:     repository.NewMemoryWebAuthnCeremonyStore(),
+		sessions:       repository.NewMemorySessionRepository(),
+		loginAttempts:  repository.NewMemoryLoginAttemptStore(),
+		apiKeys:        repository.NewMemoryAPIKeyRepository(),
+		organizations:  repository.NewMemoryOrganizationRepository(),
+		invitations:    repository.NewMemoryInvitationRepository(),
+		policies:       repository.NewMemoryPolicyStore(),
+		idempotency:    repository.NewMemoryIdempotencyStore(),
+		suppressions:   repository.NewMemorySuppressionList(),
+		attributes:     repository.NewMemoryAttributeSchemaRepository(),
+		audit:          repository.NewMemoryAuditRepository(),
+	}
+}
+
+func newMongoStorage(db *mongo.Database) (*storage, error) {
+	users, err := repository.NewMongoUserRepository(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate users: %w", err)
+	}
+	return &storage{
+		users:          users,
+		passwordResets: repository.NewMongoPasswordResetRepository(db),
+		ceremonies:     repository.NewMongoWebAuthnCeremonyStore(db),
+		sessions:       repository.NewMongoSessionRepository(db),
+		loginAttempts:  repository.NewMongoLoginAttemptStore(db),
+		apiKeys:        repository.NewMongoAPIKeyRepository(db),
+		organizations:  repository.NewMongoOrganizationRepository(db),
+		invitations:    repository.NewMongoInvitationRepository(db),
+		policies:       repository.NewMongoPolicyStore(db),
+		idempotency:    repository.NewMongoIdempotencyStore(db),
+		suppressions:   repository.NewMongoSuppressionList(db),
+		attributes:     repository.NewMongoAttributeSchemaRepository(db),
+		audit:          repository.NewMongoAuditRepository(db),
+
+		attributeIndexes: users,
+	}, nil
+}
+
+// newBlobStore keeps blobs in GridFS alongside the rest of the data when
+// MongoDB is connected, unless the filesystem is configured. db is nil
+// without MongoDB.
+func newBlobStore(cfg *config.AvatarConfig, db *mongo.Database) (appblob.Store, error) {
+	storage := cfg.Storage
+	if storage == "" {
+		storage = config.AvatarStorageFilesystem
+		if db != nil {
+			storage = config.AvatarStorageGridFS
+		}
+	}
+
+	if storage == config.AvatarStorageGridFS {
+		if db == nil {
+			return nil, fmt.Errorf("AVATAR_STORAGE is %s but MongoDB is not connected", config.AvatarStorageGridFS)
+		}
+		return blob.NewGridFSStore(db, "blobs")
+	}
+	return blob.NewFilesystemStore(cfg.Dir)
+}
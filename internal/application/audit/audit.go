@@ -0,0 +1,90 @@
+// Package audit records who changed which user, and how, in a hash-chained
+// log. The HTTP layer puts the actor on the request context; the user
+// repository records every change it stores.
+package audit
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxAppendAttempts bounds the retries when other instances append to the
+// same tenant's chain at the same time.
+const maxAppendAttempts = 5
+
+type contextKey struct{}
+
+func WithActor(ctx context.Context, actor domain.AuditActor) context.Context {
+	return context.WithValue(ctx, contextKey{}, actor)
+}
+
+// ActorFromContext returns the zero actor for changes made outside a
+// request.
+func ActorFromContext(ctx context.Context) domain.AuditActor {
+	actor, _ := ctx.Value(contextKey{}).(domain.AuditActor)
+	return actor
+}
+
+// Recorder appends entries to the tenant's chain.
+type Recorder struct {
+	entries domain.AuditRepository
+	key     []byte
+	now     func() time.Time
+	// locks serialize appends to each tenant's chain in this process, so
+	// retries are only needed against other instances. Tenants do not wait
+	// for each other.
+	locks map[domain.TenantID]*sync.Mutex
+	mutex sync.Mutex
+}
+
+// NewRecorder builds a recorder that hashes entries with key.
+func NewRecorder(entries domain.AuditRepository, key []byte) *Recorder {
+	return &Recorder{
+		entries: entries,
+		key:     key,
+		now:     time.Now,
+		locks:   make(map[domain.TenantID]*sync.Mutex),
+	}
+}
+
+// Record appends an entry for the change, attributed to the context's
+// actor. Changes without field differences are not recorded.
+func (r *Recorder) Record(ctx context.Context, tenant domain.TenantID, userID domain.UserID, action domain.AuditAction, changes []domain.FieldChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	lock := r.lock(tenant)
+	lock.Lock()
+	defer lock.Unlock()
+
+	actor := ActorFromContext(ctx)
+	for range maxAppendAttempts {
+		last, err := r.entries.Last(ctx, tenant)
+		if err != nil {
+			return err
+		}
+		entry := domain.NewAuditEntry(r.key, last, tenant, userID, action, actor, changes, r.now())
+		err = r.entries.Append(ctx, entry)
+		if !errors.Is(err, domain.ErrAuditSequenceTaken) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to append audit entry after %d attempts: %w", maxAppendAttempts, domain.ErrAuditSequenceTaken)
+}
+
+func (r *Recorder) lock(tenant domain.TenantID) *sync.Mutex {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	lock, ok := r.locks[tenant]
+	if !ok {
+		lock = &sync.Mutex{}
+		r.locks[tenant] = lock
+	}
+	return lock
+}
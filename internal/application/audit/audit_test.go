@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"sync"
+	"testing"
+	"time"
+)
+
+// chains is an in-memory AuditRepository. Appends to a tenant in blocked
+// wait until the tenant's channel is closed.
+type chains struct {
+	mutex   sync.Mutex
+	entries map[domain.TenantID][]*domain.AuditEntry
+	blocked map[domain.TenantID]chan struct{}
+}
+
+func newChains() *chains {
+	return &chains{
+		entries: make(map[domain.TenantID][]*domain.AuditEntry),
+		blocked: make(map[domain.TenantID]chan struct{}),
+	}
+}
+
+func (c *chains) Append(_ context.Context, entry *domain.AuditEntry) error {
+	c.mutex.Lock()
+	wait := c.blocked[entry.TenantID]
+	c.mutex.Unlock()
+	if wait != nil {
+		<-wait
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if int64(len(c.entries[entry.TenantID])) >= entry.Sequence {
+		return domain.ErrAuditSequenceTaken
+	}
+	c.entries[entry.TenantID] = append(c.entries[entry.TenantID], entry)
+	return nil
+}
+
+func (c *chains) Last(_ context.Context, tenant domain.TenantID) (*domain.AuditEntry, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if entries := c.entries[tenant]; len(entries) > 0 {
+		return entries[len(entries)-1], nil
+	}
+	return nil, nil
+}
+
+func (c *chains) List(context.Context, domain.TenantID, domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	return nil, nil
+}
+
+func (c *chains) Chain(_ context.Context, tenant domain.TenantID) ([]*domain.AuditEntry, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.entries[tenant], nil
+}
+
+var changes = []domain.FieldChange{{Field: "name"}}
+
+func TestRecorderChainsConcurrentAppends(t *testing.T) {
+	key := []byte("audit-key")
+	entries := newChains()
+	recorder := NewRecorder(entries, key)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := recorder.Record(context.Background(), "acme", "u-1", domain.AuditUserUpdated, changes); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	chain, _ := entries.Chain(context.Background(), "acme")
+	if len(chain) != 20 {
+		t.Fatalf("recorded %d entries, want 20", len(chain))
+	}
+	if brokenAt := domain.VerifyAuditChain(key, chain); brokenAt != 0 {
+		t.Fatalf("chain broken at %d", brokenAt)
+	}
+}
+
+func TestRecorderDoesNotSerializeTenants(t *testing.T) {
+	entries := newChains()
+	release := make(chan struct{})
+	entries.blocked["slow"] = release
+	recorder := NewRecorder(entries, []byte("audit-key"))
+
+	slowDone := make(chan error, 1)
+	go func() {
+		slowDone <- recorder.Record(context.Background(), "slow", "u-1", domain.AuditUserUpdated, changes)
+	}()
+
+	fastDone := make(chan error, 1)
+	go func() {
+		fastDone <- recorder.Record(context.Background(), "fast", "u-2", domain.AuditUserUpdated, changes)
+	}()
+
+	select {
+	case err := <-fastDone:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("an append in one tenant waited for another tenant")
+	}
+
+	close(release)
+	if err := <-slowDone; err != nil {
+		t.Fatal(err)
+	}
+}
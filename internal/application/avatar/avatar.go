@@ -0,0 +1,142 @@
+package avatar
+
+import (
+	"bytes"
+	"ddd-user-service/internal/domain"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"slices"
+	"strconv"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+const (
+	CodeTooLarge           domain.ErrorCode = "avatar_too_large"
+	CodeDimensionsTooLarge domain.ErrorCode = "avatar_dimensions_too_large"
+	CodeTypeUnsupported    domain.ErrorCode = "avatar_type_unsupported"
+	CodeInvalid            domain.ErrorCode = "avatar_invalid"
+)
+
+var (
+	ErrTypeUnsupported = domain.NewValidationError(CodeTypeUnsupported, "file", "avatar must be a JPEG, PNG or GIF image")
+	ErrInvalid         = domain.NewValidationError(CodeInvalid, "file", "avatar could not be read as an image")
+)
+
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+	ContentTypeGIF  = "image/gif"
+
+	jpegQuality = 85
+)
+
+var accepted = []string{ContentTypeJPEG, ContentTypePNG, ContentTypeGIF}
+
+type Options struct {
+	// MaxBytes limits the uploaded file.
+	MaxBytes int64
+	// MaxPixels limits the decoded image, so a small file cannot expand
+	// into a huge bitmap.
+	MaxPixels int
+	// Sizes are the edges, in pixels, of the square images generated.
+	Sizes []int
+}
+
+// Image is one generated size of an avatar.
+type Image struct {
+	Size        int
+	ContentType string
+	Data        []byte
+}
+
+// Processor turns an uploaded picture into square avatars. Images are
+// decoded and encoded afresh, so EXIF and any other metadata in the upload
+// is dropped; the EXIF orientation of a JPEG is applied first.
+type Processor struct {
+	opts Options
+}
+
+func NewProcessor(opts Options) *Processor {
+	opts.Sizes = slices.Clone(opts.Sizes)
+	slices.Sort(opts.Sizes)
+	opts.Sizes = slices.Compact(opts.Sizes)
+	return &Processor{opts: opts}
+}
+
+func (p *Processor) MaxBytes() int64 {
+	return p.opts.MaxBytes
+}
+
+// Process returns one image per configured size, largest first. The type is
+// decided by sniffing the content, never by the client's file name or
+// headers. JPEG uploads stay JPEG; anything else becomes PNG so
+// transparency survives.
+func (p *Processor) Process(data []byte) ([]Image, error) {
+	if int64(len(data)) > p.opts.MaxBytes {
+		return nil, domain.NewValidationError(CodeTooLarge, "file", "avatar must be at most "+strconv.FormatInt(p.opts.MaxBytes, 10)+" bytes").
+			WithParams(map[string]any{"max_bytes": p.opts.MaxBytes})
+	}
+
+	sniffed := mimetype.Detect(data)
+	if !slices.ContainsFunc(accepted, sniffed.Is) {
+		return nil, ErrTypeUnsupported.WithParams(map[string]any{"detected": sniffed.String()})
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width == 0 || cfg.Height == 0 {
+		return nil, ErrInvalid
+	}
+	if cfg.Width*cfg.Height > p.opts.MaxPixels {
+		return nil, domain.NewValidationError(CodeDimensionsTooLarge, "file", "avatar must be at most "+strconv.Itoa(p.opts.MaxPixels)+" pixels").
+			WithParams(map[string]any{"max_pixels": p.opts.MaxPixels})
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	src := toRGBA(decoded)
+	contentType := ContentTypePNG
+	if sniffed.Is(ContentTypeJPEG) {
+		contentType = ContentTypeJPEG
+		src = orient(src, jpegOrientation(data))
+	}
+	src = cropSquare(src)
+
+	images := make([]Image, 0, len(p.opts.Sizes))
+	for _, size := range slices.Backward(p.opts.Sizes) {
+		encoded, err := encode(resize(src, size), contentType)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, Image{Size: size, ContentType: contentType, Data: encoded})
+	}
+	return images, nil
+}
+
+// Extension returns the file extension for a content type Process produces.
+func Extension(contentType string) string {
+	if contentType == ContentTypeJPEG {
+		return ".jpg"
+	}
+	return ".png"
+}
+
+func encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == ContentTypeJPEG {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode avatar: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,205 @@
+package avatar
+
+import (
+	"bytes"
+	"ddd-user-service/internal/domain"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func encoded(t *testing.T, img image.Image, contentType string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var err error
+	switch contentType {
+	case ContentTypeJPEG:
+		err = jpeg.Encode(&buf, img, nil)
+	case ContentTypeGIF:
+		err = gif.Encode(&buf, img, nil)
+	default:
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withOrientation inserts an EXIF segment carrying the orientation tag
+// right after the JPEG start-of-image marker.
+func withOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+	return append(append(append([]byte(nil), data[:2]...), segment...), data[2:]...)
+}
+
+func TestProcess(t *testing.T) {
+	p := NewProcessor(Options{MaxBytes: 1 << 20, MaxPixels: 200 * 200, Sizes: []int{64, 16, 128, 64}})
+	red := color.RGBA{R: 255, A: 255}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		wantType string
+	}{
+		{"png", encoded(t, solid(90, 60, red), ContentTypePNG), ContentTypePNG},
+		{"gif becomes png", encoded(t, solid(60, 90, red), ContentTypeGIF), ContentTypePNG},
+		{"jpeg stays jpeg", encoded(t, solid(100, 100, red), ContentTypeJPEG), ContentTypeJPEG},
+		{"jpeg with exif", withOrientation(encoded(t, solid(120, 80, red), ContentTypeJPEG), 6), ContentTypeJPEG},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			images, err := p.Process(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sizes []int
+			for _, img := range images {
+				sizes = append(sizes, img.Size)
+				if img.ContentType != tt.wantType {
+					t.Fatalf("content type = %s, want %s", img.ContentType, tt.wantType)
+				}
+				if bytes.Contains(img.Data, []byte("Exif")) {
+					t.Fatal("EXIF metadata survived processing")
+				}
+				decoded, _, err := image.Decode(bytes.NewReader(img.Data))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if b := decoded.Bounds(); b.Dx() != img.Size || b.Dy() != img.Size {
+					t.Fatalf("image is %dx%d, want %d square", b.Dx(), b.Dy(), img.Size)
+				}
+				if r, g, b, _ := decoded.At(img.Size/2, img.Size/2).RGBA(); r>>8 < 240 || g>>8 > 15 || b>>8 > 15 {
+					t.Fatalf("centre pixel = %d,%d,%d, want red", r>>8, g>>8, b>>8)
+				}
+			}
+			if len(sizes) != 3 || sizes[0] != 128 || sizes[1] != 64 || sizes[2] != 16 {
+				t.Fatalf("sizes = %v, want 128, 64, 16", sizes)
+			}
+		})
+	}
+}
+
+func TestProcessRejects(t *testing.T) {
+	p := NewProcessor(Options{MaxBytes: 4096, MaxPixels: 50 * 50, Sizes: []int{16}})
+	small := encoded(t, solid(10, 10, color.Black), ContentTypePNG)
+
+	tests := []struct {
+		name string
+		data []byte
+		code domain.ErrorCode
+	}{
+		{"too many bytes", make([]byte, 4097), CodeTooLarge},
+		{"svg", []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"), CodeTypeUnsupported},
+		{"truncated png", small[:len(small)/2], CodeInvalid},
+		{"too many pixels", encoded(t, solid(51, 50, color.Black), ContentTypePNG), CodeDimensionsTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.Process(tt.data)
+			var domainErr *domain.Error
+			if !errors.As(err, &domainErr) || domainErr.Code != tt.code {
+				t.Fatalf("err = %v, want %s", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 3×2 image whose pixels are numbered in reading order.
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for i := range 6 {
+		src.Pix[i*4] = uint8(i + 1)
+	}
+	read := func(img *image.RGBA) []uint8 {
+		var out []uint8
+		for i := 0; i < len(img.Pix); i += 4 {
+			out = append(out, img.Pix[i])
+		}
+		return out
+	}
+
+	tests := []struct {
+		orientation int
+		w, h        int
+		want        []uint8
+	}{
+		{1, 3, 2, []uint8{1, 2, 3, 4, 5, 6}},
+		{2, 3, 2, []uint8{3, 2, 1, 6, 5, 4}},
+		{3, 3, 2, []uint8{6, 5, 4, 3, 2, 1}},
+		{4, 3, 2, []uint8{4, 5, 6, 1, 2, 3}},
+		{5, 2, 3, []uint8{1, 4, 2, 5, 3, 6}},
+		{6, 2, 3, []uint8{4, 1, 5, 2, 6, 3}},
+		{7, 2, 3, []uint8{6, 3, 5, 2, 4, 1}},
+		{8, 2, 3, []uint8{3, 6, 2, 5, 1, 4}},
+		{9, 3, 2, []uint8{1, 2, 3, 4, 5, 6}},
+	}
+	for _, tt := range tests {
+		got := orient(src, tt.orientation)
+		if b := got.Bounds(); b.Dx() != tt.w || b.Dy() != tt.h {
+			t.Fatalf("orientation %d: %dx%d, want %dx%d", tt.orientation, b.Dx(), b.Dy(), tt.w, tt.h)
+		}
+		if pixels := read(got); !bytes.Equal(pixels, tt.want) {
+			t.Fatalf("orientation %d: pixels %v, want %v", tt.orientation, pixels, tt.want)
+		}
+	}
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	plain := encoded(t, solid(4, 4, color.White), ContentTypeJPEG)
+	for _, tt := range []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"tagged", withOrientation(plain, 8), 8},
+		{"untagged", plain, 1},
+		{"not a jpeg", []byte("GIF89a"), 1},
+		{"truncated segment", withOrientation(plain, 6)[:12], 1},
+	} {
+		if got := jpegOrientation(tt.data); got != tt.want {
+			t.Errorf("%s: orientation = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResizeKeepsColour(t *testing.T) {
+	grey := color.RGBA{R: 120, G: 120, B: 120, A: 255}
+	for _, size := range []int{7, 10, 33} {
+		got := resize(cropSquare(solid(10, 14, grey)), size)
+		if b := got.Bounds(); b.Dx() != size || b.Dy() != size {
+			t.Fatalf("resized to %dx%d, want %d", b.Dx(), b.Dy(), size)
+		}
+		for i := 0; i < len(got.Pix); i += 4 {
+			if got.Pix[i] != 120 || got.Pix[i+3] != 255 {
+				t.Fatalf("size %d: pixel %v, want the source colour", size, got.Pix[i:i+4])
+			}
+		}
+	}
+}
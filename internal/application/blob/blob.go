@@ -0,0 +1,25 @@
+package blob
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+type Object struct {
+	ContentType string
+	Data        []byte
+}
+
+// Store keeps opaque binary objects, such as images, by key. Keys are
+// slash-separated paths built by the application, never taken verbatim from
+// a request.
+type Store interface {
+	// Put replaces any object stored under the key.
+	Put(ctx context.Context, key string, obj Object) error
+	// Get returns ErrNotFound for an unknown key.
+	Get(ctx context.Context, key string) (Object, error)
+	// Delete succeeds for an unknown key.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// CreateAttributeRequest defines a custom attribute. Pattern and Enum apply
+// to string attributes only.
+type CreateAttributeRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Type     string   `json:"type" binding:"required"`
+	Required bool     `json:"required"`
+	Unique   bool     `json:"unique"`
+	Pattern  string   `json:"pattern,omitempty"`
+	Enum     []string `json:"enum,omitempty"`
+}
+
+type AttributeResponse struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Required  bool      `json:"required"`
+	Unique    bool      `json:"unique"`
+	Pattern   string    `json:"pattern,omitempty"`
+	Enum      []string  `json:"enum,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AttributeListResponse struct {
+	Attributes []AttributeResponse `json:"attributes"`
+}
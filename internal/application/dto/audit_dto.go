@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditQuery holds the query string of the audit list as given: UserID
+// narrows it to one user, Limit caps the page and Before pages back from a
+// sequence.
+type AuditQuery struct {
+	UserID string
+	Limit  string
+	Before string
+}
+
+type AuditActorResponse struct {
+	UserID    string `json:"user_id,omitempty"`
+	APIKeyID  string `json:"api_key_id,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	IP        string `json:"ip,omitempty"`
+}
+
+// FieldChangeResponse has null Before for a field that was set and null
+// After for one that was cleared.
+type FieldChangeResponse struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+type AuditEntryResponse struct {
+	ID         string                `json:"id"`
+	Sequence   int64                 `json:"sequence"`
+	UserID     string                `json:"user_id"`
+	Action     string                `json:"action"`
+	Actor      AuditActorResponse    `json:"actor"`
+	Changes    []FieldChangeResponse `json:"changes"`
+	OccurredAt time.Time             `json:"occurred_at"`
+	PrevHash   string                `json:"prev_hash,omitempty"`
+	Hash       string                `json:"hash"`
+}
+
+type AuditListResponse struct {
+	Entries []AuditEntryResponse `json:"entries"`
+}
+
+// AuditVerifyResponse reports BrokenAt, the first sequence that fails to
+// verify, when Valid is false.
+type AuditVerifyResponse struct {
+	Valid    bool   `json:"valid"`
+	Entries  int    `json:"entries"`
+	LastHash string `json:"last_hash,omitempty"`
+	BrokenAt int64  `json:"broken_at,omitempty"`
+}
@@ -0,0 +1,96 @@
+package dto
+
+import "time"
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type LoginRequest struct {
+	// Login is an email address or a username.
+	Login    string `json:"login" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// LoginMFARequest completes a login with either a TOTP code or a recovery
+// code.
+type LoginMFARequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recovery_code,omitempty"`
+}
+
+const (
+	LoginStatusAuthenticated         = "authenticated"
+	LoginStatusMFARequired           = "mfa_required"
+	LoginStatusMFAEnrollmentRequired = "mfa_enrollment_required"
+)
+
+type LoginResponse struct {
+	Status      string     `json:"status"`
+	AccessToken string     `json:"access_token,omitempty"`
+	TokenType   string     `json:"token_type,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	MFAToken    string     `json:"mfa_token,omitempty"`
+	MFAMethods  []string   `json:"mfa_methods,omitempty"`
+}
+
+const (
+	ScopeFull = "full"
+	// ScopeMFAEnrollment only allows enrolling a second factor; it is issued
+	// to users whose role requires MFA before they have one.
+	ScopeMFAEnrollment = "mfa_enrollment"
+	// ScopeAPIKey is the scope of callers using an API key; what they may do
+	// is limited further by APIKeyScopes.
+	ScopeAPIKey = "api_key"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	UserID string
+	// TenantID is the tenant the credential belongs to. Requests made with
+	// it are pinned to that tenant.
+	TenantID     string
+	Role         string
+	Scope        string
+	SessionID    string
+	APIKeyID     string
+	APIKeyScopes []string
+}
+
+// ClientInfo describes the device a request came from, for the session
+// list.
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	IPAddress  string    `json:"ip_address,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Current marks the session the request was made with.
+	Current bool `json:"current"`
+}
+
+type TOTPEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
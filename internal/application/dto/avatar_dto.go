@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+type AvatarResponse struct {
+	ID string `json:"id"`
+	// URL is the largest image, also set as the profile's avatar_url.
+	URL         string                `json:"url"`
+	ContentType string                `json:"content_type"`
+	Images      []AvatarImageResponse `json:"images"`
+	UploadedAt  time.Time             `json:"uploaded_at"`
+}
+
+type AvatarImageResponse struct {
+	Size int    `json:"size"`
+	URL  string `json:"url"`
+}
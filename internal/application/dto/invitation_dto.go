@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+// CreateInvitationRequest invites an email address. Role is the service role
+// for the account; OrganizationID optionally adds the invitee to an
+// organization with OrganizationRole, "member" by default.
+type CreateInvitationRequest struct {
+	Email            string `json:"email" binding:"required"`
+	Role             string `json:"role,omitempty"`
+	OrganizationID   string `json:"organization_id,omitempty" binding:"required_with=OrganizationRole"`
+	OrganizationRole string `json:"organization_role,omitempty"`
+}
+
+// AcceptInvitationRequest accepts an invitation. Name, Username and
+// Password are only used, and then required, when a new account is created,
+// as are Attributes, which are checked against the tenant's schemas.
+type AcceptInvitationRequest struct {
+	Token      string         `json:"token" binding:"required"`
+	Name       string         `json:"name"`
+	Username   string         `json:"username"`
+	Password   string         `json:"password"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// InvitationResponse never includes the token, which only goes to the
+// invitee's address. Status is "pending", "expired", "accepted" or
+// "revoked".
+type InvitationResponse struct {
+	ID               string     `json:"id"`
+	Email            string     `json:"email"`
+	Role             string     `json:"role,omitempty"`
+	OrganizationID   string     `json:"organization_id,omitempty"`
+	OrganizationRole string     `json:"organization_role,omitempty"`
+	Status           string     `json:"status"`
+	InvitedBy        string     `json:"invited_by,omitempty"`
+	UserID           string     `json:"user_id,omitempty"`
+	SendCount        int        `json:"send_count"`
+	CreatedAt        time.Time  `json:"created_at"`
+	SentAt           time.Time  `json:"sent_at"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	AcceptedAt       *time.Time `json:"accepted_at,omitempty"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+}
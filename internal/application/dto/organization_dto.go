@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// AddMemberRequest adds a member directly or, on the invitations endpoint,
+// invites them.
+type AddMemberRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required"`
+}
+
+type ChangeMemberRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type CreateTeamRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type OrganizationResponse struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Members   []*MemberResponse `json:"members"`
+	Teams     []*TeamResponse   `json:"teams"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// MemberResponse describes a membership. Status is "active" or "invited".
+type MemberResponse struct {
+	UserID    string     `json:"user_id"`
+	Role      string     `json:"role"`
+	Status    string     `json:"status"`
+	InvitedBy string     `json:"invited_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	JoinedAt  *time.Time `json:"joined_at,omitempty"`
+}
+
+type TeamResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Members   []string  `json:"members"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserOrganizationResponse is one of a user's organizations, with the user's
+// membership in it.
+type UserOrganizationResponse struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	Status string `json:"status"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// AddPolicyEntryRequest adds a value to a policy list. Usernames and words
+// are normalized like usernames; domains are stored in their ASCII form.
+type AddPolicyEntryRequest struct {
+	Value string `json:"value" binding:"required"`
+}
+
+// PolicyEntryResponse has Source "config" for entries loaded at start up,
+// which apply to every tenant, and "admin" for the tenant's own.
+type PolicyEntryResponse struct {
+	Value     string     `json:"value"`
+	Source    string     `json:"source"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type PolicyListResponse struct {
+	List    string                `json:"list"`
+	Entries []PolicyEntryResponse `json:"entries"`
+}
@@ -1,20 +1,107 @@
 package dto
 
+import "time"
+
+// CreateUserRequest carries no binding rules: the domain validates every
+// field and reports all failures together. Password is optional; Role is
+// only accepted from the admin API. Attributes are checked against the
+// tenant's attribute schemas.
 type CreateUserRequest struct {
-	Name     string `json:"name" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
-	Username string `json:"username" binding:"required,min=3"`
+	Name       string         `json:"name"`
+	Email      string         `json:"email"`
+	Username   string         `json:"username"`
+	Password   string         `json:"password,omitempty"`
+	Role       string         `json:"role,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// ReplaceUserRequest is the body of PUT: it replaces every field, so an
+// omitted field is treated as empty.
+type ReplaceUserRequest struct {
+	Name       string         `json:"name"`
+	Email      string         `json:"email"`
+	Username   string         `json:"username"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+// ListUsersQuery narrows the user list. Attributes match custom attribute
+// values, written as text. Users match when they carry any of Tags, or all
+// of them when TagMatch is "all".
+type ListUsersQuery struct {
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	TagMatch   string            `json:"tag_match,omitempty"`
+}
+
+// UpdateTagsRequest removes and then adds tags, so a tag in both lists ends
+// up set.
+type UpdateTagsRequest struct {
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+}
+
+// BulkTagRequest updates the tags of every user matching Filter, which must
+// name at least one tag or attribute.
+type BulkTagRequest struct {
+	Filter ListUsersQuery `json:"filter"`
+	UpdateTagsRequest
 }
 
-type UpdateUserRequest struct {
-	Name     *string `json:"name,omitempty"`
-	Email    *string `json:"email,omitempty"`
-	Username *string `json:"username,omitempty"`
+type BulkTagResponse struct {
+	Matched int `json:"matched"`
+	Updated int `json:"updated"`
+}
+
+// ProfileRequest is the body of PUT on the profile: it replaces every
+// field, so an omitted field is cleared.
+type ProfileRequest struct {
+	DisplayName string `json:"display_name"`
+	GivenName   string `json:"given_name"`
+	FamilyName  string `json:"family_name"`
+	Phone       string `json:"phone"`
+	Locale      string `json:"locale"`
+	Timezone    string `json:"timezone"`
+	Bio         string `json:"bio"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+// ProfileResponse includes empty fields so JSON Patch can replace them.
+type ProfileResponse struct {
+	DisplayName string `json:"display_name"`
+	GivenName   string `json:"given_name"`
+	FamilyName  string `json:"family_name"`
+	Phone       string `json:"phone"`
+	Locale      string `json:"locale"`
+	Timezone    string `json:"timezone"`
+	Bio         string `json:"bio"`
+	AvatarURL   string `json:"avatar_url"`
 }
 
 type UserResponse struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Username string `json:"username"`
+	ID              string     `json:"id"`
+	TenantID        string     `json:"tenant_id"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email"`
+	Username        string     `json:"username"`
+	EmailVerified   bool       `json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	PendingEmail    string     `json:"pending_email,omitempty"`
+	Role            string     `json:"role"`
+	MFAEnabled      bool       `json:"mfa_enabled"`
+	Suspended       bool       `json:"suspended"`
+	// Attributes is never omitted, so JSON Patch can add to it.
+	Attributes map[string]any `json:"attributes"`
+	// Tags is read-only here; it changes through the tags endpoints.
+	Tags []string `json:"tags"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// AcceptedResponse is returned with 202 by flows that deliberately do not
+// reveal their outcome.
+type AcceptedResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
 }
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type BeginWebAuthnRegistrationRequest struct {
+	// Name labels the authenticator in the credential list, e.g. "YubiKey".
+	Name string `json:"name"`
+}
+
+type BeginWebAuthnLoginRequest struct {
+	// Login optionally names the account, for authenticators that cannot
+	// store discoverable credentials.
+	Login string `json:"login,omitempty"`
+}
+
+// WebAuthnCeremonyResponse carries the options to pass to
+// navigator.credentials.create or .get, and the ID to finish with.
+type WebAuthnCeremonyResponse struct {
+	CeremonyID string `json:"ceremony_id"`
+	Options    any    `json:"options"`
+}
+
+// FinishWebAuthnRequest carries the PublicKeyCredential returned by the
+// browser, serialised as JSON with binary fields base64url encoded.
+type FinishWebAuthnRequest struct {
+	CeremonyID string          `json:"ceremony_id" binding:"required"`
+	Credential json.RawMessage `json:"credential" binding:"required"`
+}
+
+type WebAuthnCredentialResponse struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	AttestationType string     `json:"attestation_type"`
+	Transports      []string   `json:"transports,omitempty"`
+	BackupEligible  bool       `json:"backup_eligible"`
+	CreatedAt       time.Time  `json:"created_at"`
+	LastUsedAt      *time.Time `json:"last_used_at,omitempty"`
+}
+
+type RenameWebAuthnCredentialRequest struct {
+	Name string `json:"name" binding:"required"`
+}
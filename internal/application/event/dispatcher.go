@@ -0,0 +1,53 @@
+package event
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/logging"
+	"log/slog"
+	"sync"
+)
+
+type Handler func(ctx context.Context, event domain.Event) error
+
+type Publisher interface {
+	Publish(ctx context.Context, events ...domain.Event)
+}
+
+// Dispatcher delivers events to in-process subscribers synchronously, in
+// subscription order. A failing handler is logged and does not stop the
+// others: the change that raised the event is already stored.
+type Dispatcher struct {
+	handlers map[string][]Handler
+	mutex    sync.RWMutex
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		handlers: make(map[string][]Handler),
+	}
+}
+
+func (d *Dispatcher) Subscribe(name string, handler Handler) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.handlers[name] = append(d.handlers[name], handler)
+}
+
+func (d *Dispatcher) Publish(ctx context.Context, events ...domain.Event) {
+	logger := logging.FromContext(ctx).With(slog.String(logging.KeyComponent, "events"))
+
+	for _, e := range events {
+		d.mutex.RLock()
+		handlers := d.handlers[e.EventName()]
+		d.mutex.RUnlock()
+
+		logger.Info("event published", slog.String("event", e.EventName()), slog.Int("handlers", len(handlers)))
+		for _, handler := range handlers {
+			if err := handler(ctx, e); err != nil {
+				logger.Error("event handler failed", slog.String("event", e.EventName()), logging.KeyError, err.Error())
+			}
+		}
+	}
+}
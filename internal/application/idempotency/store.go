@@ -0,0 +1,38 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+var ErrNotFound = errors.New("idempotency record not found")
+
+// Record is the stored outcome of a request made with an Idempotency-Key.
+// A record without a StatusCode is still in progress.
+type Record struct {
+	Key         string
+	Fingerprint string
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+func (r *Record) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// Store persists idempotency records. Reserve must be atomic: exactly one
+// caller may create a record for a key.
+type Store interface {
+	// Reserve creates an in-progress record for rec.Key. If a live record
+	// already exists it is returned and nothing is written.
+	Reserve(ctx context.Context, rec *Record) (existing *Record, err error)
+	// Complete stores the response for a previously reserved key.
+	Complete(ctx context.Context, key string, statusCode int, header http.Header, body []byte) error
+	// Release removes a reservation so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
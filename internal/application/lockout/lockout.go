@@ -0,0 +1,168 @@
+package lockout
+
+import (
+	"context"
+	"time"
+)
+
+// Attempts is the failed-login state kept for one key.
+type Attempts struct {
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   time.Time
+}
+
+// Store keeps failed-attempt counters by key. Implementations shared by
+// several instances must apply RecordFailure atomically.
+type Store interface {
+	// Get returns the zero Attempts for an unknown key.
+	Get(ctx context.Context, key string) (Attempts, error)
+	// RecordFailure counts a failure at the given time. Failures are
+	// forgotten once window has passed since the previous one.
+	RecordFailure(ctx context.Context, key string, at time.Time, window time.Duration) (Attempts, error)
+	// Lock blocks the key until the given time and clears its failures.
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+// Policy locks a key for Duration once Threshold failures happen with no
+// more than Window between them. Before that, each failure after the first
+// makes the caller wait BaseDelay, doubling up to MaxDelay.
+type Policy struct {
+	Threshold int
+	Window    time.Duration
+	Duration  time.Duration
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+func (p Policy) Enabled() bool {
+	return p.Threshold > 0
+}
+
+func (p Policy) delay(failures int) time.Duration {
+	if failures < 2 || p.BaseDelay <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := 2; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+type Reason string
+
+const (
+	ReasonNone Reason = ""
+	// ReasonLocked means the threshold was reached.
+	ReasonLocked Reason = "locked"
+	// ReasonThrottled means the progressive delay has not passed yet.
+	ReasonThrottled Reason = "throttled"
+)
+
+type Status struct {
+	Reason     Reason
+	RetryAfter time.Duration
+}
+
+func (s Status) Blocked() bool {
+	return s.Reason != ReasonNone
+}
+
+// Guard applies one policy to accounts and another to client IP addresses.
+// Account keys get progressive delays; IP keys are only locked, so that
+// users behind a shared address are not slowed down by each other.
+type Guard struct {
+	store   Store
+	account Policy
+	ip      Policy
+	now     func() time.Time
+}
+
+func NewGuard(store Store, account, ip Policy) *Guard {
+	return &Guard{
+		store:   store,
+		account: account,
+		ip:      ip,
+		now:     time.Now,
+	}
+}
+
+func accountKey(account string) string {
+	return "account:" + account
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check reports whether a login for account from ip may be attempted now.
+func (g *Guard) Check(ctx context.Context, account, ip string) (Status, error) {
+	now := g.now()
+
+	if g.ip.Enabled() && ip != "" {
+		attempts, err := g.store.Get(ctx, ipKey(ip))
+		if err != nil {
+			return Status{}, err
+		}
+		if now.Before(attempts.LockedUntil) {
+			return Status{Reason: ReasonLocked, RetryAfter: attempts.LockedUntil.Sub(now)}, nil
+		}
+	}
+
+	if !g.account.Enabled() {
+		return Status{}, nil
+	}
+	attempts, err := g.store.Get(ctx, accountKey(account))
+	if err != nil {
+		return Status{}, err
+	}
+	if now.Before(attempts.LockedUntil) {
+		return Status{Reason: ReasonLocked, RetryAfter: attempts.LockedUntil.Sub(now)}, nil
+	}
+	if now.Sub(attempts.LastFailureAt) < g.account.Window {
+		if next := attempts.LastFailureAt.Add(g.account.delay(attempts.Failures)); now.Before(next) {
+			return Status{Reason: ReasonThrottled, RetryAfter: next.Sub(now)}, nil
+		}
+	}
+	return Status{}, nil
+}
+
+// RecordFailure counts a failed login and reports whether it locked the
+// account.
+func (g *Guard) RecordFailure(ctx context.Context, account, ip string) (lockedUntil *time.Time, err error) {
+	now := g.now()
+
+	if g.ip.Enabled() && ip != "" {
+		if _, err := g.record(ctx, ipKey(ip), g.ip, now); err != nil {
+			return nil, err
+		}
+	}
+	if g.account.Enabled() {
+		return g.record(ctx, accountKey(account), g.account, now)
+	}
+	return nil, nil
+}
+
+func (g *Guard) record(ctx context.Context, key string, policy Policy, now time.Time) (*time.Time, error) {
+	attempts, err := g.store.RecordFailure(ctx, key, now, policy.Window)
+	if err != nil {
+		return nil, err
+	}
+	if attempts.Failures < policy.Threshold {
+		return nil, nil
+	}
+
+	until := now.Add(policy.Duration)
+	if err := g.store.Lock(ctx, key, until); err != nil {
+		return nil, err
+	}
+	return &until, nil
+}
+
+// Reset clears an account's failures, after a successful login or when an
+// administrator unlocks it.
+func (g *Guard) Reset(ctx context.Context, account string) error {
+	return g.store.Reset(ctx, accountKey(account))
+}
@@ -0,0 +1,45 @@
+package mail
+
+import (
+	"context"
+	"errors"
+)
+
+// Message is an outbound email. Either Template is set and the message is
+// rendered from it, or Subject, Text and optionally HTML are given directly.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+
+	Template string
+	Locale   string
+	Data     map[string]any
+}
+
+// Mailer delivers outbound email.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// PermanentError marks a delivery failure that will not succeed on retry,
+// such as a hard bounce for an unknown mailbox.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return "permanent delivery failure: " + e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+var ErrSuppressed = errors.New("recipient is suppressed")
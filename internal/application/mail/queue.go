@@ -0,0 +1,180 @@
+package mail
+
+import (
+	"context"
+	"ddd-user-service/internal/logging"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrQueueClosed = errors.New("mail queue is closed")
+
+type QueueOptions struct {
+	Workers        int
+	BufferSize     int
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+type job struct {
+	ctx context.Context
+	msg Message
+}
+
+// Queue delivers messages in the background through a transport, retrying
+// transient failures with exponential backoff. Recipients on the
+// suppression list are skipped, and permanent failures add the recipient to
+// it.
+type Queue struct {
+	transport    Mailer
+	suppressions SuppressionList
+	opts         QueueOptions
+	jobs         chan job
+	wg           sync.WaitGroup
+	closeOnce    sync.Once
+	mutex        sync.RWMutex
+	closed       bool
+	sleep        func(ctx context.Context, d time.Duration) error
+	now          func() time.Time
+}
+
+func NewQueue(transport Mailer, suppressions SuppressionList, opts QueueOptions) *Queue {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 1
+	}
+
+	q := &Queue{
+		transport:    transport,
+		suppressions: suppressions,
+		opts:         opts,
+		jobs:         make(chan job, opts.BufferSize),
+		sleep:        sleepContext,
+		now:          time.Now,
+	}
+
+	for i := 0; i < opts.Workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+
+	return q
+}
+
+// Send enqueues msg. It blocks while the buffer is full and gives up when
+// ctx is done. Delivery outlives the caller's context, but keeps its values
+// such as the request logger.
+func (q *Queue) Send(ctx context.Context, msg Message) error {
+	suppressed, err := q.suppressions.IsSuppressed(ctx, msg.To)
+	if err != nil {
+		return err
+	}
+	if suppressed {
+		return ErrSuppressed
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.jobs <- job{ctx: context.WithoutCancel(ctx), msg: msg}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting messages and waits for queued ones to be delivered
+// or for ctx to expire.
+func (q *Queue) Close(ctx context.Context) error {
+	q.closeOnce.Do(func() {
+		q.mutex.Lock()
+		q.closed = true
+		close(q.jobs)
+		q.mutex.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for j := range q.jobs {
+		q.deliver(j.ctx, j.msg)
+	}
+}
+
+func (q *Queue) deliver(ctx context.Context, msg Message) {
+	logger := logging.FromContext(ctx).With(slog.String(logging.KeyComponent, "mail"))
+	backoff := q.opts.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		suppressed, err := q.suppressions.IsSuppressed(ctx, msg.To)
+		if err == nil && suppressed {
+			logger.Info("mail skipped for suppressed recipient", slog.String("email", msg.To))
+			return
+		}
+
+		err = q.transport.Send(ctx, msg)
+		if err == nil {
+			return
+		}
+
+		if IsPermanent(err) {
+			logger.Warn("mail bounced, suppressing recipient",
+				slog.String("email", msg.To), slog.String(logging.KeyError, err.Error()))
+			suppression := Suppression{Email: strings.ToLower(msg.To), Reason: err.Error(), CreatedAt: q.now()}
+			if err := q.suppressions.Suppress(ctx, suppression); err != nil {
+				logger.Error("failed to suppress recipient", logging.KeyError, err.Error())
+			}
+			return
+		}
+
+		if attempt >= q.opts.MaxAttempts {
+			logger.Error("mail delivery failed",
+				slog.String("email", msg.To), slog.Int("attempts", attempt), slog.String(logging.KeyError, err.Error()))
+			return
+		}
+
+		logger.Warn("mail delivery failed, retrying",
+			slog.Int("attempt", attempt), slog.Duration("backoff", backoff), slog.String(logging.KeyError, err.Error()))
+		if err := q.sleep(ctx, backoff); err != nil {
+			return
+		}
+		backoff *= 2
+		if q.opts.MaxBackoff > 0 && backoff > q.opts.MaxBackoff {
+			backoff = q.opts.MaxBackoff
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
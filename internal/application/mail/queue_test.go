@@ -0,0 +1,158 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scriptedTransport fails with the scripted errors in turn, then succeeds.
+type scriptedTransport struct {
+	mutex    sync.Mutex
+	errs     []error
+	attempts int
+	sent     []Message
+}
+
+func (t *scriptedTransport) Send(_ context.Context, msg Message) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.attempts++
+	if len(t.errs) > 0 {
+		err := t.errs[0]
+		t.errs = t.errs[1:]
+		return err
+	}
+	t.sent = append(t.sent, msg)
+	return nil
+}
+
+type memorySuppressions struct {
+	mutex      sync.Mutex
+	suppressed map[string]Suppression
+}
+
+func (s *memorySuppressions) IsSuppressed(_ context.Context, email string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.suppressed[email]
+	return ok, nil
+}
+
+func (s *memorySuppressions) Suppress(_ context.Context, suppression Suppression) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.suppressed[suppression.Email] = suppression
+	return nil
+}
+
+func TestQueueRetriesWithBackoff(t *testing.T) {
+	transient := errors.New("connection reset")
+	tests := []struct {
+		name         string
+		errs         []error
+		wantAttempts int
+		wantSent     bool
+		wantBackoffs []time.Duration
+		wantBlocked  bool
+	}{
+		{
+			name:         "delivered first time",
+			wantAttempts: 1,
+			wantSent:     true,
+		},
+		{
+			name:         "delivered after transient failures",
+			errs:         []error{transient, transient},
+			wantAttempts: 3,
+			wantSent:     true,
+			wantBackoffs: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:         "backoff is capped and attempts are bounded",
+			errs:         []error{transient, transient, transient, transient, transient},
+			wantAttempts: 4,
+			wantBackoffs: []time.Duration{time.Second, 2 * time.Second, 3 * time.Second},
+		},
+		{
+			name:         "permanent failure suppresses without retrying",
+			errs:         []error{&PermanentError{Err: errors.New("550 no such user")}},
+			wantAttempts: 1,
+			wantBlocked:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &scriptedTransport{errs: tt.errs}
+			suppressions := &memorySuppressions{suppressed: make(map[string]Suppression)}
+			q := NewQueue(transport, suppressions, QueueOptions{
+				MaxAttempts:    4,
+				InitialBackoff: time.Second,
+				MaxBackoff:     3 * time.Second,
+			})
+			var backoffs []time.Duration
+			q.sleep = func(_ context.Context, d time.Duration) error {
+				backoffs = append(backoffs, d)
+				return nil
+			}
+
+			if err := q.Send(context.Background(), Message{To: "Jane@example.com", Subject: "Hi"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := q.Close(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if transport.attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", transport.attempts, tt.wantAttempts)
+			}
+			if sent := len(transport.sent) == 1; sent != tt.wantSent {
+				t.Errorf("sent = %v, want %v", sent, tt.wantSent)
+			}
+			if !slices.Equal(backoffs, tt.wantBackoffs) {
+				t.Errorf("backoffs = %v, want %v", backoffs, tt.wantBackoffs)
+			}
+			if blocked, _ := suppressions.IsSuppressed(context.Background(), "jane@example.com"); blocked != tt.wantBlocked {
+				t.Errorf("suppressed = %v, want %v", blocked, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestQueueStopsRetryingWhenSleepIsCancelled(t *testing.T) {
+	transport := &scriptedTransport{errs: []error{errors.New("timeout"), errors.New("timeout")}}
+	q := NewQueue(transport, &memorySuppressions{suppressed: make(map[string]Suppression)}, QueueOptions{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+	})
+	q.sleep = func(context.Context, time.Duration) error { return context.Canceled }
+
+	if err := q.Send(context.Background(), Message{To: "jane@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if transport.attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", transport.attempts)
+	}
+}
+
+func TestQueueRefusesSuppressedAndClosed(t *testing.T) {
+	suppressions := &memorySuppressions{suppressed: map[string]Suppression{"gone@example.com": {}}}
+	q := NewQueue(&scriptedTransport{}, suppressions, QueueOptions{})
+
+	if err := q.Send(context.Background(), Message{To: "gone@example.com"}); !errors.Is(err, ErrSuppressed) {
+		t.Fatalf("suppressed recipient: err = %v, want ErrSuppressed", err)
+	}
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Send(context.Background(), Message{To: "jane@example.com"}); !errors.Is(err, ErrQueueClosed) {
+		t.Fatalf("closed queue: err = %v, want ErrQueueClosed", err)
+	}
+}
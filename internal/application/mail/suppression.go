@@ -0,0 +1,19 @@
+package mail
+
+import (
+	"context"
+	"time"
+)
+
+type Suppression struct {
+	Email     string
+	Reason    string
+	CreatedAt time.Time
+}
+
+// SuppressionList holds addresses that must not be mailed again, typically
+// after a hard bounce.
+type SuppressionList interface {
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+	Suppress(ctx context.Context, suppression Suppression) error
+}
@@ -0,0 +1,122 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+)
+
+// TemplateRenderer renders messages from a file system laid out as
+// <locale>/<template>.subject.txt, <locale>/<template>.txt and the optional
+// <locale>/<template>.html. Missing locales fall back to the base language
+// ("pt-BR" to "pt") and then to the default locale.
+type TemplateRenderer struct {
+	fsys          fs.FS
+	defaultLocale string
+}
+
+func NewTemplateRenderer(fsys fs.FS, defaultLocale string) *TemplateRenderer {
+	return &TemplateRenderer{
+		fsys:          fsys,
+		defaultLocale: defaultLocale,
+	}
+}
+
+func (r *TemplateRenderer) Render(msg Message) (Message, error) {
+	if msg.Template == "" {
+		return msg, nil
+	}
+
+	locale := r.resolveLocale(msg.Template, msg.Locale)
+	base := locale + "/" + msg.Template
+
+	subject, err := r.renderText(base+".subject.txt", msg.Data)
+	if err != nil {
+		return Message{}, err
+	}
+	text, err := r.renderText(base+".txt", msg.Data)
+	if err != nil {
+		return Message{}, err
+	}
+	html, err := r.renderHTML(base+".html", msg.Data)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Message{}, err
+	}
+
+	msg.Subject = strings.TrimSpace(subject)
+	msg.Text = text
+	msg.HTML = html
+	msg.Locale = locale
+	return msg, nil
+}
+
+func (r *TemplateRenderer) resolveLocale(name, locale string) string {
+	candidates := []string{}
+	if locale != "" {
+		candidates = append(candidates, locale)
+		if lang, _, ok := strings.Cut(locale, "-"); ok {
+			candidates = append(candidates, lang)
+		}
+	}
+
+	for _, candidate := range candidates {
+		if _, err := fs.Stat(r.fsys, candidate+"/"+name+".txt"); err == nil {
+			return candidate
+		}
+	}
+	return r.defaultLocale
+}
+
+func (r *TemplateRenderer) renderText(path string, data any) (string, error) {
+	tmpl, err := texttemplate.ParseFS(r.fsys, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse mail template %s: %w", path, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render mail template %s: %w", path, err)
+	}
+	return buf.String(), nil
+}
+
+func (r *TemplateRenderer) renderHTML(path string, data any) (string, error) {
+	if _, err := fs.Stat(r.fsys, path); err != nil {
+		return "", err
+	}
+	tmpl, err := htmltemplate.ParseFS(r.fsys, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse mail template %s: %w", path, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render mail template %s: %w", path, err)
+	}
+	return buf.String(), nil
+}
+
+// TemplatingMailer renders templated messages before handing them on, so
+// template errors surface to the caller instead of inside the send queue.
+type TemplatingMailer struct {
+	renderer *TemplateRenderer
+	next     Mailer
+}
+
+func NewTemplatingMailer(renderer *TemplateRenderer, next Mailer) *TemplatingMailer {
+	return &TemplatingMailer{
+		renderer: renderer,
+		next:     next,
+	}
+}
+
+func (m *TemplatingMailer) Send(ctx context.Context, msg Message) error {
+	rendered, err := m.renderer.Render(msg)
+	if err != nil {
+		return err
+	}
+	return m.next.Send(ctx, rendered)
+}
@@ -0,0 +1,113 @@
+package mail
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var testTemplates = fstest.MapFS{
+	"en/welcome.subject.txt": {Data: []byte("Welcome, {{.Name}}\n")},
+	"en/welcome.txt":         {Data: []byte("Hello {{.Name}}")},
+	"en/welcome.html":        {Data: []byte("<p>Hello {{.Name}}</p>")},
+	"pt/welcome.subject.txt": {Data: []byte("Bem-vindo, {{.Name}}")},
+	"pt/welcome.txt":         {Data: []byte("Olá {{.Name}}")},
+	"en/plain.subject.txt":   {Data: []byte("Plain")},
+	"en/plain.txt":           {Data: []byte("Text only")},
+	"en/broken.subject.txt":  {Data: []byte("{{.Name")},
+	"en/broken.txt":          {Data: []byte("never rendered")},
+}
+
+func TestTemplatingMailerRendersBeforeSending(t *testing.T) {
+	tests := []struct {
+		name       string
+		msg        Message
+		wantLocale string
+		wantSubj   string
+		wantText   string
+		wantHTML   string
+	}{
+		{
+			name:       "default locale",
+			msg:        Message{Template: "welcome", Data: map[string]any{"Name": "Jane"}},
+			wantLocale: "en",
+			wantSubj:   "Welcome, Jane",
+			wantText:   "Hello Jane",
+			wantHTML:   "<p>Hello Jane</p>",
+		},
+		{
+			name:       "regional locale falls back to the language",
+			msg:        Message{Template: "welcome", Locale: "pt-BR", Data: map[string]any{"Name": "Ana"}},
+			wantLocale: "pt",
+			wantSubj:   "Bem-vindo, Ana",
+			wantText:   "Olá Ana",
+		},
+		{
+			name:       "unknown locale falls back to the default",
+			msg:        Message{Template: "welcome", Locale: "de", Data: map[string]any{"Name": "Jo"}},
+			wantLocale: "en",
+			wantSubj:   "Welcome, Jo",
+			wantText:   "Hello Jo",
+			wantHTML:   "<p>Hello Jo</p>",
+		},
+		{
+			name:       "HTML is escaped",
+			msg:        Message{Template: "welcome", Data: map[string]any{"Name": "<b>Eve</b>"}},
+			wantLocale: "en",
+			wantSubj:   "Welcome, <b>Eve</b>",
+			wantText:   "Hello <b>Eve</b>",
+			wantHTML:   "<p>Hello &lt;b&gt;Eve&lt;/b&gt;</p>",
+		},
+		{
+			name:       "HTML part is optional",
+			msg:        Message{Template: "plain"},
+			wantLocale: "en",
+			wantSubj:   "Plain",
+			wantText:   "Text only",
+		},
+		{
+			name:     "untemplated messages pass through",
+			msg:      Message{Subject: "Raw", Text: "As is"},
+			wantSubj: "Raw",
+			wantText: "As is",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &scriptedTransport{}
+			mailer := NewTemplatingMailer(NewTemplateRenderer(testTemplates, "en"), transport)
+
+			tt.msg.To = "jane@example.com"
+			if err := mailer.Send(context.Background(), tt.msg); err != nil {
+				t.Fatal(err)
+			}
+			if len(transport.sent) != 1 {
+				t.Fatalf("sent %d messages, want 1", len(transport.sent))
+			}
+			got := transport.sent[0]
+			if got.To != "jane@example.com" || got.Locale != tt.wantLocale || got.Subject != tt.wantSubj ||
+				got.Text != tt.wantText || got.HTML != tt.wantHTML {
+				t.Fatalf("sent %+v", got)
+			}
+		})
+	}
+}
+
+func TestTemplatingMailerReportsTemplateErrors(t *testing.T) {
+	for _, name := range []string{"broken", "missing"} {
+		t.Run(name, func(t *testing.T) {
+			transport := &scriptedTransport{}
+			mailer := NewTemplatingMailer(NewTemplateRenderer(testTemplates, "en"), transport)
+
+			err := mailer.Send(context.Background(), Message{To: "jane@example.com", Template: name})
+			if err == nil || !strings.Contains(err.Error(), "mail template") {
+				t.Fatalf("err = %v, want a template error", err)
+			}
+			if transport.attempts != 0 {
+				t.Fatalf("transport called %d times, want 0", transport.attempts)
+			}
+		})
+	}
+}
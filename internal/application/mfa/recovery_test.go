@@ -0,0 +1,41 @@
+package mfa
+
+import (
+	"ddd-user-service/internal/domain"
+	"strings"
+	"testing"
+)
+
+func TestRecoveryCodesAreSingleUse(t *testing.T) {
+	codes, hashes, err := GenerateRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != RecoveryCodeCount || len(hashes) != RecoveryCodeCount {
+		t.Fatalf("got %d codes and %d hashes, want %d", len(codes), len(hashes), RecoveryCodeCount)
+	}
+
+	user := &domain.User{RecoveryCodes: hashes}
+	tests := []struct {
+		name  string
+		typed string
+		want  bool
+	}{
+		{"as shown", codes[0], true},
+		{"used again", codes[0], false},
+		{"typed in upper case without the dash", strings.ToUpper(strings.ReplaceAll(codes[1], "-", "")), true},
+		{"typed with spaces", strings.ReplaceAll(codes[2], "-", " "), true},
+		{"unknown code", "aaaaa-aaaaa", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := user.UseRecoveryCode(HashRecoveryCode(tt.typed)); got != tt.want {
+				t.Fatalf("UseRecoveryCode = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if left := len(user.RecoveryCodes); left != RecoveryCodeCount-3 {
+		t.Fatalf("%d recovery codes left, want %d", left, RecoveryCodeCount-3)
+	}
+}
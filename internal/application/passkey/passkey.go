@@ -0,0 +1,129 @@
+// Package passkey adapts the go-webauthn relying party to the user
+// aggregate and keeps ceremony state between the begin and finish steps.
+package passkey
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+const (
+	CeremonyRegistration = "registration"
+	CeremonyLogin        = "login"
+)
+
+const (
+	CodeCeremonyInvalid  domain.ErrorCode = "webauthn_ceremony_invalid"
+	CodeCeremonyNotFound domain.ErrorCode = "webauthn_ceremony_not_found"
+)
+
+var (
+	ErrCeremonyInvalid = domain.NewValidationError(CodeCeremonyInvalid, "ceremony_id",
+		"the WebAuthn ceremony has expired or was already completed")
+	ErrCeremonyNotFound = domain.NewNotFoundError(CodeCeremonyNotFound, "WebAuthn ceremony not found")
+)
+
+// Ceremony is the server side of a registration or login in progress: the
+// challenge the authenticator must sign, and for registrations the name the
+// user chose for the new authenticator.
+type Ceremony struct {
+	ID     string
+	Kind   string
+	UserID string
+	Name   string
+	// Session is the JSON encoded webauthn.SessionData.
+	Session   []byte
+	ExpiresAt time.Time
+}
+
+// CeremonyStore keeps ceremonies until they are finished. Take removes the
+// ceremony, so each challenge can be answered once; it returns
+// ErrCeremonyNotFound when there is none.
+type CeremonyStore interface {
+	Save(ctx context.Context, ceremony *Ceremony) error
+	Take(ctx context.Context, id string) (*Ceremony, error)
+}
+
+// NewRelyingParty configures WebAuthn for passwordless sign-in: user
+// verification is required, discoverable credentials are preferred, and no
+// attestation is requested, so every authenticator is accepted without
+// checking its make and model.
+func NewRelyingParty(rpID, displayName string, origins []string) (*webauthn.WebAuthn, error) {
+	return webauthn.New(&webauthn.Config{
+		RPID:                  rpID,
+		RPDisplayName:         displayName,
+		RPOrigins:             origins,
+		AttestationPreference: protocol.PreferNoAttestation,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementPreferred,
+			RequireResidentKey: protocol.ResidentKeyNotRequired(),
+			UserVerification:   protocol.VerificationRequired,
+		},
+	})
+}
+
+// User exposes a domain user to the relying party. The user handle is the
+// user ID, so a discoverable login can find the user from the assertion.
+func User(user *domain.User) webauthn.User {
+	return webauthnUser{user}
+}
+
+type webauthnUser struct {
+	user *domain.User
+}
+
+func (u webauthnUser) WebAuthnID() []byte {
+	return []byte(u.user.ID)
+}
+
+func (u webauthnUser) WebAuthnName() string {
+	return u.user.Username.String()
+}
+
+func (u webauthnUser) WebAuthnDisplayName() string {
+	return u.user.Name
+}
+
+func (u webauthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, len(u.user.WebAuthnCredentials))
+	for i, c := range u.user.WebAuthnCredentials {
+		transports := make([]protocol.AuthenticatorTransport, len(c.Transports))
+		for j, t := range c.Transports {
+			transports[j] = protocol.AuthenticatorTransport(t)
+		}
+		credentials[i] = webauthn.Credential{
+			ID:              c.ID,
+			PublicKey:       c.PublicKey,
+			AttestationType: c.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: c.BackupEligible,
+				BackupState:    c.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    c.AAGUID,
+				SignCount: c.SignCount,
+			},
+		}
+	}
+	return credentials
+}
+
+// Descriptors lists the user's credentials, to exclude them from a new
+// registration.
+func Descriptors(user *domain.User) []protocol.CredentialDescriptor {
+	credentials := User(user).WebAuthnCredentials()
+	descriptors := make([]protocol.CredentialDescriptor, len(credentials))
+	for i, c := range credentials {
+		descriptors[i] = c.Descriptor()
+	}
+	return descriptors
+}
@@ -0,0 +1,210 @@
+package passkey
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+// SoftwareAuthenticator is an in-process WebAuthn authenticator with
+// ES256 keys and "none" attestation. It lets tests and local scripts run
+// both ceremonies without hardware or a browser; it is not meant to protect
+// real accounts.
+type SoftwareAuthenticator struct {
+	Origin string
+
+	mutex       sync.Mutex
+	credentials map[string]*softwareCredential
+}
+
+type softwareCredential struct {
+	id         []byte
+	key        *ecdsa.PrivateKey
+	rpID       string
+	userHandle []byte
+	signCount  uint32
+}
+
+func NewSoftwareAuthenticator(origin string) *SoftwareAuthenticator {
+	return &SoftwareAuthenticator{
+		Origin:      origin,
+		credentials: make(map[string]*softwareCredential),
+	}
+}
+
+// Create answers registration options (the "publicKey" member of
+// navigator.credentials.create) with the JSON a browser would send back.
+func (a *SoftwareAuthenticator) Create(options protocol.PublicKeyCredentialCreationOptions) ([]byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	userHandle, err := userHandle(options.User.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential := &softwareCredential{
+		id:         id,
+		key:        key,
+		rpID:       options.RelyingParty.ID,
+		userHandle: userHandle,
+	}
+
+	clientData, err := a.clientData("webauthn.create", options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := cbor.Marshal(map[int]any{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: key.PublicKey.X.FillBytes(make([]byte, 32)),
+		-3: key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authData := credential.authenticatorData(true)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(id)))
+	authData = append(authData, id...)
+	authData = append(authData, publicKey...)
+
+	attestationObject, err := cbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a.mutex.Lock()
+	a.credentials[string(id)] = credential
+	a.mutex.Unlock()
+
+	return json.Marshal(map[string]any{
+		"id":    encode(id),
+		"rawId": encode(id),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    encode(clientData),
+			"attestationObject": encode(attestationObject),
+			"transports":        []string{"internal"},
+		},
+	})
+}
+
+// Get answers login options (the "publicKey" member of
+// navigator.credentials.get). With an empty allow list it uses any
+// credential it holds for the relying party, like a passkey would.
+func (a *SoftwareAuthenticator) Get(options protocol.PublicKeyCredentialRequestOptions) ([]byte, error) {
+	credential := a.find(options)
+	if credential == nil {
+		return nil, fmt.Errorf("no credential for relying party %q", options.RelyingPartyID)
+	}
+
+	clientData, err := a.clientData("webauthn.get", options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mutex.Lock()
+	credential.signCount++
+	authData := credential.authenticatorData(false)
+	a.mutex.Unlock()
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, credential.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]any{
+		"id":    encode(credential.id),
+		"rawId": encode(credential.id),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    encode(clientData),
+			"authenticatorData": encode(authData),
+			"signature":         encode(signature),
+			"userHandle":        encode(credential.userHandle),
+		},
+	})
+}
+
+func (a *SoftwareAuthenticator) find(options protocol.PublicKeyCredentialRequestOptions) *softwareCredential {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for _, allowed := range options.AllowedCredentials {
+		if credential, ok := a.credentials[string(allowed.CredentialID)]; ok {
+			return credential
+		}
+	}
+	if len(options.AllowedCredentials) > 0 {
+		return nil
+	}
+	for _, credential := range a.credentials {
+		if credential.rpID == options.RelyingPartyID {
+			return credential
+		}
+	}
+	return nil
+}
+
+func (a *SoftwareAuthenticator) clientData(ceremony string, challenge protocol.URLEncodedBase64) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type":      ceremony,
+		"challenge": encode(challenge),
+		"origin":    a.Origin,
+	})
+}
+
+// authenticatorData builds the fixed part of the authenticator data with
+// the user present and user verified flags set.
+func (c *softwareCredential) authenticatorData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(c.rpID))
+	flags := byte(protocol.FlagUserPresent | protocol.FlagUserVerified)
+	if attested {
+		flags |= byte(protocol.FlagAttestedCredentialData)
+	}
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, c.signCount)
+}
+
+// userHandle accepts the user ID as the relying party built it, or as it
+// arrives after a round trip through JSON.
+func userHandle(id any) ([]byte, error) {
+	switch v := id.(type) {
+	case protocol.URLEncodedBase64:
+		return v, nil
+	case []byte:
+		return v, nil
+	case string:
+		return base64.RawURLEncoding.DecodeString(v)
+	}
+	return nil, fmt.Errorf("unsupported user id %T", id)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
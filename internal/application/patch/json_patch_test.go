@@ -0,0 +1,77 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJSONPatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+		err   error
+	}{
+		{
+			name:  "replace a member",
+			doc:   `{"name":"Jane","tags":["a"]}`,
+			patch: `[{"op":"replace","path":"/name","value":"Joan"}]`,
+			want:  `{"name":"Joan","tags":["a"]}`,
+		},
+		{
+			name:  "replace the whole document",
+			doc:   `{"name":"Jane","tags":["a"]}`,
+			patch: `[{"op":"replace","path":"","value":{"name":"Joan"}}]`,
+			want:  `{"name":"Joan"}`,
+		},
+		{
+			name:  "test then replace the whole document",
+			doc:   `{"name":"Jane"}`,
+			patch: `[{"op":"test","path":"","value":{"name":"Jane"}},{"op":"replace","path":"","value":{"name":"Joan"}}]`,
+			want:  `{"name":"Joan"}`,
+		},
+		{
+			name:  "add at the root replaces the document",
+			doc:   `{"name":"Jane"}`,
+			patch: `[{"op":"add","path":"","value":{"name":"Joan"}}]`,
+			want:  `{"name":"Joan"}`,
+		},
+		{
+			name:  "remove the whole document",
+			doc:   `{"name":"Jane"}`,
+			patch: `[{"op":"remove","path":""}]`,
+			err:   ErrInvalidPatch,
+		},
+		{
+			name:  "replace a missing member",
+			doc:   `{"name":"Jane"}`,
+			patch: `[{"op":"replace","path":"/email","value":"jane@example.com"}]`,
+			err:   ErrPathNotFound,
+		},
+		{
+			name:  "failed test",
+			doc:   `{"name":"Jane"}`,
+			patch: `[{"op":"test","path":"/name","value":"Joan"}]`,
+			err:   ErrTestFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package patch
+
+import (
+	"bytes"
+	"ddd-user-service/internal/domain"
+	"encoding/json"
+	"fmt"
+)
+
+type Format string
+
+const (
+	FormatMergePatch Format = "application/merge-patch+json"
+	FormatJSONPatch  Format = "application/json-patch+json"
+)
+
+const (
+	CodeInvalidPatch domain.ErrorCode = "invalid_patch"
+	CodeTestFailed   domain.ErrorCode = "patch_test_failed"
+	CodePathNotFound domain.ErrorCode = "patch_path_not_found"
+)
+
+var (
+	ErrInvalidPatch = domain.NewValidationError(CodeInvalidPatch, "", "patch document is invalid")
+	ErrTestFailed   = domain.NewConflictError(CodeTestFailed, "", "patch test operation failed")
+	ErrPathNotFound = domain.NewValidationError(CodePathNotFound, "", "patch path does not exist")
+)
+
+// Apply applies a patch document of the given format to doc and returns the
+// patched document.
+func Apply(format Format, doc, patch []byte) ([]byte, error) {
+	switch format {
+	case FormatMergePatch:
+		return MergePatch(doc, patch)
+	case FormatJSONPatch:
+		return JSONPatch(doc, patch)
+	default:
+		return nil, fmt.Errorf("unsupported patch format %q", format)
+	}
+}
+
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return v, nil
+}
+
+func invalid(format string, args ...any) error {
+	return ErrInvalidPatch.WithMessage(fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,292 @@
+package policy
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	CodeListNotFound  domain.ErrorCode = "policy_list_not_found"
+	CodeEntryNotFound domain.ErrorCode = "policy_entry_not_found"
+	CodeEntryBuiltin  domain.ErrorCode = "policy_entry_builtin"
+	CodeValueInvalid  domain.ErrorCode = "policy_value_invalid"
+)
+
+var (
+	ErrListNotFound  = domain.NewNotFoundError(CodeListNotFound, "policy list not found")
+	ErrEntryNotFound = domain.NewNotFoundError(CodeEntryNotFound, "policy entry not found")
+	ErrEntryBuiltin  = domain.NewConflictError(CodeEntryBuiltin, "value", "entries from the configuration cannot be removed at runtime")
+	ErrValueInvalid  = domain.NewValidationError(CodeValueInvalid, "value", "value is not valid for this list")
+)
+
+// List names one of the lists the engine consults.
+type List string
+
+const (
+	// ListReservedUsernames holds usernames nobody can choose, nor anything
+	// that looks like them.
+	ListReservedUsernames List = "reserved_usernames"
+	// ListBlockedWords holds words no username may contain, such as
+	// profanity.
+	ListBlockedWords List = "blocked_words"
+	// ListBlockedDomains holds email domains that cannot be used, such as
+	// disposable mail providers. Subdomains are blocked too.
+	ListBlockedDomains List = "blocked_domains"
+)
+
+var lists = []List{ListReservedUsernames, ListBlockedWords, ListBlockedDomains}
+
+func ParseList(list string) (List, error) {
+	if l := List(list); slices.Contains(lists, l) {
+		return l, nil
+	}
+	return "", ErrListNotFound
+}
+
+// Normalize returns the canonical form of a value for the list.
+func (l List) Normalize(value string) (string, error) {
+	if l == ListBlockedDomains {
+		host, err := domain.ParseEmailDomain(value)
+		if err != nil {
+			return "", ErrValueInvalid
+		}
+		return host, nil
+	}
+	normalized := domain.NormalizeUsername(value).String()
+	if normalized == "" {
+		return "", ErrValueInvalid
+	}
+	return normalized, nil
+}
+
+type Source string
+
+const (
+	// SourceConfig entries are loaded at start up and apply to every tenant.
+	SourceConfig Source = "config"
+	// SourceAdmin entries are managed at runtime, per tenant.
+	SourceAdmin Source = "admin"
+)
+
+type Entry struct {
+	List      List
+	Value     string
+	Source    Source
+	CreatedAt time.Time
+}
+
+// Store keeps the entries managed at runtime. Values are stored normalized.
+type Store interface {
+	// List returns the tenant's entries ordered by value.
+	List(ctx context.Context, tenant domain.TenantID, list List) ([]Entry, error)
+	Contains(ctx context.Context, tenant domain.TenantID, list List, value string) (bool, error)
+	// Add returns the stored entry and false when the value is already on
+	// the list.
+	Add(ctx context.Context, tenant domain.TenantID, entry Entry) (Entry, bool, error)
+	// Remove returns ErrEntryNotFound for an unknown entry.
+	Remove(ctx context.Context, tenant domain.TenantID, list List, value string) error
+}
+
+// Config holds the entries loaded at start up.
+type Config struct {
+	ReservedUsernames []string
+	BlockedWords      []string
+	BlockedDomains    []string
+	// DetectConfusables rejects usernames that look like an existing one.
+	DetectConfusables bool
+}
+
+// Engine decides whether an email or username may be chosen, beyond the
+// domain's own rules. Usernames are compared by Username.Skeleton, so
+// lookalike spellings of a reserved name or blocked word are caught too.
+type Engine struct {
+	store             Store
+	users             domain.UserRepository
+	builtin           map[List][]string
+	builtinSet        map[List]map[string]bool
+	detectConfusables bool
+	now               func() time.Time
+}
+
+// NewEngine rejects configured values that are invalid for their list.
+func NewEngine(store Store, users domain.UserRepository, cfg Config) (*Engine, error) {
+	e := &Engine{
+		store:             store,
+		users:             users,
+		builtin:           make(map[List][]string),
+		builtinSet:        make(map[List]map[string]bool),
+		detectConfusables: cfg.DetectConfusables,
+		now:               time.Now,
+	}
+
+	configured := map[List][]string{
+		ListReservedUsernames: cfg.ReservedUsernames,
+		ListBlockedWords:      cfg.BlockedWords,
+		ListBlockedDomains:    cfg.BlockedDomains,
+	}
+	for list, values := range configured {
+		set := make(map[string]bool, len(values))
+		for _, value := range values {
+			normalized, err := list.Normalize(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid value %q", list, value)
+			}
+			set[normalized] = true
+		}
+		e.builtinSet[list] = set
+		for value := range set {
+			e.builtin[list] = append(e.builtin[list], value)
+		}
+		slices.Sort(e.builtin[list])
+	}
+
+	return e, nil
+}
+
+// CheckIdentifiers reports the policies a new or changed email or username
+// breaks; pass an empty value to skip it. self is the user being changed,
+// who is not compared with themselves.
+func (e *Engine) CheckIdentifiers(ctx context.Context, tenant domain.TenantID, email domain.Email, username domain.Username, self domain.UserID) error {
+	var errs domain.ValidationErrors
+	if email != "" {
+		blocked, err := e.domainBlocked(ctx, tenant, email.Domain())
+		if err != nil {
+			return err
+		}
+		if blocked {
+			errs.Add(domain.ErrEmailDomainBlocked)
+		}
+	}
+	if username != "" {
+		err := e.checkUsername(ctx, tenant, username)
+		if err != nil && !isValidation(err) {
+			return err
+		}
+		errs.Add(err)
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	if username != "" && e.detectConfusables {
+		return e.checkConfusable(ctx, tenant, username, self)
+	}
+	return nil
+}
+
+func (e *Engine) checkUsername(ctx context.Context, tenant domain.TenantID, username domain.Username) error {
+	skeleton := username.Skeleton()
+
+	reserved, err := e.values(ctx, tenant, ListReservedUsernames)
+	if err != nil {
+		return err
+	}
+	for _, value := range reserved {
+		if domain.Username(value).Skeleton() == skeleton {
+			return domain.ErrUsernameReserved
+		}
+	}
+
+	words, err := e.values(ctx, tenant, ListBlockedWords)
+	if err != nil {
+		return err
+	}
+	for _, value := range words {
+		if strings.Contains(skeleton, domain.Username(value).Skeleton()) {
+			return domain.ErrUsernameBlocked
+		}
+	}
+	return nil
+}
+
+// checkConfusable leaves exact matches to the username uniqueness check.
+func (e *Engine) checkConfusable(ctx context.Context, tenant domain.TenantID, username domain.Username, self domain.UserID) error {
+	users, err := e.users.FindByUsernameSkeleton(ctx, tenant, username.Skeleton())
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if user.ID != self && user.Username != username {
+			return domain.ErrUsernameConfusable
+		}
+	}
+	return nil
+}
+
+// domainBlocked checks the domain and each of its parents.
+func (e *Engine) domainBlocked(ctx context.Context, tenant domain.TenantID, host string) (bool, error) {
+	for host != "" {
+		if e.builtinSet[ListBlockedDomains][host] {
+			return true, nil
+		}
+		blocked, err := e.store.Contains(ctx, tenant, ListBlockedDomains, host)
+		if err != nil {
+			return false, err
+		}
+		if blocked {
+			return true, nil
+		}
+		_, host, _ = strings.Cut(host, ".")
+	}
+	return false, nil
+}
+
+func (e *Engine) values(ctx context.Context, tenant domain.TenantID, list List) ([]string, error) {
+	entries, err := e.store.List(ctx, tenant, list)
+	if err != nil {
+		return nil, err
+	}
+	values := slices.Clone(e.builtin[list])
+	for _, entry := range entries {
+		values = append(values, entry.Value)
+	}
+	return values, nil
+}
+
+// Entries returns the configured entries followed by the tenant's own.
+func (e *Engine) Entries(ctx context.Context, tenant domain.TenantID, list List) ([]Entry, error) {
+	stored, err := e.store.List(ctx, tenant, list)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(e.builtin[list])+len(stored))
+	for _, value := range e.builtin[list] {
+		entries = append(entries, Entry{List: list, Value: value, Source: SourceConfig})
+	}
+	return append(entries, stored...), nil
+}
+
+// Add stores an entry for the tenant. It reports false, and changes
+// nothing, when the value is already on the list.
+func (e *Engine) Add(ctx context.Context, tenant domain.TenantID, list List, value string) (Entry, bool, error) {
+	normalized, err := list.Normalize(value)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	if e.builtinSet[list][normalized] {
+		return Entry{List: list, Value: normalized, Source: SourceConfig}, false, nil
+	}
+
+	return e.store.Add(ctx, tenant, Entry{List: list, Value: normalized, Source: SourceAdmin, CreatedAt: e.now().UTC()})
+}
+
+func (e *Engine) Remove(ctx context.Context, tenant domain.TenantID, list List, value string) error {
+	normalized, err := list.Normalize(value)
+	if err != nil {
+		return ErrEntryNotFound
+	}
+	if e.builtinSet[list][normalized] {
+		return ErrEntryBuiltin
+	}
+	return e.store.Remove(ctx, tenant, list, normalized)
+}
+
+func isValidation(err error) bool {
+	var domainErr *domain.Error
+	return errors.As(err, &domainErr) && domainErr.Kind == domain.KindValidation
+}
@@ -0,0 +1,82 @@
+package policy
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"errors"
+	"testing"
+)
+
+// tenantEntries is a Store holding entries for every tenant alike.
+type tenantEntries map[List][]Entry
+
+func (s tenantEntries) List(_ context.Context, _ domain.TenantID, list List) ([]Entry, error) {
+	return s[list], nil
+}
+
+func (s tenantEntries) Contains(_ context.Context, _ domain.TenantID, list List, value string) (bool, error) {
+	for _, entry := range s[list] {
+		if entry.Value == value {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s tenantEntries) Add(context.Context, domain.TenantID, Entry) (Entry, bool, error) {
+	return Entry{}, false, errors.ErrUnsupported
+}
+
+func (s tenantEntries) Remove(context.Context, domain.TenantID, List, string) error {
+	return errors.ErrUnsupported
+}
+
+func TestReservedUsernames(t *testing.T) {
+	store := tenantEntries{ListReservedUsernames: {{List: ListReservedUsernames, Value: "helpdesk", Source: SourceAdmin}}}
+	engine, err := NewEngine(store, nil, Config{ReservedUsernames: []string{"Admin", " root "}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		username string
+		reserved bool
+	}{
+		{"admin", true},
+		{"root", true},
+		{"r00t", true},
+		{"ad.min", true},
+		{"аdmin", true}, // Cyrillic а
+		{"helpdesk", true},
+		{"help_desk", true},
+		{"administrator", false},
+		{"jane", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			err := engine.CheckIdentifiers(context.Background(), domain.DefaultTenantID, "", domain.NormalizeUsername(tt.username), "")
+			if got := errors.Is(err, domain.ErrUsernameReserved); got != tt.reserved {
+				t.Fatalf("reserved = %v (%v), want %v", got, err, tt.reserved)
+			}
+		})
+	}
+}
+
+func TestConfiguredEntriesCannotBeRemoved(t *testing.T) {
+	engine, err := NewEngine(tenantEntries{}, nil, Config{ReservedUsernames: []string{"Admin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := engine.Entries(context.Background(), domain.DefaultTenantID, ListReservedUsernames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Value != "admin" || entries[0].Source != SourceConfig {
+		t.Fatalf("entries = %+v, want the normalized configured entry", entries)
+	}
+	if err := engine.Remove(context.Background(), domain.DefaultTenantID, ListReservedUsernames, "ADMIN"); !errors.Is(err, ErrEntryBuiltin) {
+		t.Fatalf("err = %v, want %v", err, ErrEntryBuiltin)
+	}
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy is a token bucket: up to Burst requests at once, refilled at Burst
+// tokens per Period.
+type Policy struct {
+	Name   string
+	Burst  int
+	Period time.Duration
+}
+
+func (p Policy) Enabled() bool {
+	return p.Burst > 0 && p.Period > 0
+}
+
+// RefillInterval is the time it takes to regain a single token.
+func (p Policy) RefillInterval() time.Duration {
+	return p.Period / time.Duration(p.Burst)
+}
+
+// ParsePolicy reads a policy written as "<requests>/<period>", e.g. "5/1m".
+// An empty string or "off" disables the policy.
+func ParsePolicy(name, raw string) (Policy, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "off" {
+		return Policy{Name: name}, nil
+	}
+
+	count, period, ok := strings.Cut(raw, "/")
+	if !ok {
+		return Policy{}, fmt.Errorf("rate limit %q: expected <requests>/<period>", raw)
+	}
+	burst, err := strconv.Atoi(count)
+	if err != nil || burst <= 0 {
+		return Policy{}, fmt.Errorf("rate limit %q: invalid request count", raw)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Policy{}, fmt.Errorf("rate limit %q: invalid period", raw)
+	}
+
+	return Policy{Name: name, Burst: burst, Period: d}, nil
+}
+
+type Decision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Store keeps token buckets. Implementations backed by a shared service
+// (Redis, a database) must apply Take atomically so that several instances
+// share one budget.
+type Store interface {
+	Take(ctx context.Context, key string, policy Policy) (Decision, error)
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/application/token"
+	"ddd-user-service/internal/domain"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const CodeAPIKeyInvalid domain.ErrorCode = "api_key_invalid"
+
+var ErrAPIKeyInvalid = domain.NewUnauthenticatedError(CodeAPIKeyInvalid, "the API key is invalid, expired or revoked")
+
+// apiKeyTouchInterval limits how often last-used times are written.
+const apiKeyTouchInterval = time.Minute
+
+// apiKeyDisplayLength is how much of a key is kept in the clear, prefix
+// included, so owners can tell keys apart.
+const apiKeyDisplayLength = len(domain.APIKeyPrefix) + 8
+
+type APIKeyOptions struct {
+	// RotationOverlap is how long a rotated key keeps working when the
+	// request does not say.
+	RotationOverlap time.Duration
+}
+
+type APIKeyService struct {
+	keys     domain.APIKeyRepository
+	userRepo domain.UserRepository
+	opts     APIKeyOptions
+	now      func() time.Time
+	tracer   trace.Tracer
+}
+
+func NewAPIKeyService(keys domain.APIKeyRepository, userRepo domain.UserRepository, opts APIKeyOptions) *APIKeyService {
+	return &APIKeyService{
+		keys:     keys,
+		userRepo: userRepo,
+		opts:     opts,
+		now:      time.Now,
+		tracer:   otel.Tracer(serviceTracerName),
+	}
+}
+
+func (s *APIKeyService) begin(ctx context.Context, op string, userID string) (context.Context, *slog.Logger, func(error)) {
+	return beginOperation(ctx, s.tracer, "APIKeyService", op, userID)
+}
+
+// CreateAPIKey issues a key for the owner. The key is returned once and
+// only its hash is stored.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, ownerID string, req dto.CreateAPIKeyRequest) (_ *dto.CreatedAPIKeyResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "CreateAPIKey", ownerID)
+	defer func() { done(err) }()
+
+	owner, err := s.owner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, raw, err := s.newKey(owner, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.keys.Save(ctx, key); err != nil {
+		return nil, err
+	}
+
+	logger.Info("API key created", slog.String("api_key_id", key.ID.String()), slog.Any("scopes", key.Scopes))
+	return &dto.CreatedAPIKeyResponse{APIKeyResponse: *s.apiKeyToResponse(key), Key: raw}, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, ownerID string) (_ []*dto.APIKeyResponse, err error) {
+	ctx, _, done := s.begin(ctx, "ListAPIKeys", ownerID)
+	defer func() { done(err) }()
+
+	if _, err := s.owner(ctx, ownerID); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.keys.ListByOwner(ctx, domain.UserID(ownerID))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = s.apiKeyToResponse(key)
+	}
+	return responses, nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, ownerID, keyID string) (err error) {
+	ctx, logger, done := s.begin(ctx, "RevokeAPIKey", ownerID)
+	defer func() { done(err) }()
+
+	if _, err := s.owner(ctx, ownerID); err != nil {
+		return err
+	}
+
+	if err := s.keys.Delete(ctx, domain.UserID(ownerID), domain.APIKeyID(keyID)); err != nil {
+		return err
+	}
+
+	logger.Info("API key revoked", slog.String("api_key_id", keyID))
+	return nil
+}
+
+// RotateAPIKey issues a replacement with the same name and scopes. The old
+// key keeps working until the overlap ends, so clients can be switched over
+// without downtime.
+func (s *APIKeyService) RotateAPIKey(ctx context.Context, ownerID, keyID string, req dto.RotateAPIKeyRequest) (_ *dto.CreatedAPIKeyResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "RotateAPIKey", ownerID)
+	defer func() { done(err) }()
+
+	owner, err := s.owner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := s.keys.Get(ctx, owner.ID, domain.APIKeyID(keyID))
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if err := old.CanRotate(now); err != nil {
+		return nil, err
+	}
+
+	key, raw, err := s.newKey(owner, old.Name, old.Scopes, req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	overlap := s.opts.RotationOverlap
+	if req.OverlapSeconds != nil {
+		overlap = time.Duration(*req.OverlapSeconds) * time.Second
+	}
+	if err := old.Retire(key.ID, now, overlap); err != nil {
+		return nil, err
+	}
+
+	if err := s.keys.Save(ctx, key); err != nil {
+		return nil, err
+	}
+	if err := s.keys.Update(ctx, old); err != nil {
+		return nil, err
+	}
+
+	logger.Info("API key rotated",
+		slog.String("api_key_id", old.ID.String()),
+		slog.String("replaced_by", key.ID.String()),
+		slog.Duration("overlap", overlap),
+	)
+	return &dto.CreatedAPIKeyResponse{APIKeyResponse: *s.apiKeyToResponse(key), Key: raw}, nil
+}
+
+// VerifyAPIKey resolves a raw key to the caller it belongs to. Keys of
+// missing or suspended owners are refused.
+func (s *APIKeyService) VerifyAPIKey(ctx context.Context, raw string) (*dto.Principal, error) {
+	if !strings.HasPrefix(raw, domain.APIKeyPrefix) {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	key, err := s.keys.GetBySecretHash(ctx, token.Hash(raw))
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return nil, ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	if !key.Active(now) {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, key.TenantID, key.OwnerID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if owner.Suspended() {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.keys.Touch(ctx, key.ID, now); err != nil && !errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return nil, err
+		}
+	}
+
+	return &dto.Principal{
+		UserID:       owner.ID.String(),
+		TenantID:     owner.TenantID.String(),
+		Role:         owner.Role.String(),
+		Scope:        dto.ScopeAPIKey,
+		APIKeyID:     key.ID.String(),
+		APIKeyScopes: key.Scopes,
+	}, nil
+}
+
+// owner returns the key owner, who must belong to the request's tenant.
+// Keys are stored by owner alone, so this is what keeps one tenant's
+// administrators away from another tenant's keys.
+func (s *APIKeyService) owner(ctx context.Context, ownerID string) (*domain.User, error) {
+	return s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(ownerID))
+}
+
+func (s *APIKeyService) newKey(owner *domain.User, name string, scopes []string, expiresAt *time.Time) (*domain.APIKey, string, error) {
+	secret, err := token.NewOpaque()
+	if err != nil {
+		return nil, "", err
+	}
+	raw := domain.APIKeyPrefix + secret
+
+	key, err := domain.NewAPIKey(owner.TenantID, owner.ID, name, scopes, expiresAt, raw[:apiKeyDisplayLength], token.Hash(raw), s.now())
+	if err != nil {
+		return nil, "", err
+	}
+	return key, raw, nil
+}
+
+func (s *APIKeyService) apiKeyToResponse(key *domain.APIKey) *dto.APIKeyResponse {
+	return &dto.APIKeyResponse{
+		ID:         key.ID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		Active:     key.Active(s.now()),
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		ReplacedBy: key.ReplacedBy.String(),
+	}
+}
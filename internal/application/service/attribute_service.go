@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/domain"
+	"errors"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AttributeService manages the custom attributes defined for the request's
+// tenant.
+type AttributeService struct {
+	schemas domain.AttributeSchemaRepository
+	users   domain.UserRepository
+	indexer domain.AttributeIndexer
+	now     func() time.Time
+	tracer  trace.Tracer
+}
+
+// NewAttributeService creates the service. indexer may be nil when the user
+// store has no indexes to maintain.
+func NewAttributeService(schemas domain.AttributeSchemaRepository, users domain.UserRepository, indexer domain.AttributeIndexer) *AttributeService {
+	return &AttributeService{
+		schemas: schemas,
+		users:   users,
+		indexer: indexer,
+		now:     time.Now,
+		tracer:  otel.Tracer(serviceTracerName),
+	}
+}
+
+func (s *AttributeService) begin(ctx context.Context, op string) (context.Context, *slog.Logger, func(error)) {
+	return beginOperation(ctx, s.tracer, "AttributeService", op, "")
+}
+
+func (s *AttributeService) ListAttributes(ctx context.Context) (_ *dto.AttributeListResponse, err error) {
+	ctx, _, done := s.begin(ctx, "ListAttributes")
+	defer func() { done(err) }()
+
+	schemas, err := s.schemas.List(ctx, tenancy.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.AttributeListResponse{Attributes: make([]dto.AttributeResponse, len(schemas))}
+	for i, schema := range schemas {
+		resp.Attributes[i] = attributeToResponse(schema)
+	}
+	return resp, nil
+}
+
+func (s *AttributeService) GetAttribute(ctx context.Context, name string) (_ *dto.AttributeResponse, err error) {
+	ctx, _, done := s.begin(ctx, "GetAttribute")
+	defer func() { done(err) }()
+
+	schema, err := s.schemas.Get(ctx, tenancy.FromContext(ctx), name)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := attributeToResponse(schema)
+	return &resp, nil
+}
+
+// CreateAttribute defines a new attribute. Users created before a required
+// attribute existed must be given a value on their next update.
+func (s *AttributeService) CreateAttribute(ctx context.Context, req dto.CreateAttributeRequest) (_ *dto.AttributeResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "CreateAttribute")
+	defer func() { done(err) }()
+
+	tenant := tenancy.FromContext(ctx)
+	schema, err := domain.NewAttributeSchema(tenant, req.Name, req.Type, req.Required, req.Unique, req.Pattern, req.Enum, s.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.schemas.Save(ctx, schema); err != nil {
+		return nil, err
+	}
+
+	if schema.Unique && s.indexer != nil {
+		if err := s.indexer.EnsureUniqueAttribute(ctx, tenant, schema.Name); err != nil {
+			// Without its index the attribute would not be unique; take the
+			// definition back.
+			if deleteErr := s.schemas.Delete(ctx, tenant, schema.Name); deleteErr != nil {
+				err = errors.Join(err, deleteErr)
+			}
+			return nil, err
+		}
+	}
+
+	logger.Info("attribute created",
+		slog.String("name", schema.Name),
+		slog.String("type", string(schema.Type)),
+		slog.Bool("required", schema.Required),
+		slog.Bool("unique", schema.Unique),
+	)
+	resp := attributeToResponse(schema)
+	return &resp, nil
+}
+
+// DeleteAttribute removes the definition together with every user's value.
+func (s *AttributeService) DeleteAttribute(ctx context.Context, name string) (err error) {
+	ctx, logger, done := s.begin(ctx, "DeleteAttribute")
+	defer func() { done(err) }()
+
+	tenant := tenancy.FromContext(ctx)
+	schema, err := s.schemas.Get(ctx, tenant, name)
+	if err != nil {
+		return err
+	}
+
+	if err := s.schemas.Delete(ctx, tenant, name); err != nil {
+		return err
+	}
+	if err := s.users.UnsetAttribute(ctx, tenant, name); err != nil {
+		return err
+	}
+	if schema.Unique && s.indexer != nil {
+		if err := s.indexer.DropUniqueAttribute(ctx, tenant, name); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("attribute deleted", slog.String("name", name))
+	return nil
+}
+
+func attributeToResponse(schema *domain.AttributeSchema) dto.AttributeResponse {
+	return dto.AttributeResponse{
+		Name:      schema.Name,
+		Type:      string(schema.Type),
+		Required:  schema.Required,
+		Unique:    schema.Unique,
+		Pattern:   schema.Pattern,
+		Enum:      schema.Enum,
+		CreatedAt: schema.CreatedAt,
+	}
+}
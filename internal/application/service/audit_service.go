@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/domain"
+	"log/slog"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+var (
+	ErrAuditLimitInvalid = domain.NewValidationError("limit_invalid", "limit", "limit must be a number from 1 to 1000").
+				WithParams(map[string]any{"min": 1, "max": maxAuditLimit})
+	ErrAuditBeforeInvalid = domain.NewValidationError("before_invalid", "before", "before must be a positive sequence number")
+)
+
+// AuditService reads the audit log of the request's tenant.
+type AuditService struct {
+	entries domain.AuditRepository
+	key     []byte
+	tracer  trace.Tracer
+}
+
+// NewAuditService builds the service; key must be the one the log's
+// entries were hashed with.
+func NewAuditService(entries domain.AuditRepository, key []byte) *AuditService {
+	return &AuditService{
+		entries: entries,
+		key:     key,
+		tracer:  otel.Tracer(serviceTracerName),
+	}
+}
+
+func (s *AuditService) begin(ctx context.Context, op string) (context.Context, *slog.Logger, func(error)) {
+	return beginOperation(ctx, s.tracer, "AuditService", op, "")
+}
+
+// ListAuditEntries returns entries newest first.
+func (s *AuditService) ListAuditEntries(ctx context.Context, query dto.AuditQuery) (_ *dto.AuditListResponse, err error) {
+	ctx, _, done := s.begin(ctx, "ListAuditEntries")
+	defer func() { done(err) }()
+
+	filter := domain.AuditFilter{UserID: domain.UserID(query.UserID), Limit: defaultAuditLimit}
+	var errs domain.ValidationErrors
+	if query.Limit != "" {
+		limit, err := strconv.Atoi(query.Limit)
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			errs.Add(ErrAuditLimitInvalid)
+		}
+		filter.Limit = limit
+	}
+	if query.Before != "" {
+		before, err := strconv.ParseInt(query.Before, 10, 64)
+		if err != nil || before < 1 {
+			errs.Add(ErrAuditBeforeInvalid)
+		}
+		filter.Before = before
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.entries.List(ctx, tenancy.FromContext(ctx), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.AuditListResponse{Entries: make([]dto.AuditEntryResponse, len(entries))}
+	for i, entry := range entries {
+		resp.Entries[i] = auditEntryToResponse(entry)
+	}
+	return resp, nil
+}
+
+// VerifyAuditChain recomputes every hash of the tenant's log.
+func (s *AuditService) VerifyAuditChain(ctx context.Context) (_ *dto.AuditVerifyResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "VerifyAuditChain")
+	defer func() { done(err) }()
+
+	entries, err := s.entries.Chain(ctx, tenancy.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.AuditVerifyResponse{Valid: true, Entries: len(entries)}
+	if brokenAt := domain.VerifyAuditChain(s.key, entries); brokenAt > 0 {
+		resp.Valid = false
+		resp.BrokenAt = brokenAt
+		logger.Warn("audit chain broken", slog.Int64("sequence", brokenAt))
+	}
+	if len(entries) > 0 {
+		resp.LastHash = entries[len(entries)-1].Hash
+	}
+	return resp, nil
+}
+
+func auditEntryToResponse(entry *domain.AuditEntry) dto.AuditEntryResponse {
+	resp := dto.AuditEntryResponse{
+		ID:       entry.ID,
+		Sequence: entry.Sequence,
+		UserID:   entry.UserID.String(),
+		Action:   string(entry.Action),
+		Actor: dto.AuditActorResponse{
+			UserID:    entry.Actor.UserID,
+			APIKeyID:  entry.Actor.APIKeyID,
+			RequestID: entry.Actor.RequestID,
+			IP:        entry.Actor.IP,
+		},
+		Changes:    make([]dto.FieldChangeResponse, len(entry.Changes)),
+		OccurredAt: entry.OccurredAt,
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	}
+	for i, change := range entry.Changes {
+		resp.Changes[i] = dto.FieldChangeResponse{Field: change.Field, Before: change.Before, After: change.After}
+	}
+	return resp
+}
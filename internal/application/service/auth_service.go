@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/event"
+	"ddd-user-service/internal/application/lockout"
+	"ddd-user-service/internal/application/mail"
+	"ddd-user-service/internal/application/passkey"
+	"ddd-user-service/internal/application/password"
+	"ddd-user-service/internal/application/token"
+	"ddd-user-service/internal/domain"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AuthDependencies are the ports AuthService works through.
+type AuthDependencies struct {
+	Users        domain.UserRepository
+	ResetTokens  domain.PasswordResetRepository
+	Ceremonies   passkey.CeremonyStore
+	Sessions     *SessionService
+	Lockout      *lockout.Guard
+	Hasher       password.Hasher
+	Tokens       *token.Signer
+	RelyingParty *webauthn.WebAuthn
+	Mailer       mail.Mailer
+	Events       event.Publisher
+}
+
+type AuthOptions struct {
+	MFAChallengeTTL  time.Duration
+	MFARequiredRoles []domain.Role
+	TOTPIssuer       string
+	ResetTTL         time.Duration
+	CeremonyTTL      time.Duration
+}
+
+// AuthService handles credentials: everything that proves who a user is, as
+// opposed to the profile data managed by UserService.
+type AuthService struct {
+	userRepo     domain.UserRepository
+	resetTokens  domain.PasswordResetRepository
+	ceremonies   passkey.CeremonyStore
+	sessions     *SessionService
+	lockout      *lockout.Guard
+	hasher       password.Hasher
+	tokens       *token.Signer
+	relyingParty *webauthn.WebAuthn
+	mailer       mail.Mailer
+	events       event.Publisher
+	opts         AuthOptions
+	now          func() time.Time
+	tracer       trace.Tracer
+
+	dummyHashOnce sync.Once
+	dummyHash     string
+}
+
+func NewAuthService(deps AuthDependencies, opts AuthOptions) *AuthService {
+	return &AuthService{
+		userRepo:     deps.Users,
+		resetTokens:  deps.ResetTokens,
+		ceremonies:   deps.Ceremonies,
+		sessions:     deps.Sessions,
+		lockout:      deps.Lockout,
+		hasher:       deps.Hasher,
+		tokens:       deps.Tokens,
+		relyingParty: deps.RelyingParty,
+		mailer:       deps.Mailer,
+		events:       deps.Events,
+		opts:         opts,
+		now:          time.Now,
+		tracer:       otel.Tracer(serviceTracerName),
+	}
+}
+
+func (s *AuthService) begin(ctx context.Context, op string, userID string) (context.Context, *slog.Logger, func(error)) {
+	return beginOperation(ctx, s.tracer, "AuthService", op, userID)
+}
+
+func (s *AuthService) mfaRequired(role domain.Role) bool {
+	return slices.Contains(s.opts.MFARequiredRoles, role)
+}
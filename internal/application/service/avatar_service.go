@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/avatar"
+	"ddd-user-service/internal/application/blob"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/logging"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const CodeAvatarNotFound domain.ErrorCode = "avatar_not_found"
+
+var ErrAvatarNotFound = domain.NewNotFoundError(CodeAvatarNotFound, "avatar not found")
+
+// avatarFilePattern matches the file names images are stored under, such
+// as "128.jpg".
+var avatarFilePattern = regexp.MustCompile(`^[0-9]+\.(jpg|png)$`)
+
+type AvatarDependencies struct {
+	Users     domain.UserRepository
+	Blobs     blob.Store
+	Processor *avatar.Processor
+}
+
+type AvatarOptions struct {
+	// BaseURL is where clients reach this service, such as
+	// "https://users.example.com". Avatar URLs are built on it.
+	BaseURL string
+}
+
+// AvatarService stores uploaded profile pictures. The images of an upload
+// are kept under a fresh ID and never modified, so they can be cached
+// forever; replacing the avatar removes the previous upload's images.
+type AvatarService struct {
+	userRepo  domain.UserRepository
+	blobs     blob.Store
+	processor *avatar.Processor
+	opts      AvatarOptions
+	now       func() time.Time
+	tracer    trace.Tracer
+}
+
+func NewAvatarService(deps AvatarDependencies, opts AvatarOptions) *AvatarService {
+	opts.BaseURL = strings.TrimSuffix(opts.BaseURL, "/")
+	return &AvatarService{
+		userRepo:  deps.Users,
+		blobs:     deps.Blobs,
+		processor: deps.Processor,
+		opts:      opts,
+		now:       time.Now,
+		tracer:    otel.Tracer(serviceTracerName),
+	}
+}
+
+func (s *AvatarService) begin(ctx context.Context, op string, userID string) (context.Context, *slog.Logger, func(error)) {
+	return beginOperation(ctx, s.tracer, "AvatarService", op, userID)
+}
+
+// MaxUploadBytes is the largest file UploadAvatar accepts.
+func (s *AvatarService) MaxUploadBytes() int64 {
+	return s.processor.MaxBytes()
+}
+
+// UploadAvatar processes the picture, stores its images and makes the
+// largest the user's profile avatar_url.
+func (s *AvatarService) UploadAvatar(ctx context.Context, id string, data []byte) (_ *dto.AvatarResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "UploadAvatar", id)
+	defer func() { done(err) }()
+
+	tenant := tenancy.FromContext(ctx)
+	user, err := s.userRepo.GetByID(ctx, tenant, domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := s.processor.Process(data)
+	if err != nil {
+		return nil, err
+	}
+
+	uploaded := domain.Avatar{
+		ID:          uuid.New().String(),
+		ContentType: images[0].ContentType,
+		UploadedAt:  s.now().UTC(),
+	}
+	for _, image := range images {
+		uploaded.Sizes = append(uploaded.Sizes, image.Size)
+	}
+
+	for _, image := range images {
+		key := avatarKey(tenant, uploaded, image.Size)
+		if err := s.blobs.Put(ctx, key, blob.Object{ContentType: image.ContentType, Data: image.Data}); err != nil {
+			s.removeImages(ctx, logger, tenant, uploaded)
+			return nil, fmt.Errorf("failed to store avatar: %w", err)
+		}
+	}
+
+	previous, err := user.SetAvatar(uploaded, s.avatarURL(tenant, uploaded, uploaded.Sizes[0]))
+	if err == nil {
+		err = s.userRepo.Update(ctx, user)
+	}
+	if err != nil {
+		s.removeImages(ctx, logger, tenant, uploaded)
+		return nil, err
+	}
+	if previous != nil {
+		s.removeImages(ctx, logger, tenant, *previous)
+	}
+
+	logger.Info("avatar uploaded", "avatar_id", uploaded.ID, "bytes", len(data))
+	return s.avatarToResponse(tenant, uploaded), nil
+}
+
+// GetAvatarImage returns a stored image by the parts of its URL. It needs
+// no tenant in the context, so images can be served to anyone.
+func (s *AvatarService) GetAvatarImage(ctx context.Context, tenant, avatarID, file string) (_ blob.Object, err error) {
+	ctx, _, done := s.begin(ctx, "GetAvatarImage", "")
+	defer func() { done(err) }()
+
+	parsedTenant, err := domain.ParseTenantID(tenant)
+	if err != nil || parsedTenant.String() != tenant {
+		return blob.Object{}, ErrAvatarNotFound
+	}
+	if _, err := uuid.Parse(avatarID); err != nil {
+		return blob.Object{}, ErrAvatarNotFound
+	}
+	if !avatarFilePattern.MatchString(file) {
+		return blob.Object{}, ErrAvatarNotFound
+	}
+
+	obj, err := s.blobs.Get(ctx, path.Join("avatars", tenant, avatarID, file))
+	if errors.Is(err, blob.ErrNotFound) {
+		return blob.Object{}, ErrAvatarNotFound
+	}
+	return obj, err
+}
+
+// RemoveOnEvent is an event handler that removes a deleted user's avatar
+// images, which are served without credentials.
+func (s *AvatarService) RemoveOnEvent(ctx context.Context, e domain.Event) (err error) {
+	deleted, ok := e.(domain.UserDeleted)
+	if !ok {
+		return fmt.Errorf("unexpected event %s", e.EventName())
+	}
+	if deleted.Avatar == nil {
+		return nil
+	}
+
+	ctx, logger, done := s.begin(ctx, "RemoveOnEvent", deleted.UserID.String())
+	defer func() { done(err) }()
+
+	s.removeImages(ctx, logger, deleted.TenantID, *deleted.Avatar)
+	logger.Info("avatar removed with its user", "avatar_id", deleted.Avatar.ID)
+	return nil
+}
+
+// removeImages is best effort: an image left behind is unreachable once no
+// user refers to it, so failures are logged rather than returned.
+func (s *AvatarService) removeImages(ctx context.Context, logger *slog.Logger, tenant domain.TenantID, uploaded domain.Avatar) {
+	for _, size := range uploaded.Sizes {
+		if err := s.blobs.Delete(ctx, avatarKey(tenant, uploaded, size)); err != nil {
+			logger.Warn("failed to remove avatar image", "avatar_id", uploaded.ID, logging.KeyError, err)
+		}
+	}
+}
+
+func (s *AvatarService) avatarURL(tenant domain.TenantID, uploaded domain.Avatar, size int) string {
+	return s.opts.BaseURL + "/" + avatarKey(tenant, uploaded, size)
+}
+
+func (s *AvatarService) avatarToResponse(tenant domain.TenantID, uploaded domain.Avatar) *dto.AvatarResponse {
+	resp := &dto.AvatarResponse{
+		ID:          uploaded.ID,
+		URL:         s.avatarURL(tenant, uploaded, uploaded.Sizes[0]),
+		ContentType: uploaded.ContentType,
+		UploadedAt:  uploaded.UploadedAt,
+	}
+	for _, size := range uploaded.Sizes {
+		resp.Images = append(resp.Images, dto.AvatarImageResponse{
+			Size: size,
+			URL:  s.avatarURL(tenant, uploaded, size),
+		})
+	}
+	return resp
+}
+
+// avatarKey is both the blob key and the path the image is served at.
+func avatarKey(tenant domain.TenantID, uploaded domain.Avatar, size int) string {
+	return path.Join("avatars", tenant.String(), uploaded.ID, strconv.Itoa(size)+avatar.Extension(uploaded.ContentType))
+}
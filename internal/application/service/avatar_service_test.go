@@ -0,0 +1,101 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"ddd-user-service/internal/application/avatar"
+	"ddd-user-service/internal/application/blob"
+	"ddd-user-service/internal/application/event"
+	"ddd-user-service/internal/application/password"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/infrastructure/repository"
+	"errors"
+	"image"
+	"image/png"
+	"path"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blobMap is an in-memory blob.Store.
+type blobMap struct {
+	mutex   sync.Mutex
+	objects map[string]blob.Object
+}
+
+func (b *blobMap) Put(_ context.Context, key string, obj blob.Object) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.objects[key] = obj
+	return nil
+}
+
+func (b *blobMap) Get(_ context.Context, key string) (blob.Object, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	obj, ok := b.objects[key]
+	if !ok {
+		return blob.Object{}, blob.ErrNotFound
+	}
+	return obj, nil
+}
+
+func (b *blobMap) Delete(_ context.Context, key string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	delete(b.objects, key)
+	return nil
+}
+
+func TestDeletingUserRemovesAvatar(t *testing.T) {
+	ctx := tenancy.WithTenant(context.Background(), domain.DefaultTenantID)
+	users := repository.NewMemoryUserRepository()
+	blobs := &blobMap{objects: make(map[string]blob.Object)}
+	events := event.NewDispatcher()
+
+	avatars := NewAvatarService(AvatarDependencies{
+		Users:     users,
+		Blobs:     blobs,
+		Processor: avatar.NewProcessor(avatar.Options{MaxBytes: 1 << 20, MaxPixels: 1 << 20, Sizes: []int{32, 16}}),
+	}, AvatarOptions{BaseURL: "https://users.example.com"})
+	userService := NewUserService(users, nil, nil, password.NewBcryptHasher(4), events, nil, nil,
+		repository.NewMemoryAttributeSchemaRepository(), time.Hour)
+	events.Subscribe(domain.EventUserDeleted, avatars.RemoveOnEvent)
+
+	user, err := domain.NewUser(domain.DefaultTenantID, "Jane", "jane@example.com", "jane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Save(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	var picture bytes.Buffer
+	if err := png.Encode(&picture, image.NewRGBA(image.Rect(0, 0, 40, 30))); err != nil {
+		t.Fatal(err)
+	}
+	uploaded, err := avatars.UploadAvatar(ctx, user.ID.String(), picture.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs.objects) != 2 {
+		t.Fatalf("stored %d images, want 2", len(blobs.objects))
+	}
+
+	if err := userService.DeleteUser(ctx, user.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(blobs.objects) != 0 {
+		t.Fatalf("%d images left after deleting the user", len(blobs.objects))
+	}
+	for _, image := range uploaded.Images {
+		file := path.Base(image.URL)
+		_, err := avatars.GetAvatarImage(ctx, domain.DefaultTenantID.String(), uploaded.ID, file)
+		if !errors.Is(err, ErrAvatarNotFound) {
+			t.Fatalf("GetAvatarImage(%s) err = %v, want %v", file, err, ErrAvatarNotFound)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/mail"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/logging"
+	"log/slog"
+)
+
+const purposeEmailVerification = "email_verification"
+
+// VerifyEmail confirms the address carried by a verification token. A token
+// only matches the address currently awaiting verification, so it cannot be
+// replayed once used or after a newer change was requested.
+func (s *UserService) VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) (_ *dto.UserResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "VerifyEmail", "")
+	defer func() { done(err) }()
+
+	claims, err := s.tokens.Verify(purposeEmailVerification, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(claims.Subject))
+	if err != nil {
+		return nil, err
+	}
+
+	email := domain.Email(claims.Value)
+	if !email.Equal(user.Email) && email == user.EmailToVerify() {
+		// The address may have been claimed since the change was requested.
+		emailExists, err := s.userRepo.ExistsByEmail(ctx, user.TenantID, email)
+		if err != nil {
+			return nil, err
+		}
+		if emailExists {
+			return nil, domain.ErrEmailExists
+		}
+	}
+
+	if err := user.VerifyEmail(email, s.now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	logger.Info("email verified", slog.String(logging.KeyUserID, user.ID.String()), slog.String("email", user.Email.String()))
+
+	return userToResponse(user), nil
+}
+
+func (s *UserService) verificationToken(user *domain.User) (string, error) {
+	return s.tokens.Sign(purposeEmailVerification, user.ID.String(), user.EmailToVerify().String(), s.verificationTTL)
+}
+
+// sendVerification mails a verification token to the address awaiting
+// confirmation. Failures are logged rather than returned because the user
+// change has already been stored.
+func (s *UserService) sendVerification(ctx context.Context, logger *slog.Logger, user *domain.User) {
+	email := user.EmailToVerify()
+	if email == "" {
+		return
+	}
+
+	verificationToken, err := s.verificationToken(user)
+	if err != nil {
+		logger.Error("failed to issue verification token", logging.KeyError, err.Error())
+		return
+	}
+
+	msg := mail.Message{
+		To:       email.String(),
+		Template: "verify_email",
+		Data: map[string]any{
+			"Name":      user.Name,
+			"Token":     verificationToken,
+			"ExpiresIn": s.verificationTTL.String(),
+		},
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		logger.Error("failed to send verification mail", logging.KeyError, err.Error())
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/password"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/application/token"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/infrastructure/repository"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerificationTokens(t *testing.T) {
+	ctx := tenancy.WithTenant(context.Background(), domain.DefaultTenantID)
+	users := repository.NewMemoryUserRepository()
+	svc := NewUserService(users, nil, token.NewSigner([]byte("secret")), password.NewBcryptHasher(4), nil, nil, nil,
+		repository.NewMemoryAttributeSchemaRepository(), time.Hour)
+
+	user, err := domain.NewUser(domain.DefaultTenantID, "Jane", "jane@example.com", "jane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Save(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	verify := func(tok string) error {
+		_, err := svc.VerifyEmail(ctx, dto.VerifyEmailRequest{Token: tok})
+		return err
+	}
+	issue := func(email string) string {
+		t.Helper()
+		if email != "" {
+			user, err = users.GetByID(ctx, domain.DefaultTenantID, user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := user.UpdateEmail(email); err != nil {
+				t.Fatal(err)
+			}
+			if err := users.Update(ctx, user); err != nil {
+				t.Fatal(err)
+			}
+		}
+		tok, err := svc.verificationToken(user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tok
+	}
+
+	signup := issue("")
+	if err := verify(signup); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(signup); !errors.Is(err, domain.ErrEmailNotPending) {
+		t.Fatalf("replayed token: err = %v, want %v", err, domain.ErrEmailNotPending)
+	}
+
+	superseded := issue("jane@work.example")
+	latest := issue("jane@home.example")
+	if err := verify(superseded); !errors.Is(err, domain.ErrEmailNotPending) {
+		t.Fatalf("superseded token: err = %v, want %v", err, domain.ErrEmailNotPending)
+	}
+
+	other, err := domain.NewUser(domain.DefaultTenantID, "Joe", "jane@home.example", "joe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Save(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(latest); !errors.Is(err, domain.ErrEmailExists) {
+		t.Fatalf("claimed address: err = %v, want %v", err, domain.ErrEmailExists)
+	}
+	if err := users.Delete(ctx, domain.DefaultTenantID, other.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(latest); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := users.GetByID(ctx, domain.DefaultTenantID, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Email != "jane@home.example" || !stored.EmailVerified || stored.PendingEmail != "" {
+		t.Fatalf("stored email = %q verified=%t pending=%q", stored.Email, stored.EmailVerified, stored.PendingEmail)
+	}
+}
@@ -0,0 +1,481 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/mail"
+	"ddd-user-service/internal/application/password"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/application/token"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/logging"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const CodeInviterRequired domain.ErrorCode = "inviter_required"
+
+// ErrInviterRequired rejects invitations that no signed-in user sent.
+var ErrInviterRequired = domain.NewUnauthenticatedError(CodeInviterRequired, "invitations must be sent by a signed-in user")
+
+type InvitationDependencies struct {
+	Invitations   domain.InvitationRepository
+	Users         domain.UserRepository
+	Organizations domain.OrganizationRepository
+	Attributes    domain.AttributeSchemaRepository
+	Hasher        password.Hasher
+	Mailer        mail.Mailer
+	// Policy is consulted for the username a new invitee chooses; the
+	// address was chosen by whoever sent the invitation. It may be nil.
+	Policy IdentifierPolicy
+}
+
+type InvitationOptions struct {
+	// TTL is how long an invitation can be accepted after it was last sent.
+	TTL time.Duration
+}
+
+// InvitationService onboards people by email. An invitation carries the
+// role and organization membership the invitee gets, and accepting it
+// creates their account, or attaches to the account that already uses the
+// address instead of creating a second one.
+type InvitationService struct {
+	invitations domain.InvitationRepository
+	userRepo    domain.UserRepository
+	orgs        domain.OrganizationRepository
+	attributes  domain.AttributeSchemaRepository
+	hasher      password.Hasher
+	mailer      mail.Mailer
+	policy      IdentifierPolicy
+	opts        InvitationOptions
+	now         func() time.Time
+	tracer      trace.Tracer
+}
+
+func NewInvitationService(deps InvitationDependencies, opts InvitationOptions) *InvitationService {
+	return &InvitationService{
+		invitations: deps.Invitations,
+		userRepo:    deps.Users,
+		orgs:        deps.Organizations,
+		attributes:  deps.Attributes,
+		hasher:      deps.Hasher,
+		mailer:      deps.Mailer,
+		policy:      deps.Policy,
+		opts:        opts,
+		now:         time.Now,
+		tracer:      otel.Tracer(serviceTracerName),
+	}
+}
+
+func (s *InvitationService) begin(ctx context.Context, op string, userID string) (context.Context, *slog.Logger, func(error)) {
+	return beginOperation(ctx, s.tracer, "InvitationService", op, userID)
+}
+
+// CreateInvitation invites the address and mails it a token. Inviting an
+// address that already has an open invitation to the same organization
+// updates and resends that invitation, and created is false.
+func (s *InvitationService) CreateInvitation(ctx context.Context, invitedBy string, req dto.CreateInvitationRequest) (_ *dto.InvitationResponse, created bool, err error) {
+	ctx, logger, done := s.begin(ctx, "CreateInvitation", invitedBy)
+	defer func() { done(err) }()
+
+	if invitedBy == "" {
+		return nil, false, ErrInviterRequired
+	}
+
+	role, orgID, orgRole, err := parseInvitationGrants(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tenant := tenancy.FromContext(ctx)
+	var org *domain.Organization
+	if orgID != "" {
+		if org, err = s.orgs.Get(ctx, tenant, orgID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	raw, err := token.NewOpaque()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+
+	email, err := domain.ParseEmail(req.Email)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := s.now()
+	inv, err := s.invitations.FindOpen(ctx, tenant, email, orgID)
+	switch {
+	case err == nil:
+		inv.Assign(role, orgID, orgRole)
+		inv.InvitedBy = domain.UserID(invitedBy)
+		if err := inv.Resend(token.Hash(raw), now, s.opts.TTL); err != nil {
+			return nil, false, err
+		}
+		if err := s.invitations.Update(ctx, inv); err != nil {
+			return nil, false, err
+		}
+	case errors.Is(err, domain.ErrInvitationNotFound):
+		inv, err = domain.NewInvitation(tenant, email.String(), role, orgID, orgRole, domain.UserID(invitedBy), token.Hash(raw), now, s.opts.TTL)
+		if err != nil {
+			return nil, false, err
+		}
+		existing, err := s.userRepo.GetByEmail(ctx, tenant, inv.Email)
+		if err == nil {
+			inv.UserID = existing.ID
+		} else if !errors.Is(err, domain.ErrUserNotFound) {
+			return nil, false, err
+		}
+		if err := s.invitations.Save(ctx, inv); err != nil {
+			return nil, false, err
+		}
+		created = true
+	default:
+		return nil, false, err
+	}
+
+	s.sendInvitation(ctx, logger, inv, org, raw)
+
+	logger.Info("invitation sent",
+		slog.String("invitation_id", inv.ID.String()),
+		slog.Bool("existing_account", inv.UserID != ""),
+		slog.Bool("resent", !created),
+	)
+	return s.invitationToResponse(inv), created, nil
+}
+
+func parseInvitationGrants(req dto.CreateInvitationRequest) (domain.Role, domain.OrganizationID, domain.MemberRole, error) {
+	var errs domain.ValidationErrors
+	var role domain.Role
+	var orgRole domain.MemberRole
+	if req.Role != "" {
+		var err error
+		role, err = domain.ParseRole(req.Role)
+		errs.Add(err)
+	}
+	if req.OrganizationRole != "" {
+		var err error
+		orgRole, err = domain.ParseMemberRole(req.OrganizationRole)
+		errs.Add(err)
+	}
+	if err := errs.Err(); err != nil {
+		return "", "", "", err
+	}
+	return role, domain.OrganizationID(strings.TrimSpace(req.OrganizationID)), orgRole, nil
+}
+
+func (s *InvitationService) ListInvitations(ctx context.Context) (_ []*dto.InvitationResponse, err error) {
+	ctx, _, done := s.begin(ctx, "ListInvitations", "")
+	defer func() { done(err) }()
+
+	invitations, err := s.invitations.List(ctx, tenancy.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.InvitationResponse, len(invitations))
+	for i, inv := range invitations {
+		responses[i] = s.invitationToResponse(inv)
+	}
+	return responses, nil
+}
+
+func (s *InvitationService) GetInvitation(ctx context.Context, id string) (_ *dto.InvitationResponse, err error) {
+	ctx, _, done := s.begin(ctx, "GetInvitation", "")
+	defer func() { done(err) }()
+
+	inv, err := s.invitations.Get(ctx, tenancy.FromContext(ctx), domain.InvitationID(id))
+	if err != nil {
+		return nil, err
+	}
+	return s.invitationToResponse(inv), nil
+}
+
+// ResendInvitation mails a new token and restarts the expiry. The previous
+// token stops working.
+func (s *InvitationService) ResendInvitation(ctx context.Context, id string) (_ *dto.InvitationResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "ResendInvitation", "")
+	defer func() { done(err) }()
+
+	inv, err := s.invitations.Get(ctx, tenancy.FromContext(ctx), domain.InvitationID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := token.NewOpaque()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	if err := inv.Resend(token.Hash(raw), s.now(), s.opts.TTL); err != nil {
+		return nil, err
+	}
+	if err := s.invitations.Update(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	var org *domain.Organization
+	if inv.OrganizationID != "" {
+		org, err = s.orgs.Get(ctx, inv.TenantID, inv.OrganizationID)
+		if err != nil && !errors.Is(err, domain.ErrOrganizationNotFound) {
+			return nil, err
+		}
+	}
+	s.sendInvitation(ctx, logger, inv, org, raw)
+
+	logger.Info("invitation resent", slog.String("invitation_id", inv.ID.String()), slog.Int("send_count", inv.SendCount))
+	return s.invitationToResponse(inv), nil
+}
+
+func (s *InvitationService) RevokeInvitation(ctx context.Context, id string) (_ *dto.InvitationResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "RevokeInvitation", "")
+	defer func() { done(err) }()
+
+	inv, err := s.invitations.Get(ctx, tenancy.FromContext(ctx), domain.InvitationID(id))
+	if err != nil {
+		return nil, err
+	}
+	if err := inv.Revoke(s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.invitations.Update(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	logger.Info("invitation revoked", slog.String("invitation_id", inv.ID.String()))
+	return s.invitationToResponse(inv), nil
+}
+
+// AcceptInvitation creates the invitee's account, or attaches to the
+// account that uses the invited address, and applies what the invitation
+// grants. The address counts as verified, since the token was mailed to it.
+// Accepting an accepted invitation again returns the same account with
+// created false, so a retried request is harmless.
+func (s *InvitationService) AcceptInvitation(ctx context.Context, req dto.AcceptInvitationRequest) (_ *dto.UserResponse, created bool, err error) {
+	ctx, logger, done := s.begin(ctx, "AcceptInvitation", "")
+	defer func() { done(err) }()
+
+	inv, err := s.invitations.GetByTokenHash(ctx, tenancy.FromContext(ctx), token.Hash(req.Token))
+	if errors.Is(err, domain.ErrInvitationNotFound) {
+		return nil, false, token.ErrInvalid
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if inv.AcceptedAt != nil {
+		user, err := s.userRepo.GetByID(ctx, inv.TenantID, inv.UserID)
+		if err != nil {
+			return nil, false, err
+		}
+		return userToResponse(user), false, nil
+	}
+
+	now := s.now()
+	if err := inv.CanAccept(now); err != nil {
+		return nil, false, err
+	}
+
+	user, err := s.existingInvitee(ctx, inv)
+	if err != nil {
+		return nil, false, err
+	}
+	if user == nil {
+		if user, err = s.createInvitee(ctx, inv, req, now); err != nil {
+			return nil, false, err
+		}
+		created = true
+	} else if err := s.attachInvitee(ctx, inv, user, now); err != nil {
+		return nil, false, err
+	}
+
+	if err := s.joinOrganization(ctx, logger, inv, user.ID, now); err != nil {
+		return nil, false, err
+	}
+
+	if err := inv.Accept(user.ID, now); err != nil {
+		return nil, false, err
+	}
+	if err := s.invitations.Update(ctx, inv); err != nil {
+		return nil, false, err
+	}
+
+	logger.Info("invitation accepted",
+		slog.String("invitation_id", inv.ID.String()),
+		slog.String(logging.KeyUserID, user.ID.String()),
+		slog.Bool("account_created", created),
+	)
+	return userToResponse(user), created, nil
+}
+
+// existingInvitee returns the account the invitation attaches to, if any:
+// the one it was attached to when sent, or one that has taken the address
+// since.
+func (s *InvitationService) existingInvitee(ctx context.Context, inv *domain.Invitation) (*domain.User, error) {
+	if inv.UserID != "" {
+		user, err := s.userRepo.GetByID(ctx, inv.TenantID, inv.UserID)
+		if err == nil || !errors.Is(err, domain.ErrUserNotFound) {
+			return user, err
+		}
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, inv.TenantID, inv.Email)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (s *InvitationService) createInvitee(ctx context.Context, inv *domain.Invitation, req dto.AcceptInvitationRequest, now time.Time) (*domain.User, error) {
+	newReq := dto.CreateUserRequest{
+		Name:     req.Name,
+		Email:    inv.Email.String(),
+		Username: req.Username,
+		Password: req.Password,
+		Role:     inv.Role.String(),
+	}
+	// Unlike CreateUser, the invitee must choose a password: nobody else
+	// knows the account exists yet.
+	var errs domain.ValidationErrors
+	errs.Add(validateNewUser(newReq))
+	if req.Password == "" {
+		errs.Add(domain.ValidatePassword(req.Password))
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	user, err := newUser(s.hasher, inv.TenantID, newReq, now)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := s.attributes.List(ctx, inv.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := user.SetAttributes(schemas, req.Attributes); err != nil {
+		return nil, err
+	}
+
+	if err := checkIdentifiers(ctx, s.policy, inv.TenantID, "", user.Username, ""); err != nil {
+		return nil, err
+	}
+
+	usernameExists, err := s.userRepo.ExistsByUsername(ctx, inv.TenantID, user.Username)
+	if err != nil {
+		return nil, err
+	}
+	if usernameExists {
+		return nil, domain.ErrUsernameExists
+	}
+	if err := checkUniqueAttributes(ctx, s.userRepo, user, schemas, nil); err != nil {
+		return nil, err
+	}
+	if err := user.VerifyEmail(user.Email, now); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Save(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *InvitationService) attachInvitee(ctx context.Context, inv *domain.Invitation, user *domain.User, now time.Time) error {
+	changed := false
+	// An invitation can promote an existing account but never demote it.
+	if inv.Role.Outranks(user.Role) {
+		user.AssignRole(inv.Role)
+		changed = true
+	}
+	if user.EmailToVerify() == inv.Email {
+		if err := user.VerifyEmail(inv.Email, now); err != nil {
+			return err
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return s.userRepo.Update(ctx, user)
+}
+
+// joinOrganization makes the invitee a member of the invitation's
+// organization. A pending invitation from the organization itself is
+// accepted instead, and existing members are left as they are.
+func (s *InvitationService) joinOrganization(ctx context.Context, logger *slog.Logger, inv *domain.Invitation, userID domain.UserID, now time.Time) error {
+	if inv.OrganizationID == "" {
+		return nil
+	}
+
+	org, err := s.orgs.Get(ctx, inv.TenantID, inv.OrganizationID)
+	if errors.Is(err, domain.ErrOrganizationNotFound) {
+		logger.Warn("invited organization no longer exists", slog.String("organization_id", inv.OrganizationID.String()))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	membership, exists := org.Membership(userID)
+	switch {
+	case !exists:
+		err = org.AddMember(userID, inv.OrganizationRole, now)
+	case membership.Invited:
+		err = org.AcceptInvitation(userID, now)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.orgs.Update(ctx, org)
+}
+
+func (s *InvitationService) sendInvitation(ctx context.Context, logger *slog.Logger, inv *domain.Invitation, org *domain.Organization, raw string) {
+	data := map[string]any{
+		"Email":           inv.Email.String(),
+		"Token":           raw,
+		"ExpiresIn":       s.opts.TTL.String(),
+		"ExistingAccount": inv.UserID != "",
+	}
+	if org != nil {
+		data["Organization"] = org.Name
+	}
+
+	msg := mail.Message{
+		To:       inv.Email.String(),
+		Template: "invitation",
+		Data:     data,
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		// The invitation is stored either way and can be resent.
+		logger.Error("failed to send invitation mail", logging.KeyError, err.Error())
+	}
+}
+
+func (s *InvitationService) invitationToResponse(inv *domain.Invitation) *dto.InvitationResponse {
+	return &dto.InvitationResponse{
+		ID:               inv.ID.String(),
+		Email:            inv.Email.String(),
+		Role:             inv.Role.String(),
+		OrganizationID:   inv.OrganizationID.String(),
+		OrganizationRole: inv.OrganizationRole.String(),
+		Status:           string(inv.Status(s.now())),
+		InvitedBy:        inv.InvitedBy.String(),
+		UserID:           inv.UserID.String(),
+		SendCount:        inv.SendCount,
+		CreatedAt:        inv.CreatedAt,
+		SentAt:           inv.SentAt,
+		ExpiresAt:        inv.ExpiresAt,
+		AcceptedAt:       inv.AcceptedAt,
+		RevokedAt:        inv.RevokedAt,
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/password"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/application/token"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/infrastructure/repository"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAcceptInvitationChecksAttributes(t *testing.T) {
+	ctx := tenancy.WithTenant(context.Background(), domain.DefaultTenantID)
+	now := time.Now()
+
+	attributes := repository.NewMemoryAttributeSchemaRepository()
+	schema, err := domain.NewAttributeSchema(domain.DefaultTenantID, "employee_no", "string", true, true, "", nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := attributes.Save(ctx, schema); err != nil {
+		t.Fatal(err)
+	}
+
+	users := repository.NewMemoryUserRepository()
+	holder, err := domain.NewUser(domain.DefaultTenantID, "Ann", "ann@example.com", "ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	holder.Attributes = map[string]any{"employee_no": "E1"}
+	if err := users.Save(ctx, holder); err != nil {
+		t.Fatal(err)
+	}
+
+	invitations := repository.NewMemoryInvitationRepository()
+	inv, err := domain.NewInvitation(domain.DefaultTenantID, "jane@example.com", domain.RoleUser, "", "", holder.ID, token.Hash("invite"), now, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := invitations.Save(ctx, inv); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewInvitationService(InvitationDependencies{
+		Invitations: invitations,
+		Users:       users,
+		Attributes:  attributes,
+		Hasher:      password.NewBcryptHasher(4),
+	}, InvitationOptions{TTL: time.Hour})
+
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		wantErr    error
+	}{
+		{"required attribute missing", nil, domain.NewValidationError(domain.CodeAttributeRequired, "", "")},
+		{"unique value taken", map[string]any{"employee_no": "E1"}, domain.ErrAttributeValueExists},
+		{"unknown attribute", map[string]any{"employee_no": "E2", "shoe_size": 42}, domain.NewValidationError(domain.CodeAttributeUnknown, "", "")},
+		{"valid attributes", map[string]any{"employee_no": "E2"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, created, err := svc.AcceptInvitation(ctx, dto.AcceptInvitationRequest{
+				Token:      "invite",
+				Name:       "Jane",
+				Username:   "jane",
+				Password:   "a-strong-password",
+				Attributes: tt.attributes,
+			})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !created || resp.Attributes["employee_no"] != "E2" {
+				t.Fatalf("created = %v, attributes = %v", created, resp.Attributes)
+			}
+		})
+	}
+}
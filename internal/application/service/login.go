@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/lockout"
+	"ddd-user-service/internal/application/mfa"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/application/token"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/logging"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+)
+
+const purposeMFAChallenge = "mfa_challenge"
+
+const (
+	CodeInvalidCredentials domain.ErrorCode = "invalid_credentials"
+	CodeMFACodeInvalid     domain.ErrorCode = "mfa_code_invalid"
+	CodeAccessTokenInvalid domain.ErrorCode = "access_token_invalid"
+	CodeMFACodeRequired    domain.ErrorCode = "mfa_code_required"
+	CodeAccountLocked      domain.ErrorCode = "account_locked"
+	CodeLoginThrottled     domain.ErrorCode = "login_throttled"
+)
+
+var (
+	ErrInvalidCredentials = domain.NewUnauthenticatedError(CodeInvalidCredentials, "the login or password is incorrect")
+	ErrMFACodeInvalid     = domain.NewUnauthenticatedError(CodeMFACodeInvalid, "the authentication code is incorrect")
+	ErrAccessTokenInvalid = domain.NewUnauthenticatedError(CodeAccessTokenInvalid, "the access token is invalid or has expired")
+	ErrMFACodeRequired    = domain.NewValidationError(CodeMFACodeRequired, "code", "either code or recovery_code is required")
+	ErrAccountLocked      = domain.NewTooManyAttemptsError(CodeAccountLocked, "too many failed attempts; try again later")
+	ErrLoginThrottled     = domain.NewTooManyAttemptsError(CodeLoginThrottled, "too many failed attempts; wait before trying again")
+)
+
+// Login checks a password. Users with a second factor get a short-lived MFA
+// challenge to complete with CompleteMFALogin; users whose role requires MFA
+// but who have not enrolled get a token that only allows enrollment.
+func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest, client dto.ClientInfo) (_ *dto.LoginResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "Login", "")
+	defer func() { done(err) }()
+
+	user, err := s.findByLogin(ctx, req.Login)
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, err
+	}
+
+	// Unknown logins are counted too, under their own key, so that lockout
+	// responses do not reveal which accounts exist. The same login may
+	// exist in several tenants.
+	account := "login:" + tenancy.FromContext(ctx).String() + ":" + normalizeLogin(req.Login)
+	if user != nil {
+		account = user.ID.String()
+	}
+	if err := s.checkLockout(ctx, account, client); err != nil {
+		return nil, err
+	}
+
+	if user == nil || !user.HasPassword() || user.Role == domain.RoleService {
+		// Spend the same time as a real check so response times do not
+		// reveal which logins exist.
+		s.hasher.Compare(s.dummyPasswordHash(), req.Password)
+		return nil, s.loginFailed(ctx, logger, user, account, client, ErrInvalidCredentials)
+	}
+
+	ok, err := s.hasher.Compare(user.PasswordHash, req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check password: %w", err)
+	}
+	if !ok {
+		return nil, s.loginFailed(ctx, logger, user, account, client, ErrInvalidCredentials)
+	}
+	if user.Suspended() {
+		return nil, domain.ErrUserSuspended
+	}
+
+	switch {
+	case user.MFAEnabled():
+		challenge, err := s.tokens.Sign(purposeMFAChallenge, user.ID.String(), "", s.opts.MFAChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("login requires second factor", slog.String(logging.KeyUserID, user.ID.String()))
+		return &dto.LoginResponse{
+			Status:     dto.LoginStatusMFARequired,
+			MFAToken:   challenge,
+			MFAMethods: []string{"totp", "recovery_code"},
+		}, nil
+	case s.mfaRequired(user.Role):
+		logger.Info("login restricted until MFA enrollment", slog.String(logging.KeyUserID, user.ID.String()))
+		return s.startSession(ctx, user, dto.ScopeMFAEnrollment, dto.LoginStatusMFAEnrollmentRequired, client)
+	}
+
+	logger.Info("login succeeded", slog.String(logging.KeyUserID, user.ID.String()))
+	return s.startSession(ctx, user, dto.ScopeFull, dto.LoginStatusAuthenticated, client)
+}
+
+// CompleteMFALogin finishes a login started by Login with a TOTP code or a
+// recovery code. Each code is accepted once.
+func (s *AuthService) CompleteMFALogin(ctx context.Context, req dto.LoginMFARequest, client dto.ClientInfo) (_ *dto.LoginResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "CompleteMFALogin", "")
+	defer func() { done(err) }()
+
+	if req.Code == "" && req.RecoveryCode == "" {
+		return nil, ErrMFACodeRequired
+	}
+
+	claims, err := s.tokens.Verify(purposeMFAChallenge, req.MFAToken)
+	if err != nil {
+		var tokenErr *domain.Error
+		if errors.As(err, &tokenErr) {
+			return nil, tokenErr.WithField("mfa_token")
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(claims.Subject))
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, token.ErrInvalid.WithField("mfa_token")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled() {
+		return nil, domain.ErrMFANotEnabled
+	}
+	if err := s.checkLockout(ctx, user.ID.String(), client); err != nil {
+		return nil, err
+	}
+
+	method := "totp"
+	if req.Code != "" {
+		step, ok := mfa.Validate(user.TOTP.Secret, req.Code, s.now())
+		if !ok {
+			return nil, s.loginFailed(ctx, logger, user, user.ID.String(), client, ErrMFACodeInvalid)
+		}
+		if err := user.UseTOTPStep(step); err != nil {
+			return nil, err
+		}
+	} else {
+		method = "recovery_code"
+		if !user.UseRecoveryCode(mfa.HashRecoveryCode(req.RecoveryCode)) {
+			return nil, s.loginFailed(ctx, logger, user, user.ID.String(), client, ErrMFACodeInvalid)
+		}
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	logger.Info("login succeeded",
+		slog.String(logging.KeyUserID, user.ID.String()),
+		slog.String("mfa_method", method),
+		slog.Int("recovery_codes_left", len(user.RecoveryCodes)),
+	)
+	return s.startSession(ctx, user, dto.ScopeFull, dto.LoginStatusAuthenticated, client)
+}
+
+// startSession signs the user in and returns the session's access token.
+// Failed attempts are forgotten only here, once sign-in is complete, so a
+// known password does not buy more guesses at the second factor.
+func (s *AuthService) startSession(ctx context.Context, user *domain.User, scope, status string, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	accessToken, session, err := s.sessions.Start(ctx, user, scope, client)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.lockout.Reset(ctx, user.ID.String()); err != nil {
+		return nil, err
+	}
+
+	expiresAt := session.ExpiresAt.Truncate(time.Second)
+	return &dto.LoginResponse{
+		Status:      status,
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresAt:   &expiresAt,
+	}, nil
+}
+
+// checkLockout refuses attempts on a locked account or from a locked
+// address, and attempts made before the progressive delay has passed.
+func (s *AuthService) checkLockout(ctx context.Context, account string, client dto.ClientInfo) error {
+	status, err := s.lockout.Check(ctx, account, client.IPAddress)
+	if err != nil {
+		return err
+	}
+	if !status.Blocked() {
+		return nil
+	}
+
+	blocked := ErrLoginThrottled
+	if status.Reason == lockout.ReasonLocked {
+		blocked = ErrAccountLocked
+	}
+	retryAfter := int(math.Ceil(status.RetryAfter.Seconds()))
+	return blocked.WithParams(map[string]any{"retry_after": retryAfter})
+}
+
+// loginFailed counts a failed attempt and returns failure. When the attempt
+// locks a known user's account, AccountLocked is published.
+func (s *AuthService) loginFailed(ctx context.Context, logger *slog.Logger, user *domain.User, account string, client dto.ClientInfo, failure error) error {
+	lockedUntil, err := s.lockout.RecordFailure(ctx, account, client.IPAddress)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return failure
+	}
+
+	logger.Info("login failed", slog.String(logging.KeyUserID, user.ID.String()))
+	if lockedUntil != nil {
+		logger.Warn("account locked after repeated failures",
+			slog.String(logging.KeyUserID, user.ID.String()),
+			slog.Time("locked_until", *lockedUntil),
+		)
+		s.events.Publish(ctx, domain.AccountLocked{UserID: user.ID, LockedUntil: *lockedUntil, OccurredAt: s.now().UTC()})
+	}
+	return failure
+}
+
+// UnlockUser lets an administrator clear a lockout before it expires.
+func (s *AuthService) UnlockUser(ctx context.Context, id string) (err error) {
+	ctx, logger, done := s.begin(ctx, "UnlockUser", id)
+	defer func() { done(err) }()
+
+	if _, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id)); err != nil {
+		return err
+	}
+	if err := s.lockout.Reset(ctx, id); err != nil {
+		return err
+	}
+
+	logger.Info("user unlocked")
+	return nil
+}
+
+// normalizeLogin returns the form an email or username is compared by, so
+// every spelling of a login counts towards the same lockout.
+func normalizeLogin(login string) string {
+	if strings.Contains(login, "@") {
+		if email, err := domain.ParseEmail(login); err == nil {
+			return email.Key()
+		}
+		return strings.ToLower(strings.TrimSpace(login))
+	}
+	return domain.NormalizeUsername(login).String()
+}
+
+func (s *AuthService) findByLogin(ctx context.Context, login string) (*domain.User, error) {
+	if strings.Contains(login, "@") {
+		email, err := domain.ParseEmail(login)
+		if err != nil {
+			return nil, domain.ErrUserNotFound
+		}
+		return s.userRepo.GetByEmail(ctx, tenancy.FromContext(ctx), email)
+	}
+	return s.userRepo.GetByUsername(ctx, tenancy.FromContext(ctx), domain.NormalizeUsername(login))
+}
+
+// dummyPasswordHash is a hash of a random password, compared against when
+// there is no real one so failed logins take the same time either way.
+func (s *AuthService) dummyPasswordHash() string {
+	s.dummyHashOnce.Do(func() {
+		random, _ := token.NewOpaque()
+		s.dummyHash, _ = s.hasher.Hash(random)
+	})
+	return s.dummyHash
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/mfa"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/domain"
+	"fmt"
+)
+
+// StartTOTPEnrollment generates a new authenticator secret for the user. It
+// is not used for logins until ConfirmTOTPEnrollment succeeds.
+func (s *AuthService) StartTOTPEnrollment(ctx context.Context, id string) (_ *dto.TOTPEnrollmentResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "StartTOTPEnrollment", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := mfa.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+	if err := user.StartTOTPEnrollment(secret); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	logger.Info("TOTP enrollment started")
+
+	return &dto.TOTPEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: mfa.ProvisioningURI(s.opts.TOTPIssuer, user.Email.String(), secret),
+	}, nil
+}
+
+// ConfirmTOTPEnrollment enables the pending authenticator with a first code
+// from it and returns recovery codes, which are only ever shown here.
+func (s *AuthService) ConfirmTOTPEnrollment(ctx context.Context, id string, req dto.ConfirmTOTPRequest) (_ *dto.RecoveryCodesResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "ConfirmTOTPEnrollment", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTP == nil || user.TOTP.Confirmed {
+		return nil, domain.ErrMFANotPending
+	}
+
+	step, ok := mfa.Validate(user.TOTP.Secret, req.Code, s.now())
+	if !ok {
+		return nil, ErrMFACodeInvalid
+	}
+
+	codes, hashes, err := mfa.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+	if err := user.ConfirmTOTP(step, s.now(), hashes); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	logger.Info("TOTP enrollment confirmed")
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code, used or not.
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, id string) (_ *dto.RecoveryCodesResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "RegenerateRecoveryCodes", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := mfa.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+	if err := user.ReplaceRecoveryCodes(hashes); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	logger.Info("recovery codes regenerated")
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/event"
+	"ddd-user-service/internal/application/lockout"
+	"ddd-user-service/internal/application/mfa"
+	"ddd-user-service/internal/application/password"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/application/token"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/infrastructure/repository"
+	"errors"
+	"testing"
+	"time"
+)
+
+const (
+	testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	testPassword   = "correct horse battery"
+)
+
+// mfaLoginFixture is an AuthService whose clock is fixed at now, with one
+// user who has confirmed TOTP and the given recovery codes.
+type mfaLoginFixture struct {
+	ctx           context.Context
+	auth          *AuthService
+	now           time.Time
+	recoveryCodes []string
+}
+
+func newMFALoginFixture(t *testing.T) *mfaLoginFixture {
+	t.Helper()
+	ctx := tenancy.WithTenant(context.Background(), domain.DefaultTenantID)
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	hasher := password.NewBcryptHasher(4)
+
+	users := repository.NewMemoryUserRepository()
+	user, err := domain.NewUser(domain.DefaultTenantID, "Jane Doe", "jane@example.com", "jane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := hasher.Hash(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.SetPassword(hash, now)
+	codes, hashes, err := mfa.GenerateRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := user.StartTOTPEnrollment(testTOTPSecret); err != nil {
+		t.Fatal(err)
+	}
+	// Enrolled a while ago, so every step around now is unused.
+	if err := user.ConfirmTOTP(mfa.Step(now)-10, now.Add(-5*time.Minute), hashes); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Save(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	auth := NewAuthService(AuthDependencies{
+		Users:    users,
+		Sessions: NewSessionService(repository.NewMemorySessionRepository(), users, SessionOptions{IdleTimeout: time.Hour, AbsoluteTimeout: time.Hour}),
+		Lockout:  lockout.NewGuard(repository.NewMemoryLoginAttemptStore(), lockout.Policy{}, lockout.Policy{}),
+		Hasher:   hasher,
+		Tokens:   token.NewSigner([]byte("test-secret")),
+		Events:   event.NewDispatcher(),
+	}, AuthOptions{MFAChallengeTTL: 5 * time.Minute})
+	auth.now = func() time.Time { return now }
+
+	return &mfaLoginFixture{ctx: ctx, auth: auth, now: now, recoveryCodes: codes}
+}
+
+// challenge signs in with the password and returns the MFA token.
+func (f *mfaLoginFixture) challenge(t *testing.T) string {
+	t.Helper()
+	resp, err := f.auth.Login(f.ctx, dto.LoginRequest{Login: "jane", Password: testPassword}, dto.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != dto.LoginStatusMFARequired || resp.MFAToken == "" {
+		t.Fatalf("login status = %q, want %q with a token", resp.Status, dto.LoginStatusMFARequired)
+	}
+	return resp.MFAToken
+}
+
+func (f *mfaLoginFixture) code(t *testing.T, offset int64) string {
+	t.Helper()
+	code, err := mfa.Code(testTOTPSecret, mfa.Step(f.now)+offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestCompleteMFALogin(t *testing.T) {
+	tests := []struct {
+		name string
+		// attempts are made in order with fresh challenges; all but the
+		// last must succeed.
+		attempts func(f *mfaLoginFixture, t *testing.T) []dto.LoginMFARequest
+		wantErr  error
+	}{
+		{
+			name: "current code",
+			attempts: func(f *mfaLoginFixture, t *testing.T) []dto.LoginMFARequest {
+				return []dto.LoginMFARequest{{Code: f.code(t, 0)}}
+			},
+		},
+		{
+			name: "device clock one step slow",
+			attempts: func(f *mfaLoginFixture, t *testing.T) []dto.LoginMFARequest {
+				return []dto.LoginMFARequest{{Code: f.code(t, -1)}}
+			},
+		},
+		{
+			name: "device clock one step fast",
+			attempts: func(f *mfaLoginFixture, t *testing.T) []dto.LoginMFARequest {
+				return []dto.LoginMFARequest{{Code: f.code(t, 1)}}
+			},
+		},
+		{
+			name: "device clock two steps off",
+			attempts: func(f *mfaLoginFixture, t *testing.T) []dto.LoginMFARequest {
+				return []dto.LoginMFARequest{{Code: f.code(t, -2)}}
+			},
+			wantErr: ErrMFACodeInvalid,
+		},
+		{
+			name: "code replayed",
+			attempts: func(f *mfaLoginFixture, t *testing.T) []dto.LoginMFARequest {
+				return []dto.LoginMFARequest{{Code: f.code(t, 0)}, {Code: f.code(t, 0)}}
+			},
+			wantErr: domain.ErrMFACodeReused,
+		},
+		{
+			name: "earlier step after a later one",
+			attempts: func(f *mfaLoginFixture, t *testing.T) []dto.LoginMFARequest {
+				return []dto.LoginMFARequest{{Code: f.code(t, 1)}, {Code: f.code(t, 0)}}
+			},
+			wantErr: domain.ErrMFACodeReused,
+		},
+		{
+			name: "recovery code",
+			attempts: func(f *mfaLoginFixture, t *testing.T) []dto.LoginMFARequest {
+				return []dto.LoginMFARequest{{RecoveryCode: f.recoveryCodes[0]}}
+			},
+		},
+		{
+			name: "recovery code used twice",
+			attempts: func(f *mfaLoginFixture, t *testing.T) []dto.LoginMFARequest {
+				return []dto.LoginMFARequest{{RecoveryCode: f.recoveryCodes[0]}, {RecoveryCode: f.recoveryCodes[0]}}
+			},
+			wantErr: ErrMFACodeInvalid,
+		},
+		{
+			name: "no code",
+			attempts: func(*mfaLoginFixture, *testing.T) []dto.LoginMFARequest {
+				return []dto.LoginMFARequest{{}}
+			},
+			wantErr: ErrMFACodeRequired,
+		},
+		{
+			name: "forged challenge",
+			attempts: func(f *mfaLoginFixture, t *testing.T) []dto.LoginMFARequest {
+				return []dto.LoginMFARequest{{MFAToken: "forged.token", Code: f.code(t, 0)}}
+			},
+			wantErr: token.ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newMFALoginFixture(t)
+			attempts := tt.attempts(f, t)
+			for i, req := range attempts {
+				if req.MFAToken == "" {
+					req.MFAToken = f.challenge(t)
+				}
+				resp, err := f.auth.CompleteMFALogin(f.ctx, req, dto.ClientInfo{})
+
+				if i < len(attempts)-1 || tt.wantErr == nil {
+					if err != nil {
+						t.Fatalf("attempt %d: unexpected error: %v", i+1, err)
+					}
+					if resp.Status != dto.LoginStatusAuthenticated || resp.AccessToken == "" {
+						t.Fatalf("attempt %d: status = %q, want an authenticated session", i+1, resp.Status)
+					}
+					continue
+				}
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("attempt %d: err = %v, want %v", i+1, err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,529 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/domain"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LastOwnerPolicy decides what deleting a user does to the organizations
+// they are the last owner of.
+type LastOwnerPolicy string
+
+const (
+	// LastOwnerBlock refuses to delete the user until ownership has been
+	// transferred or the organizations deleted.
+	LastOwnerBlock LastOwnerPolicy = "block"
+	// LastOwnerCascade deletes those organizations along with the user.
+	LastOwnerCascade LastOwnerPolicy = "cascade"
+)
+
+// maxOrganizationUpdateAttempts bounds the retries when removing a deleted
+// user races with other changes to an organization.
+const maxOrganizationUpdateAttempts = 5
+
+type OrganizationOptions struct {
+	LastOwnerPolicy LastOwnerPolicy
+}
+
+// OrganizationService manages organizations, their members and teams.
+// Whoever creates an organization owns it. Owners and admins manage
+// members and teams, but only owners grant or take away ownership, and
+// service administrators may do everything owners can.
+type OrganizationService struct {
+	orgs     domain.OrganizationRepository
+	userRepo domain.UserRepository
+	opts     OrganizationOptions
+	now      func() time.Time
+	tracer   trace.Tracer
+}
+
+func NewOrganizationService(orgs domain.OrganizationRepository, userRepo domain.UserRepository, opts OrganizationOptions) *OrganizationService {
+	return &OrganizationService{
+		orgs:     orgs,
+		userRepo: userRepo,
+		opts:     opts,
+		now:      time.Now,
+		tracer:   otel.Tracer(serviceTracerName),
+	}
+}
+
+func (s *OrganizationService) begin(ctx context.Context, op string, userID string) (context.Context, *slog.Logger, func(error)) {
+	return beginOperation(ctx, s.tracer, "OrganizationService", op, userID)
+}
+
+func (s *OrganizationService) CreateOrganization(ctx context.Context, actor *dto.Principal, req dto.CreateOrganizationRequest) (_ *dto.OrganizationResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "CreateOrganization", actor.UserID)
+	defer func() { done(err) }()
+
+	org, err := domain.NewOrganization(tenancy.FromContext(ctx), req.Name, domain.UserID(actor.UserID), s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.orgs.Save(ctx, org); err != nil {
+		return nil, err
+	}
+
+	logger.Info("organization created", slog.String("organization_id", org.ID.String()))
+	return organizationToResponse(org), nil
+}
+
+func (s *OrganizationService) GetOrganization(ctx context.Context, actor *dto.Principal, orgID string) (_ *dto.OrganizationResponse, err error) {
+	ctx, _, done := s.begin(ctx, "GetOrganization", actor.UserID)
+	defer func() { done(err) }()
+
+	org, err := s.orgs.Get(ctx, tenancy.FromContext(ctx), domain.OrganizationID(orgID))
+	if err != nil {
+		return nil, err
+	}
+	// Invitees may look at the organization before accepting.
+	if _, member := org.Membership(domain.UserID(actor.UserID)); !member && actor.Role != domain.RoleAdmin.String() {
+		return nil, domain.ErrOrganizationNotFound
+	}
+
+	return organizationToResponse(org), nil
+}
+
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, actor *dto.Principal, orgID string) (err error) {
+	ctx, logger, done := s.begin(ctx, "DeleteOrganization", actor.UserID)
+	defer func() { done(err) }()
+
+	org, err := s.authorize(ctx, actor, orgID, domain.MemberRoleOwner)
+	if err != nil {
+		return err
+	}
+	if err := s.orgs.Delete(ctx, org.TenantID, org.ID); err != nil {
+		return err
+	}
+
+	logger.Info("organization deleted", slog.String("organization_id", orgID))
+	return nil
+}
+
+// AddMember makes an existing user of the tenant a member straight away.
+func (s *OrganizationService) AddMember(ctx context.Context, actor *dto.Principal, orgID string, req dto.AddMemberRequest) (_ *dto.MemberResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "AddMember", actor.UserID)
+	defer func() { done(err) }()
+
+	org, userID, role, err := s.prepareNewMember(ctx, actor, orgID, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := org.AddMember(userID, role, s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.orgs.Update(ctx, org); err != nil {
+		return nil, err
+	}
+
+	logger.Info("member added", slog.String("organization_id", orgID), slog.String("member_id", req.UserID), slog.String("role", role.String()))
+	membership, _ := org.Membership(userID)
+	return membershipToResponse(membership), nil
+}
+
+// InviteMember offers an existing user of the tenant a membership, which
+// only takes effect once they accept it.
+func (s *OrganizationService) InviteMember(ctx context.Context, actor *dto.Principal, orgID string, req dto.AddMemberRequest) (_ *dto.MemberResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "InviteMember", actor.UserID)
+	defer func() { done(err) }()
+
+	org, userID, role, err := s.prepareNewMember(ctx, actor, orgID, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := org.Invite(userID, role, domain.UserID(actor.UserID), s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.orgs.Update(ctx, org); err != nil {
+		return nil, err
+	}
+
+	logger.Info("member invited", slog.String("organization_id", orgID), slog.String("member_id", req.UserID), slog.String("role", role.String()))
+	membership, _ := org.Membership(userID)
+	return membershipToResponse(membership), nil
+}
+
+// AcceptInvitation lets the caller accept their own invitation.
+func (s *OrganizationService) AcceptInvitation(ctx context.Context, actor *dto.Principal, orgID string) (_ *dto.MemberResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "AcceptInvitation", actor.UserID)
+	defer func() { done(err) }()
+
+	org, err := s.orgs.Get(ctx, tenancy.FromContext(ctx), domain.OrganizationID(orgID))
+	if err != nil {
+		return nil, err
+	}
+	userID := domain.UserID(actor.UserID)
+	if _, member := org.Membership(userID); !member {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	if err := org.AcceptInvitation(userID, s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.orgs.Update(ctx, org); err != nil {
+		return nil, err
+	}
+
+	logger.Info("invitation accepted", slog.String("organization_id", orgID))
+	membership, _ := org.Membership(userID)
+	return membershipToResponse(membership), nil
+}
+
+func (s *OrganizationService) ChangeMemberRole(ctx context.Context, actor *dto.Principal, orgID, memberID string, req dto.ChangeMemberRoleRequest) (_ *dto.MemberResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "ChangeMemberRole", actor.UserID)
+	defer func() { done(err) }()
+
+	role, err := domain.ParseMemberRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+	org, err := s.authorize(ctx, actor, orgID, domain.MemberRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	userID := domain.UserID(memberID)
+	membership, ok := org.Membership(userID)
+	if !ok {
+		return nil, domain.ErrMemberNotFound
+	}
+	if err := s.requireOwnerFor(org, actor, membership.Role, role); err != nil {
+		return nil, err
+	}
+	previous := membership.Role
+	if err := org.ChangeRole(userID, role); err != nil {
+		return nil, err
+	}
+	if err := s.orgs.Update(ctx, org); err != nil {
+		return nil, err
+	}
+
+	logger.Info("member role changed",
+		slog.String("organization_id", orgID),
+		slog.String("member_id", memberID),
+		slog.String("from", previous.String()),
+		slog.String("to", role.String()),
+	)
+	return membershipToResponse(membership), nil
+}
+
+// RemoveMember removes a member or withdraws an invitation. Members may
+// always remove themselves, which also declines an invitation.
+func (s *OrganizationService) RemoveMember(ctx context.Context, actor *dto.Principal, orgID, memberID string) (err error) {
+	ctx, logger, done := s.begin(ctx, "RemoveMember", actor.UserID)
+	defer func() { done(err) }()
+
+	var org *domain.Organization
+	userID := domain.UserID(memberID)
+	if memberID == actor.UserID {
+		org, err = s.orgs.Get(ctx, tenancy.FromContext(ctx), domain.OrganizationID(orgID))
+		if err == nil {
+			if _, member := org.Membership(userID); !member {
+				err = domain.ErrOrganizationNotFound
+			}
+		}
+	} else {
+		org, err = s.authorize(ctx, actor, orgID, domain.MemberRoleAdmin)
+		if err == nil {
+			if membership, ok := org.Membership(userID); ok {
+				err = s.requireOwnerFor(org, actor, membership.Role)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := org.RemoveMember(userID); err != nil {
+		return err
+	}
+	if err := s.orgs.Update(ctx, org); err != nil {
+		return err
+	}
+
+	logger.Info("member removed", slog.String("organization_id", orgID), slog.String("member_id", memberID))
+	return nil
+}
+
+func (s *OrganizationService) CreateTeam(ctx context.Context, actor *dto.Principal, orgID string, req dto.CreateTeamRequest) (_ *dto.TeamResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "CreateTeam", actor.UserID)
+	defer func() { done(err) }()
+
+	org, err := s.authorize(ctx, actor, orgID, domain.MemberRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	team, err := org.AddTeam(req.Name, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.orgs.Update(ctx, org); err != nil {
+		return nil, err
+	}
+
+	logger.Info("team created", slog.String("organization_id", orgID), slog.String("team_id", team.ID.String()))
+	return teamToResponse(team), nil
+}
+
+func (s *OrganizationService) DeleteTeam(ctx context.Context, actor *dto.Principal, orgID, teamID string) (err error) {
+	return s.updateTeams(ctx, actor, "DeleteTeam", orgID, func(org *domain.Organization) error {
+		return org.RemoveTeam(domain.TeamID(teamID))
+	})
+}
+
+func (s *OrganizationService) AddTeamMember(ctx context.Context, actor *dto.Principal, orgID, teamID, memberID string) (err error) {
+	return s.updateTeams(ctx, actor, "AddTeamMember", orgID, func(org *domain.Organization) error {
+		return org.AddTeamMember(domain.TeamID(teamID), domain.UserID(memberID))
+	})
+}
+
+func (s *OrganizationService) RemoveTeamMember(ctx context.Context, actor *dto.Principal, orgID, teamID, memberID string) (err error) {
+	return s.updateTeams(ctx, actor, "RemoveTeamMember", orgID, func(org *domain.Organization) error {
+		return org.RemoveTeamMember(domain.TeamID(teamID), domain.UserID(memberID))
+	})
+}
+
+// ListUserOrganizations returns the organizations the user belongs to or is
+// invited to.
+func (s *OrganizationService) ListUserOrganizations(ctx context.Context, userID string) (_ []*dto.UserOrganizationResponse, err error) {
+	ctx, _, done := s.begin(ctx, "ListUserOrganizations", userID)
+	defer func() { done(err) }()
+
+	tenant := tenancy.FromContext(ctx)
+	if _, err := s.userRepo.GetByID(ctx, tenant, domain.UserID(userID)); err != nil {
+		return nil, err
+	}
+	orgs, err := s.orgs.ListByMember(ctx, tenant, domain.UserID(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.UserOrganizationResponse, 0, len(orgs))
+	for _, org := range orgs {
+		membership, ok := org.Membership(domain.UserID(userID))
+		if !ok {
+			continue
+		}
+		responses = append(responses, &dto.UserOrganizationResponse{
+			ID:     org.ID.String(),
+			Name:   org.Name,
+			Role:   membership.Role.String(),
+			Status: membershipStatus(membership),
+		})
+	}
+	return responses, nil
+}
+
+// CheckUserDeletion refuses to delete the last owner of an organization
+// under LastOwnerBlock.
+func (s *OrganizationService) CheckUserDeletion(ctx context.Context, user *domain.User) error {
+	if s.opts.LastOwnerPolicy == LastOwnerCascade {
+		return nil
+	}
+
+	orgs, err := s.orgs.ListByMember(ctx, user.TenantID, user.ID)
+	if err != nil {
+		return err
+	}
+	var owned []string
+	for _, org := range orgs {
+		if org.IsLastOwner(user.ID) {
+			owned = append(owned, org.ID.String())
+		}
+	}
+	if len(owned) > 0 {
+		return domain.ErrLastOwner.
+			WithMessage("the user is the last owner of an organization; transfer ownership or delete it first").
+			WithParams(map[string]any{"organizations": owned})
+	}
+	return nil
+}
+
+// RemoveUserOnEvent is an event handler that takes a deleted user out of
+// every organization, and deletes the organizations they were the last
+// owner of.
+func (s *OrganizationService) RemoveUserOnEvent(ctx context.Context, e domain.Event) (err error) {
+	deleted, ok := e.(domain.UserDeleted)
+	if !ok {
+		return fmt.Errorf("unexpected event %s", e.EventName())
+	}
+
+	ctx, logger, done := s.begin(ctx, "RemoveUserOnEvent", deleted.UserID.String())
+	defer func() { done(err) }()
+
+	orgs, err := s.orgs.ListByMember(ctx, deleted.TenantID, deleted.UserID)
+	if err != nil {
+		return err
+	}
+	for _, org := range orgs {
+		if err := s.removeDeletedUser(ctx, logger, org, deleted.UserID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeDeletedUser reloads the organization and tries again when another
+// request changed it first, since nobody would retry on the user's behalf.
+func (s *OrganizationService) removeDeletedUser(ctx context.Context, logger *slog.Logger, org *domain.Organization, userID domain.UserID) error {
+	for attempt := 1; ; attempt++ {
+		if org.IsLastOwner(userID) {
+			err := s.orgs.Delete(ctx, org.TenantID, org.ID)
+			if err != nil && !errors.Is(err, domain.ErrOrganizationNotFound) {
+				return err
+			}
+			logger.Info("organization deleted with its last owner", slog.String("organization_id", org.ID.String()))
+			return nil
+		}
+		if err := org.RemoveMember(userID); err != nil {
+			return err
+		}
+		err := s.orgs.Update(ctx, org)
+		if !errors.Is(err, domain.ErrOrganizationConflict) || attempt == maxOrganizationUpdateAttempts {
+			return err
+		}
+
+		if org, err = s.orgs.Get(ctx, org.TenantID, org.ID); err != nil {
+			if errors.Is(err, domain.ErrOrganizationNotFound) {
+				return nil
+			}
+			return err
+		}
+		if _, member := org.Membership(userID); !member {
+			return nil
+		}
+	}
+}
+
+// authorize loads the organization and checks that the caller's role in it
+// is at least needed. Callers who are not members get
+// ErrOrganizationNotFound, so other organizations stay hidden.
+func (s *OrganizationService) authorize(ctx context.Context, actor *dto.Principal, orgID string, needed domain.MemberRole) (*domain.Organization, error) {
+	org, err := s.orgs.Get(ctx, tenancy.FromContext(ctx), domain.OrganizationID(orgID))
+	if err != nil {
+		return nil, err
+	}
+	role, ok := actorRole(org, actor)
+	if !ok {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	if !role.AtLeast(needed) {
+		return nil, domain.ErrOrganizationForbidden
+	}
+	return org, nil
+}
+
+// requireOwnerFor lets only owners act when any of roles is owner, so that
+// admins can neither grant ownership nor touch owners.
+func (s *OrganizationService) requireOwnerFor(org *domain.Organization, actor *dto.Principal, roles ...domain.MemberRole) error {
+	for _, role := range roles {
+		if role != domain.MemberRoleOwner {
+			continue
+		}
+		if own, _ := actorRole(org, actor); own != domain.MemberRoleOwner {
+			return domain.ErrOrganizationForbidden
+		}
+	}
+	return nil
+}
+
+func (s *OrganizationService) prepareNewMember(ctx context.Context, actor *dto.Principal, orgID string, req dto.AddMemberRequest) (*domain.Organization, domain.UserID, domain.MemberRole, error) {
+	role, err := domain.ParseMemberRole(req.Role)
+	if err != nil {
+		return nil, "", "", err
+	}
+	org, err := s.authorize(ctx, actor, orgID, domain.MemberRoleAdmin)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if err := s.requireOwnerFor(org, actor, role); err != nil {
+		return nil, "", "", err
+	}
+	user, err := s.userRepo.GetByID(ctx, org.TenantID, domain.UserID(req.UserID))
+	if err != nil {
+		return nil, "", "", err
+	}
+	return org, user.ID, role, nil
+}
+
+func (s *OrganizationService) updateTeams(ctx context.Context, actor *dto.Principal, op, orgID string, change func(*domain.Organization) error) (err error) {
+	ctx, logger, done := s.begin(ctx, op, actor.UserID)
+	defer func() { done(err) }()
+
+	org, err := s.authorize(ctx, actor, orgID, domain.MemberRoleAdmin)
+	if err != nil {
+		return err
+	}
+	if err := change(org); err != nil {
+		return err
+	}
+	if err := s.orgs.Update(ctx, org); err != nil {
+		return err
+	}
+
+	logger.Info("teams updated", slog.String("organization_id", orgID))
+	return nil
+}
+
+// actorRole returns the caller's role in the organization. Service
+// administrators act as owners.
+func actorRole(org *domain.Organization, actor *dto.Principal) (domain.MemberRole, bool) {
+	if actor.Role == domain.RoleAdmin.String() {
+		return domain.MemberRoleOwner, true
+	}
+	return org.RoleOf(domain.UserID(actor.UserID))
+}
+
+func membershipStatus(m *domain.Membership) string {
+	if m.Invited {
+		return "invited"
+	}
+	return "active"
+}
+
+func organizationToResponse(org *domain.Organization) *dto.OrganizationResponse {
+	resp := &dto.OrganizationResponse{
+		ID:        org.ID.String(),
+		Name:      org.Name,
+		Members:   make([]*dto.MemberResponse, len(org.Members)),
+		Teams:     make([]*dto.TeamResponse, len(org.Teams)),
+		CreatedAt: org.CreatedAt,
+	}
+	for i := range org.Members {
+		resp.Members[i] = membershipToResponse(&org.Members[i])
+	}
+	for i := range org.Teams {
+		resp.Teams[i] = teamToResponse(&org.Teams[i])
+	}
+	return resp
+}
+
+func membershipToResponse(m *domain.Membership) *dto.MemberResponse {
+	return &dto.MemberResponse{
+		UserID:    m.UserID.String(),
+		Role:      m.Role.String(),
+		Status:    membershipStatus(m),
+		InvitedBy: m.InvitedBy.String(),
+		CreatedAt: m.CreatedAt,
+		JoinedAt:  m.JoinedAt,
+	}
+}
+
+func teamToResponse(team *domain.Team) *dto.TeamResponse {
+	members := make([]string, len(team.Members))
+	for i, id := range team.Members {
+		members[i] = id.String()
+	}
+	return &dto.TeamResponse{
+		ID:        team.ID.String(),
+		Name:      team.Name,
+		Members:   members,
+		CreatedAt: team.CreatedAt,
+	}
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/infrastructure/repository"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// readTogether holds each Get until n of them have been made, so that n
+// concurrent changes all start from the same stored organization.
+type readTogether struct {
+	domain.OrganizationRepository
+	reads sync.WaitGroup
+}
+
+func (r *readTogether) Get(ctx context.Context, tenant domain.TenantID, id domain.OrganizationID) (*domain.Organization, error) {
+	org, err := r.OrganizationRepository.Get(ctx, tenant, id)
+	r.reads.Done()
+	r.reads.Wait()
+	return org, err
+}
+
+func newTwoOwnerOrganization(t *testing.T, orgs domain.OrganizationRepository) (context.Context, *domain.Organization) {
+	t.Helper()
+	ctx := tenancy.WithTenant(context.Background(), domain.DefaultTenantID)
+	now := time.Now()
+	org, err := domain.NewOrganization(domain.DefaultTenantID, "Acme", "u-ann", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := org.AddMember("u-bob", domain.MemberRoleOwner, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := orgs.Save(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	return ctx, org
+}
+
+func TestOwnersLeavingTogetherKeepAnOwner(t *testing.T) {
+	orgs := &readTogether{OrganizationRepository: repository.NewMemoryOrganizationRepository()}
+	ctx, org := newTwoOwnerOrganization(t, orgs.OrganizationRepository)
+	svc := NewOrganizationService(orgs, repository.NewMemoryUserRepository(), OrganizationOptions{LastOwnerPolicy: LastOwnerBlock})
+
+	owners := []string{"u-ann", "u-bob"}
+	errs := make([]error, len(owners))
+	orgs.reads.Add(len(owners))
+	var wg sync.WaitGroup
+	for i, owner := range owners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			actor := &dto.Principal{UserID: owner, TenantID: domain.DefaultTenantID.String(), Role: domain.RoleUser.String()}
+			errs[i] = svc.RemoveMember(ctx, actor, org.ID.String(), owner)
+		}()
+	}
+	wg.Wait()
+
+	conflicts := 0
+	for _, err := range errs {
+		if errors.Is(err, domain.ErrOrganizationConflict) {
+			conflicts++
+		} else if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if conflicts != 1 {
+		t.Fatalf("errors = %v, want exactly one conflict", errs)
+	}
+
+	stored, err := orgs.OrganizationRepository.Get(ctx, domain.DefaultTenantID, org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored.Members) != 1 || stored.Members[0].Role != domain.MemberRoleOwner {
+		t.Fatalf("members = %+v, want one owner left", stored.Members)
+	}
+}
+
+func TestOrganizationUpdateIsConditional(t *testing.T) {
+	orgs := repository.NewMemoryOrganizationRepository()
+	ctx, org := newTwoOwnerOrganization(t, orgs)
+
+	first, _ := orgs.Get(ctx, domain.DefaultTenantID, org.ID)
+	second, _ := orgs.Get(ctx, domain.DefaultTenantID, org.ID)
+	if err := orgs.Update(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	if first.Version != second.Version+1 {
+		t.Fatalf("version = %d after update, want %d", first.Version, second.Version+1)
+	}
+	if err := orgs.Update(ctx, second); !errors.Is(err, domain.ErrOrganizationConflict) {
+		t.Fatalf("stale update err = %v, want %v", err, domain.ErrOrganizationConflict)
+	}
+	if err := orgs.Update(ctx, first); err != nil {
+		t.Fatalf("second update from the current version failed: %v", err)
+	}
+}
+
+func TestOrganizationMemberPermissions(t *testing.T) {
+	principal := func(id string) *dto.Principal {
+		return &dto.Principal{UserID: id, TenantID: domain.DefaultTenantID.String(), Role: domain.RoleUser.String()}
+	}
+	admin := &dto.Principal{UserID: "u-root", TenantID: domain.DefaultTenantID.String(), Role: domain.RoleAdmin.String()}
+
+	tests := []struct {
+		name   string
+		change func(context.Context, *OrganizationService, string) error
+		want   error
+	}{
+		{"admin removes a member", func(ctx context.Context, s *OrganizationService, org string) error {
+			return s.RemoveMember(ctx, principal("u-adm"), org, "u-dev")
+		}, nil},
+		{"admin removes an owner", func(ctx context.Context, s *OrganizationService, org string) error {
+			return s.RemoveMember(ctx, principal("u-adm"), org, "u-ann")
+		}, domain.ErrOrganizationForbidden},
+		{"admin grants ownership", func(ctx context.Context, s *OrganizationService, org string) error {
+			_, err := s.ChangeMemberRole(ctx, principal("u-adm"), org, "u-dev", dto.ChangeMemberRoleRequest{Role: "owner"})
+			return err
+		}, domain.ErrOrganizationForbidden},
+		{"member removes another member", func(ctx context.Context, s *OrganizationService, org string) error {
+			return s.RemoveMember(ctx, principal("u-dev"), org, "u-adm")
+		}, domain.ErrOrganizationForbidden},
+		{"member leaves", func(ctx context.Context, s *OrganizationService, org string) error {
+			return s.RemoveMember(ctx, principal("u-dev"), org, "u-dev")
+		}, nil},
+		{"stranger removes a member", func(ctx context.Context, s *OrganizationService, org string) error {
+			return s.RemoveMember(ctx, principal("u-eve"), org, "u-dev")
+		}, domain.ErrOrganizationNotFound},
+		{"owner demotes an owner", func(ctx context.Context, s *OrganizationService, org string) error {
+			_, err := s.ChangeMemberRole(ctx, principal("u-ann"), org, "u-bob", dto.ChangeMemberRoleRequest{Role: "admin"})
+			return err
+		}, nil},
+		{"service admin removes an owner", func(ctx context.Context, s *OrganizationService, org string) error {
+			return s.RemoveMember(ctx, admin, org, "u-bob")
+		}, nil},
+		{"last owner steps down", func(ctx context.Context, s *OrganizationService, org string) error {
+			if err := s.RemoveMember(ctx, principal("u-bob"), org, "u-bob"); err != nil {
+				return err
+			}
+			_, err := s.ChangeMemberRole(ctx, principal("u-ann"), org, "u-ann", dto.ChangeMemberRoleRequest{Role: "member"})
+			return err
+		}, domain.ErrLastOwner},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgs := repository.NewMemoryOrganizationRepository()
+			ctx, org := newTwoOwnerOrganization(t, orgs)
+			now := time.Now()
+			if err := org.AddMember("u-adm", domain.MemberRoleAdmin, now); err != nil {
+				t.Fatal(err)
+			}
+			if err := org.AddMember("u-dev", domain.MemberRoleMember, now); err != nil {
+				t.Fatal(err)
+			}
+			if err := orgs.Update(ctx, org); err != nil {
+				t.Fatal(err)
+			}
+			svc := NewOrganizationService(orgs, repository.NewMemoryUserRepository(), OrganizationOptions{LastOwnerPolicy: LastOwnerBlock})
+
+			if err := tt.change(ctx, svc, org.ID.String()); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
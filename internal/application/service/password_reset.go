@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/mail"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/application/token"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/logging"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ForgotPassword mails a reset token when the address belongs to a user and
+// has been verified; anyone can register an address they do not own, so an
+// unverified one cannot be trusted with the account. It succeeds either way
+// so callers cannot tell which addresses are registered.
+func (s *AuthService) ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) (err error) {
+	ctx, logger, done := s.begin(ctx, "ForgotPassword", "")
+	defer func() { done(err) }()
+
+	email, err := domain.ParseEmail(req.Email)
+	if err != nil {
+		// No user can have an invalid address.
+		logger.Info("password reset requested for unknown email")
+		return nil
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, tenancy.FromContext(ctx), email)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		logger.Info("password reset requested for unknown email")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.EmailVerified {
+		logger.Info("password reset refused for unverified email", slog.String(logging.KeyUserID, user.ID.String()))
+		return nil
+	}
+
+	resetToken, err := token.NewOpaque()
+	if err != nil {
+		return fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+
+	now := s.now()
+	if err := s.resetTokens.Save(ctx, &domain.PasswordResetToken{
+		TokenHash: token.Hash(resetToken),
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.opts.ResetTTL),
+	}); err != nil {
+		return err
+	}
+
+	msg := mail.Message{
+		To:       user.Email.String(),
+		Template: "password_reset",
+		Data: map[string]any{
+			"Name":      user.Name,
+			"Token":     resetToken,
+			"ExpiresIn": s.opts.ResetTTL.String(),
+		},
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		logger.Error("failed to send password reset mail", logging.KeyError, err.Error())
+	}
+
+	logger.Info("password reset issued", slog.String(logging.KeyUserID, user.ID.String()))
+	return nil
+}
+
+// ResetPassword sets a new password with a token from ForgotPassword. The
+// token is consumed even when it turns out to be expired, and every other
+// outstanding token for the user is revoked once the password changes.
+func (s *AuthService) ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) (err error) {
+	ctx, logger, done := s.begin(ctx, "ResetPassword", "")
+	defer func() { done(err) }()
+
+	// Check the password before consuming the token so a rejected password
+	// does not cost the user their reset link.
+	if err := domain.ValidatePassword(req.Password); err != nil {
+		return err
+	}
+
+	reset, err := s.resetTokens.Consume(ctx, token.Hash(req.Token))
+	if errors.Is(err, domain.ErrResetTokenNotFound) {
+		return token.ErrInvalid
+	}
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	if reset.Expired(now) {
+		return token.ErrExpired
+	}
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), reset.UserID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return token.ErrInvalid
+	}
+	if err != nil {
+		return err
+	}
+	if reset.IssuedBefore(user.PasswordChangedAt) {
+		return token.ErrInvalid
+	}
+
+	hash, err := s.hasher.Hash(req.Password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.SetPassword(hash, now)
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.resetTokens.DeleteByUser(ctx, user.ID); err != nil {
+		// Leftover tokens are already unusable: they predate the change.
+		logger.Error("failed to revoke password reset tokens", logging.KeyError, err.Error())
+	}
+
+	s.events.Publish(ctx, user.PullEvents()...)
+
+	logger.Info("password reset", slog.String(logging.KeyUserID, user.ID.String()))
+	return nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/policy"
+	"ddd-user-service/internal/application/tenancy"
+	"log/slog"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PolicyService manages the reserved and blocked lists of the policy engine
+// at runtime. Changes apply to the request's tenant only.
+type PolicyService struct {
+	engine *policy.Engine
+	tracer trace.Tracer
+}
+
+func NewPolicyService(engine *policy.Engine) *PolicyService {
+	return &PolicyService{
+		engine: engine,
+		tracer: otel.Tracer(serviceTracerName),
+	}
+}
+
+func (s *PolicyService) begin(ctx context.Context, op string) (context.Context, *slog.Logger, func(error)) {
+	return beginOperation(ctx, s.tracer, "PolicyService", op, "")
+}
+
+func (s *PolicyService) ListPolicyEntries(ctx context.Context, list string) (_ *dto.PolicyListResponse, err error) {
+	ctx, _, done := s.begin(ctx, "ListPolicyEntries")
+	defer func() { done(err) }()
+
+	l, err := policy.ParseList(list)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.engine.Entries(ctx, tenancy.FromContext(ctx), l)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.PolicyListResponse{List: string(l), Entries: make([]dto.PolicyEntryResponse, len(entries))}
+	for i, entry := range entries {
+		resp.Entries[i] = policyEntryToResponse(entry)
+	}
+	return resp, nil
+}
+
+// AddPolicyEntry reports created as false when the value was already on
+// the list.
+func (s *PolicyService) AddPolicyEntry(ctx context.Context, list string, req dto.AddPolicyEntryRequest) (_ *dto.PolicyEntryResponse, created bool, err error) {
+	ctx, logger, done := s.begin(ctx, "AddPolicyEntry")
+	defer func() { done(err) }()
+
+	l, err := policy.ParseList(list)
+	if err != nil {
+		return nil, false, err
+	}
+	entry, created, err := s.engine.Add(ctx, tenancy.FromContext(ctx), l, req.Value)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if created {
+		logger.Info("policy entry added", slog.String("list", string(l)), slog.String("value", entry.Value))
+	}
+	resp := policyEntryToResponse(entry)
+	return &resp, created, nil
+}
+
+func (s *PolicyService) RemovePolicyEntry(ctx context.Context, list, value string) (err error) {
+	ctx, logger, done := s.begin(ctx, "RemovePolicyEntry")
+	defer func() { done(err) }()
+
+	l, err := policy.ParseList(list)
+	if err != nil {
+		return err
+	}
+	if err := s.engine.Remove(ctx, tenancy.FromContext(ctx), l, value); err != nil {
+		return err
+	}
+
+	logger.Info("policy entry removed", slog.String("list", string(l)), slog.String("value", value))
+	return nil
+}
+
+func policyEntryToResponse(entry policy.Entry) dto.PolicyEntryResponse {
+	resp := dto.PolicyEntryResponse{
+		Value:  entry.Value,
+		Source: string(entry.Source),
+	}
+	if !entry.CreatedAt.IsZero() {
+		createdAt := entry.CreatedAt
+		resp.CreatedAt = &createdAt
+	}
+	return resp
+}
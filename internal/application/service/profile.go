@@ -0,0 +1,107 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/patch"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/domain"
+	"encoding/json"
+)
+
+func (s *UserService) GetProfile(ctx context.Context, id string) (_ *dto.ProfileResponse, err error) {
+	ctx, _, done := s.begin(ctx, "GetProfile", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return profileToResponse(user.Profile), nil
+}
+
+// ReplaceProfile is a full replacement: an omitted field is cleared.
+func (s *UserService) ReplaceProfile(ctx context.Context, id string, req dto.ProfileRequest) (_ *dto.ProfileResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "ReplaceProfile", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyProfile(ctx, user, req); err != nil {
+		return nil, err
+	}
+
+	logger.Info("profile updated")
+	return profileToResponse(user.Profile), nil
+}
+
+// PatchProfile applies a merge patch or JSON patch to the profile's
+// representation and validates the result through the domain.
+func (s *UserService) PatchProfile(ctx context.Context, id string, format patch.Format, body []byte) (_ *dto.ProfileResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "PatchProfile", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := json.Marshal(profileToResponse(user.Profile))
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := patch.Apply(format, current, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var req dto.ProfileRequest
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return nil, patch.ErrInvalidPatch.WithMessage("patched profile is invalid: " + err.Error())
+	}
+
+	if err := s.applyProfile(ctx, user, req); err != nil {
+		return nil, err
+	}
+
+	logger.Info("profile updated")
+	return profileToResponse(user.Profile), nil
+}
+
+func (s *UserService) applyProfile(ctx context.Context, user *domain.User, req dto.ProfileRequest) error {
+	profile := domain.Profile{
+		DisplayName: req.DisplayName,
+		GivenName:   req.GivenName,
+		FamilyName:  req.FamilyName,
+		Phone:       req.Phone,
+		Locale:      req.Locale,
+		Timezone:    req.Timezone,
+		Bio:         req.Bio,
+		AvatarURL:   req.AvatarURL,
+	}
+	if err := user.UpdateProfile(profile); err != nil {
+		return err
+	}
+	return s.userRepo.Update(ctx, user)
+}
+
+func profileToResponse(profile domain.Profile) *dto.ProfileResponse {
+	return &dto.ProfileResponse{
+		DisplayName: profile.DisplayName,
+		GivenName:   profile.GivenName,
+		FamilyName:  profile.FamilyName,
+		Phone:       profile.Phone,
+		Locale:      profile.Locale,
+		Timezone:    profile.Timezone,
+		Bio:         profile.Bio,
+		AvatarURL:   profile.AvatarURL,
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/mail"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/logging"
+	"errors"
+	"time"
+)
+
+// RegisterUser is the enumeration-safe registration flow. Invalid input is
+// still rejected, but whether the email, username or a unique attribute
+// value is already taken is never reported to the caller: the outcome is
+// sent to the supplied address instead, and the caller always gets the same
+// answer.
+func (s *UserService) RegisterUser(ctx context.Context, req dto.CreateUserRequest) (err error) {
+	ctx, logger, done := s.begin(ctx, "RegisterUser", "")
+	defer func() { done(err) }()
+
+	// Validate first so malformed input gets field errors without touching
+	// the repository.
+	if err := validateNewUser(req); err != nil {
+		return err
+	}
+
+	email, _ := domain.ParseEmail(req.Email)
+
+	var msg mail.Message
+	user, err := s.createUser(ctx, logger, req)
+	switch {
+	case err == nil:
+		verificationToken, tokenErr := s.verificationToken(user)
+		if tokenErr != nil {
+			return tokenErr
+		}
+		msg = confirmAccountMessage(user, verificationToken, s.verificationTTL)
+	case errors.Is(err, domain.ErrEmailExists):
+		msg = accountExistsMessage(email)
+	case errors.Is(err, domain.ErrUsernameExists), errors.Is(err, domain.ErrUsernameConfusable):
+		msg = usernameTakenMessage(email, domain.NormalizeUsername(req.Username))
+	case errors.Is(err, domain.ErrAttributeValueExists):
+		// Like a username, a unique attribute value must not reveal that
+		// another user holds it.
+		var conflict *domain.Error
+		errors.As(err, &conflict)
+		name, _ := domain.AttributeFieldName(conflict.Field)
+		msg = attributeTakenMessage(email, name)
+	default:
+		return err
+	}
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		// The account state is already settled; a delivery failure must not
+		// change the response.
+		logger.Error("failed to send registration mail", logging.KeyError, err.Error())
+	}
+
+	return nil
+}
+
+func confirmAccountMessage(user *domain.User, verificationToken string, ttl time.Duration) mail.Message {
+	return mail.Message{
+		To:       user.Email.String(),
+		Template: "confirm_account",
+		Data: map[string]any{
+			"Name":      user.Name,
+			"Username":  user.Username,
+			"Token":     verificationToken,
+			"ExpiresIn": ttl.String(),
+		},
+	}
+}
+
+func accountExistsMessage(email domain.Email) mail.Message {
+	return mail.Message{
+		To:       email.String(),
+		Template: "account_exists",
+	}
+}
+
+func usernameTakenMessage(email domain.Email, username domain.Username) mail.Message {
+	return mail.Message{
+		To:       email.String(),
+		Template: "username_taken",
+		Data: map[string]any{
+			"Username": username.String(),
+		},
+	}
+}
+
+func attributeTakenMessage(email domain.Email, attribute string) mail.Message {
+	return mail.Message{
+		To:       email.String(),
+		Template: "attribute_taken",
+		Data: map[string]any{
+			"Attribute": attribute,
+		},
+	}
+}
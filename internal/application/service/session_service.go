@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/token"
+	"ddd-user-service/internal/domain"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const CodeSessionExpired domain.ErrorCode = "session_expired"
+
+var ErrSessionExpired = domain.NewUnauthenticatedError(CodeSessionExpired, "the session has expired; sign in again")
+
+// sessionTouchInterval limits how often last-seen times are written: at
+// most once a minute per session, however busy it is.
+const sessionTouchInterval = time.Minute
+
+type SessionOptions struct {
+	// IdleTimeout ends sessions that have not been used for this long.
+	IdleTimeout time.Duration
+	// AbsoluteTimeout ends sessions this long after sign-in regardless of
+	// activity.
+	AbsoluteTimeout time.Duration
+}
+
+// SessionService keeps track of signed-in devices. Access tokens are opaque
+// session tokens, so they can be listed and revoked.
+type SessionService struct {
+	sessions domain.SessionRepository
+	userRepo domain.UserRepository
+	opts     SessionOptions
+	now      func() time.Time
+	tracer   trace.Tracer
+}
+
+func NewSessionService(sessions domain.SessionRepository, userRepo domain.UserRepository, opts SessionOptions) *SessionService {
+	return &SessionService{
+		sessions: sessions,
+		userRepo: userRepo,
+		opts:     opts,
+		now:      time.Now,
+		tracer:   otel.Tracer(serviceTracerName),
+	}
+}
+
+func (s *SessionService) begin(ctx context.Context, op string, userID string) (context.Context, *slog.Logger, func(error)) {
+	return beginOperation(ctx, s.tracer, "SessionService", op, userID)
+}
+
+// Start signs the user in on the client's device and returns the access
+// token for the new session. Suspended users cannot sign in.
+func (s *SessionService) Start(ctx context.Context, user *domain.User, scope string, client dto.ClientInfo) (_ string, _ *domain.Session, err error) {
+	ctx, logger, done := s.begin(ctx, "Start", user.ID.String())
+	defer func() { done(err) }()
+
+	if user.Suspended() {
+		return "", nil, domain.ErrUserSuspended
+	}
+
+	accessToken, err := token.NewOpaque()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := s.now().UTC()
+	session := &domain.Session{
+		ID:         domain.NewSessionID(),
+		UserID:     user.ID,
+		TenantID:   user.TenantID,
+		TokenHash:  token.Hash(accessToken),
+		Scope:      scope,
+		Device:     describeDevice(client.UserAgent),
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.opts.AbsoluteTimeout),
+	}
+	if err := s.sessions.Save(ctx, session); err != nil {
+		return "", nil, err
+	}
+
+	logger.Info("session started", slog.String("session_id", session.ID.String()), slog.String("device", session.Device))
+	return accessToken, session, nil
+}
+
+// VerifyAccessToken resolves a bearer token to the caller it was issued to,
+// ending the session if it has been idle or alive for too long.
+func (s *SessionService) VerifyAccessToken(ctx context.Context, accessToken string) (*dto.Principal, error) {
+	session, err := s.sessions.GetByTokenHash(ctx, token.Hash(accessToken))
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		return nil, ErrAccessTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	if !session.Active(now, s.opts.IdleTimeout) {
+		if err := s.sessions.Delete(ctx, session.UserID, session.ID); err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+			return nil, err
+		}
+		return nil, ErrSessionExpired
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.TenantID, session.UserID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, ErrAccessTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Suspended() {
+		return nil, ErrAccessTokenInvalid
+	}
+
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		if err := s.sessions.Touch(ctx, session.ID, now); err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+			return nil, err
+		}
+	}
+
+	return &dto.Principal{
+		UserID:    user.ID.String(),
+		TenantID:  user.TenantID.String(),
+		Role:      user.Role.String(),
+		Scope:     session.Scope,
+		SessionID: session.ID.String(),
+	}, nil
+}
+
+// ListSessions returns the user's active sessions, oldest first, marking the
+// one identified by currentID.
+func (s *SessionService) ListSessions(ctx context.Context, userID, currentID string) (_ []*dto.SessionResponse, err error) {
+	ctx, _, done := s.begin(ctx, "ListSessions", userID)
+	defer func() { done(err) }()
+
+	sessions, err := s.sessions.ListByUser(ctx, domain.UserID(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	responses := make([]*dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		if !session.Active(now, s.opts.IdleTimeout) {
+			continue
+		}
+		responses = append(responses, &dto.SessionResponse{
+			ID:         session.ID.String(),
+			Device:     session.Device,
+			IPAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID.String() == currentID,
+		})
+	}
+	return responses, nil
+}
+
+// RevokeSession signs one device out. Revoking the current session is a
+// logout.
+func (s *SessionService) RevokeSession(ctx context.Context, userID, sessionID string) (err error) {
+	ctx, logger, done := s.begin(ctx, "RevokeSession", userID)
+	defer func() { done(err) }()
+
+	if err := s.sessions.Delete(ctx, domain.UserID(userID), domain.SessionID(sessionID)); err != nil {
+		return err
+	}
+
+	logger.Info("session revoked", slog.String("session_id", sessionID))
+	return nil
+}
+
+// RevokeAllSessions signs the user out everywhere.
+func (s *SessionService) RevokeAllSessions(ctx context.Context, userID string) (err error) {
+	ctx, logger, done := s.begin(ctx, "RevokeAllSessions", userID)
+	defer func() { done(err) }()
+
+	revoked, err := s.sessions.DeleteByUser(ctx, domain.UserID(userID))
+	if err != nil {
+		return err
+	}
+
+	logger.Info("sessions revoked", slog.Int("count", revoked))
+	return nil
+}
+
+// RevokeOnEvent is an event handler that signs the user out everywhere once
+// their password changes or their account is suspended.
+func (s *SessionService) RevokeOnEvent(ctx context.Context, e domain.Event) error {
+	switch e := e.(type) {
+	case domain.PasswordChanged:
+		return s.RevokeAllSessions(ctx, e.UserID.String())
+	case domain.UserSuspended:
+		return s.RevokeAllSessions(ctx, e.UserID.String())
+	default:
+		return fmt.Errorf("unexpected event %s", e.EventName())
+	}
+}
+
+var (
+	deviceBrowsers = []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	}
+	devicePlatforms = []struct{ token, name string }{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// describeDevice turns a User-Agent into a short label such as "Firefox on
+// Linux" for the session list. It only needs to be recognisable, not exact.
+func describeDevice(userAgent string) string {
+	var browser, platform string
+	for _, b := range deviceBrowsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	for _, p := range devicePlatforms {
+		if strings.Contains(userAgent, p.token) {
+			platform = p.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	}
+	return "Unknown device"
+}
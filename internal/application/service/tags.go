@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/domain"
+	"log/slog"
+)
+
+// UpdateTags removes and then adds tags on one user.
+func (s *UserService) UpdateTags(ctx context.Context, id string, req dto.UpdateTagsRequest) (_ *dto.UserResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "UpdateTags", id)
+	defer func() { done(err) }()
+
+	add, remove, err := parseTagUpdate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := user.UpdateTags(add, remove)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+		logger.Info("user tags updated", slog.Any("tags", user.Tags))
+	}
+
+	return userToResponse(user), nil
+}
+
+// BulkUpdateTags applies the same tag update to every user matching the
+// filter, which must not be empty. Every user is checked first, so an update
+// that would give one of them too many tags changes nobody. If saving a user
+// fails, the response still counts the users already updated.
+func (s *UserService) BulkUpdateTags(ctx context.Context, req dto.BulkTagRequest) (_ *dto.BulkTagResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "BulkUpdateTags", "")
+	defer func() { done(err) }()
+
+	tenant := tenancy.FromContext(ctx)
+	filter, err := s.userFilter(ctx, tenant, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Empty() {
+		return nil, domain.ErrFilterRequired
+	}
+	add, remove, err := parseTagUpdate(req.UpdateTagsRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.GetAll(ctx, tenant, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []*domain.User
+	for _, user := range users {
+		ok, err := user.UpdateTags(add, remove)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			changed = append(changed, user)
+		}
+	}
+
+	resp := &dto.BulkTagResponse{Matched: len(users)}
+	for _, user := range changed {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			logger.Warn("bulk tag update stopped part-way",
+				slog.Int("matched", resp.Matched),
+				slog.Int("updated", resp.Updated),
+			)
+			return resp, err
+		}
+		resp.Updated++
+	}
+
+	logger.Info("user tags updated in bulk",
+		slog.Int("matched", resp.Matched),
+		slog.Int("updated", resp.Updated),
+	)
+	return resp, nil
+}
+
+func parseTagUpdate(req dto.UpdateTagsRequest) (add, remove []string, err error) {
+	var errs domain.ValidationErrors
+	add, err = domain.ParseTags("add", req.Add)
+	errs.Add(err)
+	remove, err = domain.ParseTags("remove", req.Remove)
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
+		return nil, nil, err
+	}
+	return add, remove, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/password"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/infrastructure/repository"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// failingUpdates lets the first allowed updates through and fails the rest.
+type failingUpdates struct {
+	domain.UserRepository
+	allowed int
+}
+
+var errStoreDown = errors.New("store down")
+
+func (r *failingUpdates) Update(ctx context.Context, user *domain.User) error {
+	if r.allowed == 0 {
+		return errStoreDown
+	}
+	r.allowed--
+	return r.UserRepository.Update(ctx, user)
+}
+
+func TestBulkUpdateTags(t *testing.T) {
+	ctx := tenancy.WithTenant(context.Background(), domain.DefaultTenantID)
+	users := &failingUpdates{UserRepository: repository.NewMemoryUserRepository(), allowed: 1}
+	for i := range 3 {
+		user, err := domain.NewUser(domain.DefaultTenantID, "Jane", fmt.Sprintf("jane%d@example.com", i), fmt.Sprintf("jane%d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := user.UpdateTags([]string{"beta"}, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := users.Save(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc := NewUserService(users, nil, nil, password.NewBcryptHasher(4), nil, nil, nil,
+		repository.NewMemoryAttributeSchemaRepository(), time.Hour)
+
+	_, err := svc.BulkUpdateTags(ctx, dto.BulkTagRequest{UpdateTagsRequest: dto.UpdateTagsRequest{Add: []string{"vip"}}})
+	if !errors.Is(err, domain.ErrFilterRequired) {
+		t.Fatalf("empty filter err = %v, want %v", err, domain.ErrFilterRequired)
+	}
+
+	req := dto.BulkTagRequest{
+		Filter:            dto.ListUsersQuery{Tags: []string{"beta"}},
+		UpdateTagsRequest: dto.UpdateTagsRequest{Add: []string{"vip"}},
+	}
+	resp, err := svc.BulkUpdateTags(ctx, req)
+	if !errors.Is(err, errStoreDown) {
+		t.Fatalf("err = %v, want %v", err, errStoreDown)
+	}
+	if resp == nil || resp.Matched != 3 || resp.Updated != 1 {
+		t.Fatalf("partial response = %+v, want 3 matched and 1 updated", resp)
+	}
+
+	users.allowed = 3
+	resp, err = svc.BulkUpdateTags(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Matched != 3 || resp.Updated != 2 {
+		t.Fatalf("retry response = %+v, want 3 matched and 2 updated", resp)
+	}
+}
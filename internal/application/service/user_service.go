@@ -1,23 +1,199 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/event"
+	"ddd-user-service/internal/application/mail"
+	"ddd-user-service/internal/application/password"
+	"ddd-user-service/internal/application/patch"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/application/token"
 	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/logging"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const serviceTracerName = "ddd-user-service/internal/application/service"
+
+const (
+	CodeReadOnly                domain.ErrorCode = "read_only"
+	CodeRoleAssignmentForbidden domain.ErrorCode = "role_assignment_forbidden"
+)
+
+var (
+	ErrReadOnlyID    = domain.NewValidationError(CodeReadOnly, "id", "id cannot be changed")
+	ErrReadOnlyField = domain.NewValidationError(CodeReadOnly, "", "field cannot be changed")
+
+	ErrRoleAssignmentForbidden = domain.NewForbiddenError(CodeRoleAssignmentForbidden, "only administrators can assign a role")
 )
 
+// UserDeletionGuard can refuse to let a user be deleted, for example while
+// other data depends on them.
+type UserDeletionGuard interface {
+	CheckUserDeletion(ctx context.Context, user *domain.User) error
+}
+
+// IdentifierPolicy can refuse emails and usernames the domain rules accept,
+// for example reserved names or disposable mail domains. Empty values are
+// not checked; self is the user being changed, if any.
+type IdentifierPolicy interface {
+	CheckIdentifiers(ctx context.Context, tenant domain.TenantID, email domain.Email, username domain.Username, self domain.UserID) error
+}
+
 type UserService struct {
-	userRepo domain.UserRepository
+	userRepo        domain.UserRepository
+	mailer          mail.Mailer
+	tokens          *token.Signer
+	hasher          password.Hasher
+	events          event.Publisher
+	deletionGuard   UserDeletionGuard
+	policy          IdentifierPolicy
+	attributes      domain.AttributeSchemaRepository
+	verificationTTL time.Duration
+	now             func() time.Time
+	tracer          trace.Tracer
 }
 
-func NewUserService(userRepo domain.UserRepository) *UserService {
+// NewUserService creates the service. deletionGuard and policy may be nil.
+func NewUserService(userRepo domain.UserRepository, mailer mail.Mailer, tokens *token.Signer, hasher password.Hasher, events event.Publisher, deletionGuard UserDeletionGuard, policy IdentifierPolicy, attributes domain.AttributeSchemaRepository, verificationTTL time.Duration) *UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:        userRepo,
+		mailer:          mailer,
+		tokens:          tokens,
+		hasher:          hasher,
+		events:          events,
+		deletionGuard:   deletionGuard,
+		policy:          policy,
+		attributes:      attributes,
+		verificationTTL: verificationTTL,
+		now:             time.Now,
+		tracer:          otel.Tracer(serviceTracerName),
+	}
+}
+
+func (s *UserService) begin(ctx context.Context, op string, userID string) (context.Context, *slog.Logger, func(error)) {
+	return beginOperation(ctx, s.tracer, "UserService", op, userID)
+}
+
+// beginOperation opens a span and returns a function that ends it and logs
+// the outcome of the operation with the standard service fields.
+func beginOperation(ctx context.Context, tracer trace.Tracer, service, op, userID string) (context.Context, *slog.Logger, func(error)) {
+	var attrs []attribute.KeyValue
+	if userID != "" {
+		attrs = append(attrs, attribute.String("user.id", userID))
+	}
+	ctx, span := tracer.Start(ctx, service+"."+op, trace.WithAttributes(attrs...))
+
+	logger := logging.FromContext(ctx).With(
+		slog.String(logging.KeyComponent, "service"),
+		slog.String(logging.KeyOperation, op),
+	)
+	if userID != "" {
+		logger = logger.With(slog.String(logging.KeyUserID, userID))
+	}
+
+	start := time.Now()
+	return ctx, logger, func(err error) {
+		duration := slog.Int64(logging.KeyDuration, time.Since(start).Milliseconds())
+		switch {
+		case err == nil:
+			logger.LogAttrs(ctx, slog.LevelDebug, "operation completed", duration)
+		case isDomainError(err):
+			span.RecordError(err)
+			logger.LogAttrs(ctx, slog.LevelInfo, "operation rejected", duration, slog.String(logging.KeyError, err.Error()))
+		default:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			logger.LogAttrs(ctx, slog.LevelError, "operation failed", duration, slog.String(logging.KeyError, err.Error()))
+		}
+		span.End()
+	}
+}
+
+func isDomainError(err error) bool {
+	var domainErr *domain.Error
+	return errors.As(err, &domainErr)
+}
+
+// CreateUser creates a user on behalf of actor, who is nil for anonymous
+// callers, and reports duplicate emails and usernames explicitly. Only
+// administrators can give the user a role. Public self-service registration
+// in private mode goes through RegisterUser.
+func (s *UserService) CreateUser(ctx context.Context, actor *dto.Principal, req dto.CreateUserRequest) (_ *dto.UserResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "CreateUser", "")
+	defer func() { done(err) }()
+
+	if req.Role != "" && (actor == nil || actor.Role != domain.RoleAdmin.String()) {
+		return nil, ErrRoleAssignmentForbidden
+	}
+
+	user, err := s.createUser(ctx, logger, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendVerification(ctx, logger, user)
+
+	return userToResponse(user), nil
+}
+
+// EnsureAdmin creates an administrator from req unless its email is already
+// registered, and reports whether it did. The address counts as verified
+// since whoever deployed the service chose it.
+func (s *UserService) EnsureAdmin(ctx context.Context, req dto.CreateUserRequest) (created bool, err error) {
+	ctx, logger, done := s.begin(ctx, "EnsureAdmin", "")
+	defer func() { done(err) }()
+
+	req.Role = domain.RoleAdmin.String()
+	user, err := s.createUser(ctx, logger, req)
+	if errors.Is(err, domain.ErrEmailExists) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := user.VerifyEmail(user.Email, s.now()); err != nil {
+		return false, err
+	}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return false, err
 	}
+	return true, nil
 }
 
-func (s *UserService) CreateUser(ctx context.Context, req dto.CreateUserRequest) (*dto.UserResponse, error) {
-	emailExists, err := s.userRepo.ExistsByEmail(ctx, req.Email)
+func (s *UserService) createUser(ctx context.Context, logger *slog.Logger, req dto.CreateUserRequest) (*domain.User, error) {
+	user, err := newUser(s.hasher, tenancy.FromContext(ctx), req, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := s.attributes.List(ctx, user.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := user.SetAttributes(schemas, req.Attributes); err != nil {
+		return nil, err
+	}
+
+	if err := checkIdentifiers(ctx, s.policy, user.TenantID, user.Email, user.Username, ""); err != nil {
+		return nil, err
+	}
+
+	emailExists, err := s.userRepo.ExistsByEmail(ctx, user.TenantID, user.Email)
 	if err != nil {
 		return nil, err
 	}
@@ -25,7 +201,7 @@ func (s *UserService) CreateUser(ctx context.Context, req dto.CreateUserRequest)
 		return nil, domain.ErrEmailExists
 	}
 
-	usernameExists, err := s.userRepo.ExistsByUsername(ctx, req.Username)
+	usernameExists, err := s.userRepo.ExistsByUsername(ctx, user.TenantID, user.Username)
 	if err != nil {
 		return nil, err
 	}
@@ -33,8 +209,7 @@ func (s *UserService) CreateUser(ctx context.Context, req dto.CreateUserRequest)
 		return nil, domain.ErrUsernameExists
 	}
 
-	user, err := domain.NewUser(req.Name, req.Email, req.Username)
-	if err != nil {
+	if err := checkUniqueAttributes(ctx, s.userRepo, user, schemas, nil); err != nil {
 		return nil, err
 	}
 
@@ -42,94 +217,402 @@ func (s *UserService) CreateUser(ctx context.Context, req dto.CreateUserRequest)
 		return nil, err
 	}
 
-	return s.userToResponse(user), nil
+	logger.Info("user created",
+		slog.String(logging.KeyUserID, user.ID.String()),
+		slog.String("email", user.Email.String()),
+		slog.String("username", user.Username.String()),
+	)
+
+	return user, nil
+}
+
+// newUser builds the user and hashes the optional initial password.
+func newUser(hasher password.Hasher, tenant domain.TenantID, req dto.CreateUserRequest, now time.Time) (*domain.User, error) {
+	if err := validateNewUser(req); err != nil {
+		return nil, err
+	}
+
+	user, err := domain.NewUser(tenant, req.Name, req.Email, req.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Role != "" {
+		role, _ := domain.ParseRole(req.Role)
+		user.AssignRole(role)
+	}
+
+	if req.Password != "" {
+		hash, err := hasher.Hash(req.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+		user.SetPassword(hash, now)
+		// Setting the first password is not a change anything needs to react to.
+		user.PullEvents()
+	}
+
+	return user, nil
+}
+
+func checkIdentifiers(ctx context.Context, policy IdentifierPolicy, tenant domain.TenantID, email domain.Email, username domain.Username, self domain.UserID) error {
+	if policy == nil {
+		return nil
+	}
+	return policy.CheckIdentifiers(ctx, tenant, email, username, self)
+}
+
+// validateNewUser reports every invalid field of req together, including
+// the password when one is given.
+func validateNewUser(req dto.CreateUserRequest) error {
+	var errs domain.ValidationErrors
+	// The tenant plays no part in validation.
+	_, err := domain.NewUser(domain.DefaultTenantID, req.Name, req.Email, req.Username)
+	errs.Add(err)
+	if req.Password != "" {
+		errs.Add(domain.ValidatePassword(req.Password))
+	}
+	if req.Role != "" {
+		_, err := domain.ParseRole(req.Role)
+		errs.Add(err)
+	}
+	return errs.Err()
 }
 
-func (s *UserService) GetUserByID(ctx context.Context, id string) (*dto.UserResponse, error) {
+func (s *UserService) GetUserByID(ctx context.Context, id string) (_ *dto.UserResponse, err error) {
+	ctx, _, done := s.begin(ctx, "GetUserByID", id)
+	defer func() { done(err) }()
+
 	userID := domain.UserID(id)
-	user, err := s.userRepo.GetByID(ctx, userID)
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), userID)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.userToResponse(user), nil
+	return userToResponse(user), nil
+}
+
+// checkUniqueAttributes reports a unique attribute value another user
+// already holds. Values unchanged from previous are not checked again.
+func checkUniqueAttributes(ctx context.Context, users domain.UserRepository, user *domain.User, schemas []*domain.AttributeSchema, previous map[string]any) error {
+	for _, schema := range schemas {
+		value, ok := user.Attributes[schema.Name]
+		if !schema.Unique || !ok || previous[schema.Name] == value {
+			continue
+		}
+		filter := domain.UserFilter{Attributes: map[string]any{schema.Name: value}}
+		holders, err := users.GetAll(ctx, user.TenantID, filter)
+		if err != nil {
+			return err
+		}
+		for _, holder := range holders {
+			if holder.ID != user.ID {
+				return domain.AttributeValueExists(schema.Name)
+			}
+		}
+	}
+	return nil
 }
 
-func (s *UserService) GetAllUsers(ctx context.Context) ([]*dto.UserResponse, error) {
-	users, err := s.userRepo.GetAll(ctx)
+func (s *UserService) GetAllUsers(ctx context.Context, query dto.ListUsersQuery) (_ []*dto.UserResponse, err error) {
+	ctx, _, done := s.begin(ctx, "GetAllUsers", "")
+	defer func() { done(err) }()
+
+	tenant := tenancy.FromContext(ctx)
+	filter, err := s.userFilter(ctx, tenant, query)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.GetAll(ctx, tenant, filter)
 	if err != nil {
 		return nil, err
 	}
 
 	responses := make([]*dto.UserResponse, len(users))
 	for i, user := range users {
-		responses[i] = s.userToResponse(user)
+		responses[i] = userToResponse(user)
 	}
 
 	return responses, nil
 }
 
-func (s *UserService) UpdateUser(ctx context.Context, id string, req dto.UpdateUserRequest) (*dto.UserResponse, error) {
-	userID := domain.UserID(id)
-	user, err := s.userRepo.GetByID(ctx, userID)
+// userFilter validates the query, reporting every problem together.
+func (s *UserService) userFilter(ctx context.Context, tenant domain.TenantID, query dto.ListUsersQuery) (domain.UserFilter, error) {
+	var filter domain.UserFilter
+	var errs domain.ValidationErrors
+	if len(query.Attributes) > 0 {
+		schemas, err := s.attributes.List(ctx, tenant)
+		if err != nil {
+			return filter, err
+		}
+		filter.Attributes, err = domain.ParseAttributeText(schemas, query.Attributes)
+		errs.Add(err)
+	}
+	var err error
+	filter.Tags, err = domain.ParseTags("tags", query.Tags)
+	errs.Add(err)
+	filter.TagMatch, err = domain.ParseTagMatch(query.TagMatch)
+	errs.Add(err)
+	return filter, errs.Err()
+}
+
+// ReplaceUser is a full replacement: every field in req is applied, so an
+// omitted field is validated as empty.
+func (s *UserService) ReplaceUser(ctx context.Context, id string, req dto.ReplaceUserRequest) (_ *dto.UserResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "ReplaceUser", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
 	if err != nil {
 		return nil, err
 	}
 
-	if req.Name != nil {
-		if err := user.UpdateName(*req.Name); err != nil {
-			return nil, err
-		}
+	if err := s.applyReplacement(ctx, logger, user, req); err != nil {
+		return nil, err
+	}
+
+	return userToResponse(user), nil
+}
+
+// PatchUser applies a merge patch or JSON patch to the user's representation
+// and validates the result through the domain.
+func (s *UserService) PatchUser(ctx context.Context, id string, format patch.Format, body []byte) (_ *dto.UserResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "PatchUser", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := json.Marshal(userToResponse(user))
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := patch.Apply(format, current, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result dto.UserResponse
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&result); err != nil {
+		return nil, patch.ErrInvalidPatch.WithMessage("patched user is invalid: " + err.Error())
+	}
+	if err := checkReadOnly(userToResponse(user), &result); err != nil {
+		return nil, err
+	}
+
+	replacement := dto.ReplaceUserRequest{
+		Name:       result.Name,
+		Email:      result.Email,
+		Username:   result.Username,
+		Attributes: result.Attributes,
+	}
+	if err := s.applyReplacement(ctx, logger, user, replacement); err != nil {
+		return nil, err
+	}
+
+	return userToResponse(user), nil
+}
+
+func (s *UserService) applyReplacement(ctx context.Context, logger *slog.Logger, user *domain.User, req dto.ReplaceUserRequest) error {
+	previous := *user
+
+	schemas, err := s.attributes.List(ctx, user.TenantID)
+	if err != nil {
+		return err
 	}
 
-	if req.Email != nil {
-		emailExists, err := s.userRepo.ExistsByEmail(ctx, *req.Email)
+	var errs domain.ValidationErrors
+	errs.Add(user.UpdateName(req.Name))
+	errs.Add(user.UpdateEmail(req.Email))
+	errs.Add(user.UpdateUsername(req.Username))
+	errs.Add(user.SetAttributes(schemas, req.Attributes))
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	emailToVerify := user.EmailToVerify()
+	var newEmail domain.Email
+	if emailToVerify != previous.EmailToVerify() && !emailToVerify.Equal(previous.Email) {
+		newEmail = emailToVerify
+	}
+	var newUsername domain.Username
+	if user.Username != previous.Username {
+		newUsername = user.Username
+	}
+	if err := checkIdentifiers(ctx, s.policy, user.TenantID, newEmail, newUsername, user.ID); err != nil {
+		return err
+	}
+
+	if newEmail != "" {
+		emailExists, err := s.userRepo.ExistsByEmail(ctx, user.TenantID, newEmail)
 		if err != nil {
-			return nil, err
-		}
-		if emailExists && user.Email != *req.Email {
-			return nil, domain.ErrEmailExists
+			return err
 		}
-		if err := user.UpdateEmail(*req.Email); err != nil {
-			return nil, err
+		if emailExists {
+			return domain.ErrEmailExists
 		}
 	}
 
-	if req.Username != nil {
-		usernameExists, err := s.userRepo.ExistsByUsername(ctx, *req.Username)
+	if newUsername != "" {
+		usernameExists, err := s.userRepo.ExistsByUsername(ctx, user.TenantID, user.Username)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		if usernameExists && user.Username != *req.Username {
-			return nil, domain.ErrUsernameExists
+		if usernameExists {
+			return domain.ErrUsernameExists
 		}
-		if err := user.UpdateUsername(*req.Username); err != nil {
-			return nil, err
+	}
+
+	if err := checkUniqueAttributes(ctx, s.userRepo, user, schemas, previous.Attributes); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	logger.Info("user updated",
+		slog.Bool("name_changed", user.Name != previous.Name),
+		slog.Bool("email_changed", user.Email != previous.Email),
+		slog.Bool("email_change_pending", user.PendingEmail != ""),
+		slog.Bool("username_changed", user.Username != previous.Username),
+		slog.Bool("attributes_changed", !maps.Equal(user.Attributes, previous.Attributes)),
+	)
+
+	if emailToVerify != "" && emailToVerify != previous.EmailToVerify() {
+		s.sendVerification(ctx, logger, user)
+	}
+	return nil
+}
+
+// checkReadOnly rejects patches that touch fields only the server may set.
+func checkReadOnly(before, after *dto.UserResponse) error {
+	var errs domain.ValidationErrors
+	if after.ID != before.ID {
+		errs = append(errs, ErrReadOnlyID)
+	}
+	if after.TenantID != before.TenantID {
+		errs = append(errs, ErrReadOnlyField.WithField("tenant_id"))
+	}
+	if after.EmailVerified != before.EmailVerified || !equalTime(after.EmailVerifiedAt, before.EmailVerifiedAt) {
+		errs = append(errs, ErrReadOnlyField.WithField("email_verified"))
+	}
+	if after.PendingEmail != before.PendingEmail {
+		errs = append(errs, ErrReadOnlyField.WithField("pending_email"))
+	}
+	if after.Role != before.Role {
+		errs = append(errs, ErrReadOnlyField.WithField("role"))
+	}
+	if after.MFAEnabled != before.MFAEnabled {
+		errs = append(errs, ErrReadOnlyField.WithField("mfa_enabled"))
+	}
+	if after.Suspended != before.Suspended {
+		errs = append(errs, ErrReadOnlyField.WithField("suspended"))
+	}
+	if !slices.Equal(after.Tags, before.Tags) {
+		errs = append(errs, ErrReadOnlyField.WithField("tags"))
+	}
+	return errs.Err()
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func (s *UserService) DeleteUser(ctx context.Context, id string) (err error) {
+	ctx, logger, done := s.begin(ctx, "DeleteUser", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return err
+	}
+
+	if s.deletionGuard != nil {
+		if err := s.deletionGuard.CheckUserDeletion(ctx, user); err != nil {
+			return err
 		}
 	}
 
+	if err := s.userRepo.Delete(ctx, user.TenantID, user.ID); err != nil {
+		return err
+	}
+
+	logger.Info("user deleted")
+	s.events.Publish(ctx, domain.UserDeleted{UserID: user.ID, TenantID: user.TenantID, Avatar: user.Avatar, OccurredAt: s.now().UTC()})
+	return nil
+}
+
+// SuspendUser blocks the user from signing in and, through the
+// UserSuspended event, signs them out everywhere.
+func (s *UserService) SuspendUser(ctx context.Context, id string) (_ *dto.UserResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "SuspendUser", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	user.Suspend(s.now())
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
+	s.events.Publish(ctx, user.PullEvents()...)
 
-	return s.userToResponse(user), nil
+	logger.Info("user suspended")
+	return userToResponse(user), nil
 }
 
-func (s *UserService) DeleteUser(ctx context.Context, id string) error {
-	userID := domain.UserID(id)
-	_, err := s.userRepo.GetByID(ctx, userID)
+func (s *UserService) ReinstateUser(ctx context.Context, id string) (_ *dto.UserResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "ReinstateUser", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return s.userRepo.Delete(ctx, userID)
+	user.Reinstate()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	logger.Info("user reinstated")
+	return userToResponse(user), nil
 }
 
-func (s *UserService) userToResponse(user *domain.User) *dto.UserResponse {
+func userToResponse(user *domain.User) *dto.UserResponse {
+	attributes := maps.Clone(user.Attributes)
+	if attributes == nil {
+		attributes = map[string]any{}
+	}
+	tags := slices.Clone(user.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
 	return &dto.UserResponse{
-		ID:       user.ID.String(),
-		Name:     user.Name,
-		Email:    user.Email,
-		Username: user.Username,
+		ID:              user.ID.String(),
+		TenantID:        user.TenantID.String(),
+		Name:            user.Name,
+		Email:           user.Email.String(),
+		Username:        user.Username.String(),
+		EmailVerified:   user.EmailVerified,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		PendingEmail:    user.PendingEmail.String(),
+		Role:            user.Role.String(),
+		MFAEnabled:      user.MFAEnabled(),
+		Suspended:       user.Suspended(),
+		Attributes:      attributes,
+		Tags:            tags,
 	}
 }
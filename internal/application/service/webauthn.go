@@ -0,0 +1,357 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/passkey"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/application/token"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/logging"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+const CodeWebAuthnFailed domain.ErrorCode = "webauthn_verification_failed"
+
+var ErrWebAuthnFailed = domain.NewUnauthenticatedError(CodeWebAuthnFailed, "the authenticator response could not be verified")
+
+// BeginWebAuthnRegistration starts adding an authenticator to the user's
+// account. Authenticators the user already registered are excluded.
+func (s *AuthService) BeginWebAuthnRegistration(ctx context.Context, id string, req dto.BeginWebAuthnRegistrationRequest) (_ *dto.WebAuthnCeremonyResponse, err error) {
+	ctx, _, done := s.begin(ctx, "BeginWebAuthnRegistration", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = fmt.Sprintf("Passkey %d", len(user.WebAuthnCredentials)+1)
+	}
+	// Check the name now rather than after the user has touched their
+	// authenticator.
+	if name, err = domain.ValidateCredentialName(name); err != nil {
+		return nil, err
+	}
+
+	creation, session, err := s.relyingParty.BeginRegistration(passkey.User(user),
+		webauthn.WithExclusions(passkey.Descriptors(user)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin WebAuthn registration: %w", err)
+	}
+
+	ceremonyID, err := s.saveCeremony(ctx, passkey.CeremonyRegistration, user.ID.String(), name, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.WebAuthnCeremonyResponse{CeremonyID: ceremonyID, Options: creation}, nil
+}
+
+// FinishWebAuthnRegistration verifies the authenticator's response and
+// stores its public key.
+func (s *AuthService) FinishWebAuthnRegistration(ctx context.Context, id string, req dto.FinishWebAuthnRequest) (_ *dto.WebAuthnCredentialResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "FinishWebAuthnRegistration", id)
+	defer func() { done(err) }()
+
+	ceremony, session, err := s.takeCeremony(ctx, req.CeremonyID, passkey.CeremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony.UserID != id {
+		return nil, passkey.ErrCeremonyInvalid
+	}
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(req.Credential))
+	if err != nil {
+		return nil, webAuthnError(err)
+	}
+	credential, err := s.relyingParty.CreateCredential(passkey.User(user), *session, parsed)
+	if err != nil {
+		return nil, webAuthnError(err)
+	}
+
+	transports := make([]string, len(credential.Transport))
+	for i, t := range credential.Transport {
+		transports[i] = string(t)
+	}
+	if err := user.AddWebAuthnCredential(domain.WebAuthnCredential{
+		ID:              credential.ID,
+		Name:            ceremony.Name,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      transports,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		CreatedAt:       s.now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	logger.Info("WebAuthn credential registered", slog.String("attestation", credential.AttestationType))
+
+	return webAuthnCredentialResponse(user.WebAuthnCredential(credential.ID)), nil
+}
+
+func (s *AuthService) ListWebAuthnCredentials(ctx context.Context, id string) (_ []*dto.WebAuthnCredentialResponse, err error) {
+	ctx, _, done := s.begin(ctx, "ListWebAuthnCredentials", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.WebAuthnCredentialResponse, len(user.WebAuthnCredentials))
+	for i := range user.WebAuthnCredentials {
+		responses[i] = webAuthnCredentialResponse(&user.WebAuthnCredentials[i])
+	}
+	return responses, nil
+}
+
+func (s *AuthService) RenameWebAuthnCredential(ctx context.Context, id, credentialID string, req dto.RenameWebAuthnCredentialRequest) (_ *dto.WebAuthnCredentialResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "RenameWebAuthnCredential", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	rawID, err := decodeCredentialID(credentialID)
+	if err != nil {
+		return nil, err
+	}
+	if err := user.RenameWebAuthnCredential(rawID, req.Name); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	logger.Info("WebAuthn credential renamed")
+
+	return webAuthnCredentialResponse(user.WebAuthnCredential(rawID)), nil
+}
+
+func (s *AuthService) DeleteWebAuthnCredential(ctx context.Context, id, credentialID string) (err error) {
+	ctx, logger, done := s.begin(ctx, "DeleteWebAuthnCredential", id)
+	defer func() { done(err) }()
+
+	user, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(id))
+	if err != nil {
+		return err
+	}
+
+	rawID, err := decodeCredentialID(credentialID)
+	if err != nil {
+		return err
+	}
+	if err := user.RemoveWebAuthnCredential(rawID); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	logger.Info("WebAuthn credential removed")
+	return nil
+}
+
+// BeginWebAuthnLogin starts a passwordless login. Without a login, or for
+// an unknown one, any discoverable credential for this site is accepted, so
+// the response does not reveal which accounts exist.
+func (s *AuthService) BeginWebAuthnLogin(ctx context.Context, req dto.BeginWebAuthnLoginRequest) (_ *dto.WebAuthnCeremonyResponse, err error) {
+	ctx, _, done := s.begin(ctx, "BeginWebAuthnLogin", "")
+	defer func() { done(err) }()
+
+	var (
+		assertion *protocol.CredentialAssertion
+		session   *webauthn.SessionData
+		userID    string
+	)
+
+	var user *domain.User
+	if req.Login != "" {
+		user, err = s.findByLogin(ctx, req.Login)
+		if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+			return nil, err
+		}
+	}
+
+	if user != nil && len(user.WebAuthnCredentials) > 0 {
+		userID = user.ID.String()
+		assertion, session, err = s.relyingParty.BeginLogin(passkey.User(user),
+			webauthn.WithUserVerification(protocol.VerificationRequired))
+	} else {
+		assertion, session, err = s.relyingParty.BeginDiscoverableLogin(
+			webauthn.WithUserVerification(protocol.VerificationRequired))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin WebAuthn login: %w", err)
+	}
+
+	ceremonyID, err := s.saveCeremony(ctx, passkey.CeremonyLogin, userID, "", session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.WebAuthnCeremonyResponse{CeremonyID: ceremonyID, Options: assertion}, nil
+}
+
+// FinishWebAuthnLogin verifies an assertion and signs the user in. A
+// user-verifying authenticator is both factors at once, so no further MFA
+// challenge follows.
+func (s *AuthService) FinishWebAuthnLogin(ctx context.Context, req dto.FinishWebAuthnRequest, client dto.ClientInfo) (_ *dto.LoginResponse, err error) {
+	ctx, logger, done := s.begin(ctx, "FinishWebAuthnLogin", "")
+	defer func() { done(err) }()
+
+	ceremony, session, err := s.takeCeremony(ctx, req.CeremonyID, passkey.CeremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(req.Credential))
+	if err != nil {
+		return nil, webAuthnError(err)
+	}
+
+	var user *domain.User
+	if ceremony.UserID != "" {
+		user, err = s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(ceremony.UserID))
+		if err != nil {
+			return nil, err
+		}
+		_, err = s.relyingParty.ValidateLogin(passkey.User(user), *session, parsed)
+	} else {
+		_, err = s.relyingParty.ValidateDiscoverableLogin(func(_, userHandle []byte) (webauthn.User, error) {
+			found, err := s.userRepo.GetByID(ctx, tenancy.FromContext(ctx), domain.UserID(userHandle))
+			if err != nil {
+				return nil, err
+			}
+			user = found
+			return passkey.User(found), nil
+		}, *session, parsed)
+	}
+	if err != nil {
+		if user == nil && !errors.Is(err, domain.ErrUserNotFound) && !isWebAuthnError(err) {
+			return nil, err
+		}
+		return nil, webAuthnError(err)
+	}
+
+	if err := user.RecordWebAuthnUse(parsed.RawID, parsed.Response.AuthenticatorData.Counter, s.now()); err != nil {
+		logger.Warn("WebAuthn credential rejected", slog.String(logging.KeyUserID, user.ID.String()), logging.KeyError, err.Error())
+		return nil, err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	logger.Info("login succeeded", slog.String(logging.KeyUserID, user.ID.String()), slog.String("mfa_method", "webauthn"))
+	return s.startSession(ctx, user, dto.ScopeFull, dto.LoginStatusAuthenticated, client)
+}
+
+func (s *AuthService) saveCeremony(ctx context.Context, kind, userID, name string, session *webauthn.SessionData) (string, error) {
+	encoded, err := json.Marshal(session)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode WebAuthn session: %w", err)
+	}
+	ceremonyID, err := token.NewOpaque()
+	if err != nil {
+		return "", err
+	}
+
+	ceremony := &passkey.Ceremony{
+		ID:        ceremonyID,
+		Kind:      kind,
+		UserID:    userID,
+		Name:      name,
+		Session:   encoded,
+		ExpiresAt: s.now().Add(s.opts.CeremonyTTL),
+	}
+	if err := s.ceremonies.Save(ctx, ceremony); err != nil {
+		return "", err
+	}
+	return ceremony.ID, nil
+}
+
+// takeCeremony consumes the ceremony so its challenge cannot be answered
+// twice.
+func (s *AuthService) takeCeremony(ctx context.Context, id, kind string) (*passkey.Ceremony, *webauthn.SessionData, error) {
+	ceremony, err := s.ceremonies.Take(ctx, id)
+	if errors.Is(err, passkey.ErrCeremonyNotFound) {
+		return nil, nil, passkey.ErrCeremonyInvalid
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if ceremony.Kind != kind || !s.now().Before(ceremony.ExpiresAt) {
+		return nil, nil, passkey.ErrCeremonyInvalid
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal(ceremony.Session, &session); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode WebAuthn session: %w", err)
+	}
+	return ceremony, &session, nil
+}
+
+func isWebAuthnError(err error) bool {
+	var protocolErr *protocol.Error
+	return errors.As(err, &protocolErr)
+}
+
+// webAuthnError hides the library's error behind a stable code, keeping its
+// detail in the message for debugging.
+func webAuthnError(err error) error {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) && protocolErr.DevInfo != "" {
+		return ErrWebAuthnFailed.WithMessage("the authenticator response could not be verified: " + protocolErr.DevInfo)
+	}
+	return ErrWebAuthnFailed
+}
+
+func decodeCredentialID(id string) ([]byte, error) {
+	rawID, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return nil, domain.ErrCredentialNotFound
+	}
+	return rawID, nil
+}
+
+func webAuthnCredentialResponse(credential *domain.WebAuthnCredential) *dto.WebAuthnCredentialResponse {
+	return &dto.WebAuthnCredentialResponse{
+		ID:              base64.RawURLEncoding.EncodeToString(credential.ID),
+		Name:            credential.Name,
+		AttestationType: credential.AttestationType,
+		Transports:      credential.Transports,
+		BackupEligible:  credential.BackupEligible,
+		CreatedAt:       credential.CreatedAt,
+		LastUsedAt:      credential.LastUsedAt,
+	}
+}
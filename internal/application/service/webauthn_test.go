@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/event"
+	"ddd-user-service/internal/application/lockout"
+	"ddd-user-service/internal/application/passkey"
+	"ddd-user-service/internal/application/password"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/application/token"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/infrastructure/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+const testOrigin = "https://example.com"
+
+type webAuthnFixture struct {
+	ctx  context.Context
+	auth *AuthService
+	user *domain.User
+	key  *passkey.SoftwareAuthenticator
+}
+
+func newWebAuthnFixture(t *testing.T) *webAuthnFixture {
+	t.Helper()
+	ctx := tenancy.WithTenant(context.Background(), domain.DefaultTenantID)
+
+	users := repository.NewMemoryUserRepository()
+	user, err := domain.NewUser(domain.DefaultTenantID, "Jane Doe", "jane@example.com", "jane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Save(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	relyingParty, err := passkey.NewRelyingParty("example.com", "Example", []string{testOrigin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := NewAuthService(AuthDependencies{
+		Users:        users,
+		Ceremonies:   repository.NewMemoryWebAuthnCeremonyStore(),
+		Sessions:     NewSessionService(repository.NewMemorySessionRepository(), users, SessionOptions{IdleTimeout: time.Hour, AbsoluteTimeout: time.Hour}),
+		Lockout:      lockout.NewGuard(repository.NewMemoryLoginAttemptStore(), lockout.Policy{}, lockout.Policy{}),
+		Hasher:       password.NewBcryptHasher(4),
+		Tokens:       token.NewSigner([]byte("test-secret")),
+		RelyingParty: relyingParty,
+		Events:       event.NewDispatcher(),
+	}, AuthOptions{CeremonyTTL: 5 * time.Minute})
+
+	return &webAuthnFixture{ctx: ctx, auth: auth, user: user, key: passkey.NewSoftwareAuthenticator(testOrigin)}
+}
+
+// register runs a registration ceremony, letting tamper change the options
+// before the authenticator sees them.
+func (f *webAuthnFixture) register(t *testing.T, tamper func(*protocol.PublicKeyCredentialCreationOptions)) (*dto.WebAuthnCredentialResponse, error) {
+	t.Helper()
+	begun, err := f.auth.BeginWebAuthnRegistration(f.ctx, f.user.ID.String(), dto.BeginWebAuthnRegistrationRequest{Name: "Laptop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := begun.Options.(*protocol.CredentialCreation).Response
+	if tamper != nil {
+		tamper(&options)
+	}
+	credential, err := f.key.Create(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f.auth.FinishWebAuthnRegistration(f.ctx, f.user.ID.String(),
+		dto.FinishWebAuthnRequest{CeremonyID: begun.CeremonyID, Credential: credential})
+}
+
+// assert begins a login and has the authenticator answer it, without
+// finishing.
+func (f *webAuthnFixture) assert(t *testing.T, login string) dto.FinishWebAuthnRequest {
+	t.Helper()
+	begun, err := f.auth.BeginWebAuthnLogin(f.ctx, dto.BeginWebAuthnLoginRequest{Login: login})
+	if err != nil {
+		t.Fatal(err)
+	}
+	credential, err := f.key.Get(begun.Options.(*protocol.CredentialAssertion).Response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dto.FinishWebAuthnRequest{CeremonyID: begun.CeremonyID, Credential: credential}
+}
+
+func (f *webAuthnFixture) login(t *testing.T, login string) (*dto.LoginResponse, error) {
+	t.Helper()
+	return f.auth.FinishWebAuthnLogin(f.ctx, f.assert(t, login), dto.ClientInfo{})
+}
+
+func TestWebAuthnRoundTrip(t *testing.T) {
+	for _, login := range []string{"", "jane"} {
+		name := "discoverable"
+		if login != "" {
+			name = "named account"
+		}
+		t.Run(name, func(t *testing.T) {
+			f := newWebAuthnFixture(t)
+			registered, err := f.register(t, nil)
+			if err != nil {
+				t.Fatalf("registration failed: %v", err)
+			}
+			if registered.Name != "Laptop" || registered.AttestationType != "none" {
+				t.Fatalf("registered %+v, want Laptop with none attestation", registered)
+			}
+
+			for i := range 2 {
+				resp, err := f.login(t, login)
+				if err != nil {
+					t.Fatalf("login %d failed: %v", i+1, err)
+				}
+				if resp.Status != dto.LoginStatusAuthenticated || resp.AccessToken == "" {
+					t.Fatalf("login %d: status = %q, want an authenticated session", i+1, resp.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestWebAuthnRejectsSignCountRegression(t *testing.T) {
+	f := newWebAuthnFixture(t)
+	if _, err := f.register(t, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two assertions answered in order but finished out of order look like
+	// a copy of the key signing with a stale counter.
+	earlier, later := f.assert(t, ""), f.assert(t, "")
+	if _, err := f.auth.FinishWebAuthnLogin(f.ctx, later, dto.ClientInfo{}); err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	if _, err := f.auth.FinishWebAuthnLogin(f.ctx, earlier, dto.ClientInfo{}); !errors.Is(err, domain.ErrCredentialCloned) {
+		t.Fatalf("err = %v, want %v", err, domain.ErrCredentialCloned)
+	}
+}
+
+func TestWebAuthnCeremonyIsSingleUse(t *testing.T) {
+	f := newWebAuthnFixture(t)
+	if _, err := f.register(t, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	req := f.assert(t, "")
+	if _, err := f.auth.FinishWebAuthnLogin(f.ctx, req, dto.ClientInfo{}); err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	if _, err := f.auth.FinishWebAuthnLogin(f.ctx, req, dto.ClientInfo{}); !errors.Is(err, passkey.ErrCeremonyInvalid) {
+		t.Fatalf("err = %v, want %v", err, passkey.ErrCeremonyInvalid)
+	}
+}
+
+func TestWebAuthnRejectsForeignRelyingParty(t *testing.T) {
+	tests := []struct {
+		name   string
+		origin string
+		tamper func(*protocol.PublicKeyCredentialCreationOptions)
+	}{
+		{"wrong origin", "https://evil.example", nil},
+		{"wrong RP ID", testOrigin, func(o *protocol.PublicKeyCredentialCreationOptions) { o.RelyingParty.ID = "evil.example" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newWebAuthnFixture(t)
+			f.key.Origin = tt.origin
+			if _, err := f.register(t, tt.tamper); !errors.Is(err, ErrWebAuthnFailed) {
+				t.Fatalf("err = %v, want %v", err, ErrWebAuthnFailed)
+			}
+		})
+	}
+
+	t.Run("login from wrong origin", func(t *testing.T) {
+		f := newWebAuthnFixture(t)
+		if _, err := f.register(t, nil); err != nil {
+			t.Fatal(err)
+		}
+		f.key.Origin = "https://evil.example"
+		if _, err := f.login(t, "jane"); !errors.Is(err, ErrWebAuthnFailed) {
+			t.Fatalf("err = %v, want %v", err, ErrWebAuthnFailed)
+		}
+	})
+}
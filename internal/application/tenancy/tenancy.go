@@ -0,0 +1,22 @@
+// Package tenancy carries the tenant a request acts in. Services read it
+// from the context and pass it to every repository call, so code paths that
+// never resolved a tenant fail instead of reading across tenants.
+package tenancy
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+)
+
+type contextKey struct{}
+
+func WithTenant(ctx context.Context, tenant domain.TenantID) context.Context {
+	return context.WithValue(ctx, contextKey{}, tenant)
+}
+
+// FromContext returns the request's tenant, or "" when none was resolved.
+// Repositories reject the empty tenant with domain.ErrTenantRequired.
+func FromContext(ctx context.Context) domain.TenantID {
+	tenant, _ := ctx.Value(contextKey{}).(domain.TenantID)
+	return tenant
+}
@@ -0,0 +1,77 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigner(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	signer := NewSigner([]byte("secret"))
+	signer.now = func() time.Time { return now }
+
+	token, err := signer.Sign("email_verification", "u-1", "jane@example.com", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := signer.Verify("email_verification", token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "u-1" || claims.Value != "jane@example.com" || !claims.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("claims = %+v", claims)
+	}
+
+	encoded, signature, _ := strings.Cut(token, ".")
+	other := NewSigner([]byte("other-secret"))
+	other.now = signer.now
+	forged, err := other.Sign("email_verification", "u-2", "eve@example.com", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name    string
+		purpose string
+		token   string
+		after   time.Duration
+		want    error
+	}{
+		{"other purpose", "mfa_challenge", token, 0, ErrInvalid},
+		{"other secret", "email_verification", forged, 0, ErrInvalid},
+		{"swapped payload", "email_verification", forgedPayload + "." + signature, 0, ErrInvalid},
+		{"no signature", "email_verification", encoded, 0, ErrInvalid},
+		{"not base64", "email_verification", "!!!." + signer.sign("!!!"), 0, ErrInvalid},
+		{"not json", "email_verification", "bm90LWpzb24." + signer.sign("bm90LWpzb24"), 0, ErrInvalid},
+		{"just before expiry", "email_verification", token, time.Hour - time.Second, nil},
+		{"at expiry", "email_verification", token, time.Hour, ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer.now = func() time.Time { return now.Add(tt.after) }
+			if _, err := signer.Verify(tt.purpose, tt.token); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpaque(t *testing.T) {
+	a, err := NewOpaque()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewOpaque()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b || len(a) != 43 {
+		t.Fatalf("tokens %q and %q, want two distinct 32-byte tokens", a, b)
+	}
+	if Hash(a) != Hash(a) || Hash(a) == Hash(b) || strings.Contains(Hash(a), a) {
+		t.Fatal("Hash is not a stable digest of the token")
+	}
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix starts every API key so that leaked keys are easy to spot,
+// both by people and by secret scanners.
+const APIKeyPrefix = "dus_"
+
+// API key scopes.
+const (
+	APIScopeUsersRead  = "users:read"
+	APIScopeUsersWrite = "users:write"
+)
+
+var apiScopes = []string{APIScopeUsersRead, APIScopeUsersWrite}
+
+const (
+	CodeAPIKeyNotFound     ErrorCode = "api_key_not_found"
+	CodeAPIKeyNameInvalid  ErrorCode = "api_key_name_invalid"
+	CodeAPIKeyScopeInvalid ErrorCode = "api_key_scope_invalid"
+	CodeAPIKeyExpiryPast   ErrorCode = "api_key_expiry_in_past"
+	CodeAPIKeyInactive     ErrorCode = "api_key_inactive"
+	CodeAPIKeyRotated      ErrorCode = "api_key_already_rotated"
+)
+
+const maxAPIKeyNameLength = 64
+
+var (
+	ErrAPIKeyNotFound    = NewNotFoundError(CodeAPIKeyNotFound, "API key not found")
+	ErrAPIKeyNameInvalid = NewValidationError(CodeAPIKeyNameInvalid, "name", "name must be 1 to 64 characters").
+				WithParams(map[string]any{"max": maxAPIKeyNameLength})
+	ErrAPIKeyScopeInvalid = NewValidationError(CodeAPIKeyScopeInvalid, "scopes", "scopes must list one or more of: users:read, users:write").
+				WithParams(map[string]any{"allowed": apiScopes})
+	ErrAPIKeyExpiryPast = NewValidationError(CodeAPIKeyExpiryPast, "expires_at", "expires_at must be in the future")
+	ErrAPIKeyInactive   = NewConflictError(CodeAPIKeyInactive, "", "the API key has expired")
+	ErrAPIKeyRotated    = NewConflictError(CodeAPIKeyRotated, "", "the API key has already been rotated")
+)
+
+type APIKeyID string
+
+func NewAPIKeyID() APIKeyID {
+	return APIKeyID(uuid.New().String())
+}
+
+func (id APIKeyID) String() string {
+	return string(id)
+}
+
+// APIKey lets a user or service account call the API without signing in.
+// Only a hash of the secret is stored; Prefix is the start of the key, kept
+// so that owners can tell their keys apart.
+type APIKey struct {
+	ID         APIKeyID
+	OwnerID    UserID
+	TenantID   TenantID
+	Name       string
+	Prefix     string
+	SecretHash string
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	// ReplacedBy is the key this one was rotated to.
+	ReplacedBy APIKeyID
+}
+
+// NewAPIKey validates the key's settings and reports all failures together.
+func NewAPIKey(tenant TenantID, ownerID UserID, name string, scopes []string, expiresAt *time.Time, prefix, secretHash string, now time.Time) (*APIKey, error) {
+	var errs ValidationErrors
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxAPIKeyNameLength {
+		errs.Add(ErrAPIKeyNameInvalid)
+	}
+	scopes, err := normalizeAPIScopes(scopes)
+	errs.Add(err)
+	if expiresAt != nil && !expiresAt.After(now) {
+		errs.Add(ErrAPIKeyExpiryPast)
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	key := &APIKey{
+		ID:         NewAPIKeyID(),
+		OwnerID:    ownerID,
+		TenantID:   tenant,
+		Name:       name,
+		Prefix:     prefix,
+		SecretHash: secretHash,
+		Scopes:     scopes,
+		CreatedAt:  now.UTC(),
+	}
+	if expiresAt != nil {
+		expires := expiresAt.UTC()
+		key.ExpiresAt = &expires
+	}
+	return key, nil
+}
+
+func (k *APIKey) Active(now time.Time) bool {
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// CanRotate reports whether the key may be replaced: it must still work and
+// not have been rotated already.
+func (k *APIKey) CanRotate(now time.Time) error {
+	if !k.Active(now) {
+		return ErrAPIKeyInactive
+	}
+	if k.ReplacedBy != "" {
+		return ErrAPIKeyRotated
+	}
+	return nil
+}
+
+// Retire records the key that replaces this one and lets this one keep
+// working for the overlap, so clients can switch over. It never extends the
+// key's lifetime.
+func (k *APIKey) Retire(replacement APIKeyID, now time.Time, overlap time.Duration) error {
+	if err := k.CanRotate(now); err != nil {
+		return err
+	}
+	until := now.Add(overlap).UTC()
+	if k.ExpiresAt == nil || until.Before(*k.ExpiresAt) {
+		k.ExpiresAt = &until
+	}
+	k.ReplacedBy = replacement
+	return nil
+}
+
+func normalizeAPIScopes(scopes []string) ([]string, error) {
+	var normalized []string
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !slices.Contains(apiScopes, scope) {
+			return nil, ErrAPIKeyScopeInvalid
+		}
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, ErrAPIKeyScopeInvalid
+	}
+	return normalized, nil
+}
@@ -0,0 +1,318 @@
+package domain
+
+import (
+	"encoding/json"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	CodeAttributeNotFound        ErrorCode = "attribute_not_found"
+	CodeAttributeExists          ErrorCode = "attribute_exists"
+	CodeAttributeNameInvalid     ErrorCode = "attribute_name_invalid"
+	CodeAttributeTypeInvalid     ErrorCode = "attribute_type_invalid"
+	CodeAttributeSchemaInvalid   ErrorCode = "attribute_schema_invalid"
+	CodeAttributeUnknown         ErrorCode = "attribute_unknown"
+	CodeAttributeRequired        ErrorCode = "attribute_required"
+	CodeAttributeValueInvalid    ErrorCode = "attribute_value_invalid"
+	CodeAttributePatternMismatch ErrorCode = "attribute_pattern_mismatch"
+	CodeAttributeValueNotAllowed ErrorCode = "attribute_value_not_allowed"
+	CodeAttributeValueExists     ErrorCode = "attribute_value_exists"
+)
+
+const (
+	maxAttributeStringLength = 1024
+	attributeFieldPrefix     = "attributes."
+)
+
+var (
+	// ErrAttributeValueExists matches the errors of AttributeValueExists.
+	ErrAttributeValueExists = NewConflictError(CodeAttributeValueExists, "", "another user already has this value")
+	ErrAttributeNotFound    = NewNotFoundError(CodeAttributeNotFound, "attribute not found")
+	ErrAttributeExists      = NewConflictError(CodeAttributeExists, "name", "an attribute with this name is already defined")
+	ErrAttributeNameInvalid = NewValidationError(CodeAttributeNameInvalid, "name",
+		"name must start with a lowercase letter and contain only lowercase letters, digits and underscores, up to 40 characters")
+	ErrAttributeTypeInvalid = NewValidationError(CodeAttributeTypeInvalid, "type", "type must be one of: string, integer, number, boolean").
+				WithParams(map[string]any{"allowed": attributeTypes})
+)
+
+var attributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+// AttributeType is the type of a custom attribute's values. Values are held
+// as string, int64, float64 or bool respectively.
+type AttributeType string
+
+const (
+	AttributeTypeString  AttributeType = "string"
+	AttributeTypeInteger AttributeType = "integer"
+	AttributeTypeNumber  AttributeType = "number"
+	AttributeTypeBoolean AttributeType = "boolean"
+)
+
+var attributeTypes = []AttributeType{AttributeTypeString, AttributeTypeInteger, AttributeTypeNumber, AttributeTypeBoolean}
+
+func ParseAttributeType(raw string) (AttributeType, error) {
+	if t := AttributeType(strings.ToLower(strings.TrimSpace(raw))); slices.Contains(attributeTypes, t) {
+		return t, nil
+	}
+	return "", ErrAttributeTypeInvalid
+}
+
+// AttributeSchema defines a custom attribute an administrator added to the
+// users of a tenant.
+type AttributeSchema struct {
+	TenantID TenantID
+	Name     string
+	Type     AttributeType
+	// Required attributes must be given when a user is created or updated.
+	Required bool
+	// Unique attributes cannot hold the same value for two users.
+	Unique bool
+	// Pattern and Enum restrict string values. Pattern must match the
+	// whole value.
+	Pattern   string
+	Enum      []string
+	CreatedAt time.Time
+
+	pattern *regexp.Regexp
+}
+
+// NewAttributeSchema validates the definition, reporting every problem
+// together.
+func NewAttributeSchema(tenant TenantID, name string, attributeType string, required, unique bool, pattern string, enum []string, now time.Time) (*AttributeSchema, error) {
+	var errs ValidationErrors
+	if !attributeNamePattern.MatchString(name) {
+		errs.Add(ErrAttributeNameInvalid)
+	}
+	t, err := ParseAttributeType(attributeType)
+	errs.Add(err)
+
+	schema := &AttributeSchema{
+		TenantID:  tenant,
+		Name:      name,
+		Type:      t,
+		Required:  required,
+		Unique:    unique,
+		Pattern:   pattern,
+		Enum:      slices.Clone(enum),
+		CreatedAt: now,
+	}
+	if err == nil && t != AttributeTypeString && (pattern != "" || len(enum) > 0) {
+		errs.Add(NewValidationError(CodeAttributeSchemaInvalid, "type", "pattern and enum apply only to string attributes"))
+	}
+	if err := schema.compile(); err != nil {
+		errs.Add(err)
+	}
+	for _, value := range enum {
+		if value == "" || utf8.RuneCountInString(value) > maxAttributeStringLength {
+			errs.Add(NewValidationError(CodeAttributeSchemaInvalid, "enum", "enum values must be 1 to 1024 characters"))
+			break
+		}
+	}
+
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func (s *AttributeSchema) compile() error {
+	if s.Pattern == "" || s.pattern != nil {
+		return nil
+	}
+	re, err := regexp.Compile(`^(?:` + s.Pattern + `)$`)
+	if err != nil {
+		return NewValidationError(CodeAttributeSchemaInvalid, "pattern", "pattern is not a valid regular expression")
+	}
+	s.pattern = re
+	return nil
+}
+
+// Field is the name errors about the attribute's value are reported under.
+func (s *AttributeSchema) Field() string {
+	return attributeFieldPrefix + s.Name
+}
+
+// Parse checks a value decoded from JSON and returns it in canonical form.
+func (s *AttributeSchema) Parse(raw any) (any, error) {
+	var value any
+	switch s.Type {
+	case AttributeTypeString:
+		if v, ok := raw.(string); ok {
+			value = v
+		}
+	case AttributeTypeInteger:
+		if f, ok := toFloat(raw); ok && f == math.Trunc(f) && math.Abs(f) <= 1<<53 {
+			value = int64(f)
+		}
+	case AttributeTypeNumber:
+		if f, ok := toFloat(raw); ok {
+			value = f
+		}
+	case AttributeTypeBoolean:
+		if v, ok := raw.(bool); ok {
+			value = v
+		}
+	}
+	if value == nil {
+		return nil, s.valueInvalid()
+	}
+	return value, s.check(value)
+}
+
+// ParseText reads a value written as text, as in a query string.
+func (s *AttributeSchema) ParseText(raw string) (any, error) {
+	var value any
+	switch s.Type {
+	case AttributeTypeString:
+		value = raw
+	case AttributeTypeInteger:
+		if v, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			value = v
+		}
+	case AttributeTypeNumber:
+		if v, err := strconv.ParseFloat(raw, 64); err == nil && !math.IsInf(v, 0) && !math.IsNaN(v) {
+			value = v
+		}
+	case AttributeTypeBoolean:
+		if v, err := strconv.ParseBool(raw); err == nil {
+			value = v
+		}
+	}
+	if value == nil {
+		return nil, s.valueInvalid()
+	}
+	return value, nil
+}
+
+func (s *AttributeSchema) check(value any) error {
+	text, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	if utf8.RuneCountInString(text) > maxAttributeStringLength {
+		return NewValidationError(CodeTooLong, s.Field(), s.Field()+" must be at most "+strconv.Itoa(maxAttributeStringLength)+" characters").
+			WithParams(map[string]any{"max": maxAttributeStringLength})
+	}
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, text) {
+		return NewValidationError(CodeAttributeValueNotAllowed, s.Field(), s.Field()+" must be one of the allowed values").
+			WithParams(map[string]any{"allowed": s.Enum})
+	}
+	if err := s.compile(); err != nil {
+		return err
+	}
+	if s.pattern != nil && !s.pattern.MatchString(text) {
+		return NewValidationError(CodeAttributePatternMismatch, s.Field(), s.Field()+" does not match the required pattern").
+			WithParams(map[string]any{"pattern": s.Pattern})
+	}
+	return nil
+}
+
+func (s *AttributeSchema) valueInvalid() error {
+	return NewValidationError(CodeAttributeValueInvalid, s.Field(), s.Field()+" must be of type "+string(s.Type)).
+		WithParams(map[string]any{"type": s.Type})
+}
+
+// AttributeValueExists reports a unique attribute value another user holds.
+func AttributeValueExists(name string) error {
+	return NewConflictError(CodeAttributeValueExists, attributeFieldPrefix+name, "another user already has this "+name)
+}
+
+// AttributeFieldName returns the attribute an error's field refers to.
+func AttributeFieldName(field string) (string, bool) {
+	return strings.CutPrefix(field, attributeFieldPrefix)
+}
+
+// SetAttributes replaces the user's custom attributes after checking them
+// against the tenant's schemas. A null value counts as absent.
+func (u *User) SetAttributes(schemas []*AttributeSchema, raw map[string]any) error {
+	var errs ValidationErrors
+	values := make(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(raw)) {
+		value := raw[name]
+		if value == nil {
+			continue
+		}
+		schema := findAttributeSchema(schemas, name)
+		if schema == nil {
+			errs.Add(attributeUnknown(name))
+			continue
+		}
+		parsed, err := schema.Parse(value)
+		if err != nil {
+			errs.Add(err)
+			continue
+		}
+		values[name] = parsed
+	}
+	for _, schema := range schemas {
+		if _, ok := values[schema.Name]; schema.Required && !ok && raw[schema.Name] == nil {
+			errs.Add(NewValidationError(CodeAttributeRequired, schema.Field(), schema.Field()+" is required"))
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	if len(values) == 0 {
+		values = nil
+	}
+	u.Attributes = values
+	return nil
+}
+
+// ParseAttributeText reads attribute values written as text, as in a query
+// string, and returns them in canonical form.
+func ParseAttributeText(schemas []*AttributeSchema, raw map[string]string) (map[string]any, error) {
+	var errs ValidationErrors
+	values := make(map[string]any, len(raw))
+	for _, name := range slices.Sorted(maps.Keys(raw)) {
+		schema := findAttributeSchema(schemas, name)
+		if schema == nil {
+			errs.Add(attributeUnknown(name))
+			continue
+		}
+		value, err := schema.ParseText(raw[name])
+		errs.Add(err)
+		values[name] = value
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func findAttributeSchema(schemas []*AttributeSchema, name string) *AttributeSchema {
+	i := slices.IndexFunc(schemas, func(s *AttributeSchema) bool { return s.Name == name })
+	if i < 0 {
+		return nil
+	}
+	return schemas[i]
+}
+
+func attributeUnknown(name string) error {
+	return NewValidationError(CodeAttributeUnknown, attributeFieldPrefix+name, "no attribute named "+name+" is defined")
+}
+
+func toFloat(raw any) (float64, bool) {
+	switch v := raw.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
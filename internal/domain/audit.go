@@ -0,0 +1,191 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const CodeAuditSequenceTaken ErrorCode = "audit_sequence_taken"
+
+// ErrAuditSequenceTaken is returned by AuditRepository.Append when another
+// entry was appended first; the entry must be rebuilt on the new last one.
+var ErrAuditSequenceTaken = NewConflictError(CodeAuditSequenceTaken, "", "another audit entry was appended first")
+
+type AuditAction string
+
+const (
+	AuditUserCreated    AuditAction = "user.created"
+	AuditUserUpdated    AuditAction = "user.updated"
+	AuditUserDeleted    AuditAction = "user.deleted"
+	AuditUserSuspended  AuditAction = "user.suspended"
+	AuditUserReinstated AuditAction = "user.reinstated"
+)
+
+// AuditActor is who made a change. Fields are empty when unknown, such as
+// the user for anonymous requests.
+type AuditActor struct {
+	UserID    string `json:"user_id"`
+	APIKeyID  string `json:"api_key_id"`
+	RequestID string `json:"request_id"`
+	IP        string `json:"ip"`
+}
+
+// FieldChange holds a field's JSON values before and after a change; a nil
+// value means the field was unset.
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// AuditEntry is one change to a user. Each tenant's entries form a chain:
+// Sequence counts up from 1 and Hash covers the entry together with the
+// previous entry's Hash, so altering or removing an entry breaks every hash
+// after it. Hashes are keyed, so whoever can write to the store cannot
+// forge a consistent chain without the key as well.
+type AuditEntry struct {
+	ID         string
+	TenantID   TenantID
+	Sequence   int64
+	UserID     UserID
+	Action     AuditAction
+	Actor      AuditActor
+	Changes    []FieldChange
+	OccurredAt time.Time
+	PrevHash   string
+	Hash       string
+}
+
+// NewAuditEntry chains a new entry after prev, which is nil for the
+// tenant's first entry, and hashes it with key.
+func NewAuditEntry(key []byte, prev *AuditEntry, tenant TenantID, userID UserID, action AuditAction, actor AuditActor, changes []FieldChange, at time.Time) *AuditEntry {
+	entry := &AuditEntry{
+		ID:       uuid.New().String(),
+		TenantID: tenant,
+		Sequence: 1,
+		UserID:   userID,
+		Action:   action,
+		Actor:    actor,
+		Changes:  changes,
+		// Stores keep milliseconds; the hash must survive the round trip.
+		OccurredAt: at.UTC().Truncate(time.Millisecond),
+	}
+	if prev != nil {
+		entry.Sequence = prev.Sequence + 1
+		entry.PrevHash = prev.Hash
+	}
+	entry.Hash = entry.ComputeHash(key)
+	return entry
+}
+
+// ComputeHash returns the hex HMAC-SHA-256 under key of the entry's content
+// and PrevHash.
+func (e *AuditEntry) ComputeHash(key []byte) string {
+	content, _ := json.Marshal(struct {
+		PrevHash   string        `json:"prev_hash"`
+		ID         string        `json:"id"`
+		TenantID   TenantID      `json:"tenant_id"`
+		Sequence   int64         `json:"sequence"`
+		UserID     UserID        `json:"user_id"`
+		Action     AuditAction   `json:"action"`
+		Actor      AuditActor    `json:"actor"`
+		Changes    []FieldChange `json:"changes"`
+		OccurredAt string        `json:"occurred_at"`
+	}{e.PrevHash, e.ID, e.TenantID, e.Sequence, e.UserID, e.Action, e.Actor, e.Changes, e.OccurredAt.UTC().Format(time.RFC3339Nano)})
+	mac := hmac.New(sha256.New, key)
+	mac.Write(content)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyAuditChain checks a tenant's entries, oldest first, against key. It
+// returns the first sequence at which an entry was changed, removed or
+// inserted, or 0 when the chain is intact.
+func VerifyAuditChain(key []byte, entries []*AuditEntry) int64 {
+	prevHash := ""
+	for i, entry := range entries {
+		sequence := int64(i) + 1
+		if entry.Sequence != sequence || entry.PrevHash != prevHash || !hmac.Equal([]byte(entry.ComputeHash(key)), []byte(entry.Hash)) {
+			return sequence
+		}
+		prevHash = entry.Hash
+	}
+	return 0
+}
+
+// DiffUser lists the fields that differ between two versions of a user,
+// ordered by name. before is nil for a new user and after for a deleted
+// one. Secrets are left out: a password change shows as
+// password_changed_at, and MFA factors only by whether they are enabled.
+func DiffUser(before, after *User) []FieldChange {
+	old, updated := auditSnapshot(before), auditSnapshot(after)
+	fields := maps.Clone(old)
+	maps.Copy(fields, updated)
+
+	var changes []FieldChange
+	for _, field := range slices.Sorted(maps.Keys(fields)) {
+		if !bytes.Equal(old[field], updated[field]) {
+			changes = append(changes, FieldChange{Field: field, Before: old[field], After: updated[field]})
+		}
+	}
+	return changes
+}
+
+// auditSnapshot encodes the user's audited fields, leaving out those with
+// zero values.
+func auditSnapshot(user *User) map[string]json.RawMessage {
+	snapshot := make(map[string]json.RawMessage)
+	if user == nil {
+		return snapshot
+	}
+
+	set := func(field string, value any, zero bool) {
+		if !zero {
+			snapshot[field], _ = json.Marshal(value)
+		}
+	}
+	set("name", user.Name, user.Name == "")
+	set("email", user.Email, user.Email == "")
+	set("username", user.Username, user.Username == "")
+	set("pending_email", user.PendingEmail, user.PendingEmail == "")
+	set("email_verified", user.EmailVerified, !user.EmailVerified)
+	set("role", user.Role, user.Role == "")
+	set("suspended", user.Suspended(), !user.Suspended())
+	set("mfa_enabled", user.MFAEnabled(), !user.MFAEnabled())
+	if user.PasswordChangedAt != nil {
+		set("password_changed_at", user.PasswordChangedAt.UTC().Truncate(time.Millisecond), false)
+	}
+
+	passkeys := make([]string, len(user.WebAuthnCredentials))
+	for i, credential := range user.WebAuthnCredentials {
+		passkeys[i] = credential.Name
+	}
+	set("passkeys", passkeys, len(passkeys) == 0)
+
+	profile := map[string]string{
+		"display_name": user.Profile.DisplayName,
+		"given_name":   user.Profile.GivenName,
+		"family_name":  user.Profile.FamilyName,
+		"phone":        user.Profile.Phone,
+		"locale":       user.Profile.Locale,
+		"timezone":     user.Profile.Timezone,
+		"bio":          user.Profile.Bio,
+		"avatar_url":   user.Profile.AvatarURL,
+	}
+	for name, value := range profile {
+		set("profile."+name, value, value == "")
+	}
+
+	for name, value := range user.Attributes {
+		set(attributeFieldPrefix+name, value, false)
+	}
+	set("tags", user.Tags, len(user.Tags) == 0)
+	return snapshot
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifyAuditChain(t *testing.T) {
+	key := []byte("audit-key")
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	chain := func() []*AuditEntry {
+		var entries []*AuditEntry
+		var prev *AuditEntry
+		for _, action := range []AuditAction{AuditUserCreated, AuditUserUpdated, AuditUserDeleted} {
+			prev = NewAuditEntry(key, prev, DefaultTenantID, "u-1", action, AuditActor{}, []FieldChange{{Field: "name"}}, at)
+			entries = append(entries, prev)
+		}
+		return entries
+	}
+
+	tests := []struct {
+		name   string
+		key    []byte
+		tamper func([]*AuditEntry) []*AuditEntry
+		want   int64
+	}{
+		{"intact", key, func(e []*AuditEntry) []*AuditEntry { return e }, 0},
+		{"changed entry", key, func(e []*AuditEntry) []*AuditEntry {
+			e[1].Action = AuditUserSuspended
+			return e
+		}, 2},
+		{"removed entry", key, func(e []*AuditEntry) []*AuditEntry {
+			return append(e[:1], e[2:]...)
+		}, 2},
+		{"rehashed without the key", key, func(e []*AuditEntry) []*AuditEntry {
+			e[1].Action = AuditUserSuspended
+			for i := 1; i < len(e); i++ {
+				e[i].PrevHash = e[i-1].Hash
+				e[i].Hash = e[i].ComputeHash([]byte("guessed-key"))
+			}
+			return e
+		}, 2},
+		{"verified with another key", []byte("other-key"), func(e []*AuditEntry) []*AuditEntry { return e }, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyAuditChain(tt.key, tt.tamper(chain())); got != tt.want {
+				t.Fatalf("VerifyAuditChain = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// Avatar is an uploaded profile picture. Its images, one per size, are kept
+// in blob storage under the ID; a new upload gets a new ID, so the images
+// behind an ID never change.
+type Avatar struct {
+	ID          string
+	ContentType string
+	// Sizes are the edges, in pixels, of the square images stored.
+	Sizes      []int
+	UploadedAt time.Time
+}
+
+// SetAvatar records an uploaded avatar and points the profile at it. It
+// returns the avatar it replaces, if any, so its images can be removed.
+func (u *User) SetAvatar(avatar Avatar, url string) (*Avatar, error) {
+	profile := u.Profile
+	profile.AvatarURL = url
+	parsed, err := ParseProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := u.Avatar
+	u.Avatar = &avatar
+	u.Profile = parsed
+	return previous, nil
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// skeletonVersion changes whenever Skeleton maps usernames differently, so
+// stored skeletons are rebuilt.
+const skeletonVersion = 1
+
+// confusables maps characters to the Latin letter they are easily mistaken
+// for. It covers the common Cyrillic and Greek lookalikes and the digits
+// usually swapped for letters, not the whole Unicode confusables table.
+var confusables = map[rune]rune{
+	'0': 'o',
+	'1': 'l',
+	'ı': 'i',
+	'ɡ': 'g',
+	'ɩ': 'i',
+	// Cyrillic
+	'а': 'a',
+	'с': 'c',
+	'ԁ': 'd',
+	'е': 'e',
+	'һ': 'h',
+	'і': 'i',
+	'ј': 'j',
+	'ӏ': 'l',
+	'о': 'o',
+	'р': 'p',
+	'ԛ': 'q',
+	'ѕ': 's',
+	'ԝ': 'w',
+	'х': 'x',
+	'у': 'y',
+	// Greek
+	'α': 'a',
+	'ι': 'i',
+	'κ': 'k',
+	'ο': 'o',
+	'ρ': 'p',
+	'υ': 'u',
+	'ν': 'v',
+	'χ': 'x',
+	'γ': 'y',
+}
+
+// confusableSequences are letter pairs that read as a single letter.
+var confusableSequences = strings.NewReplacer("rn", "m", "vv", "w")
+
+// Skeleton is the form shared by usernames that look alike. Accents and
+// separators are dropped and lookalike characters replaced, so "jane.doe",
+// "janedoe", "jáne_doe" and "jаnedoe" with a Cyrillic "а" all have the
+// skeleton "janedoe".
+func (u Username) Skeleton() string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(string(u)) {
+		if unicode.IsMark(r) || r == '.' || r == '_' || r == '-' {
+			continue
+		}
+		if c, ok := confusables[r]; ok {
+			r = c
+		}
+		b.WriteRune(r)
+	}
+	return confusableSequences.Replace(b.String())
+}
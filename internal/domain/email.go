@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	maxEmailLength      = 254
+	maxEmailLocalLength = 64
+)
+
+const CodeEmailDomainBlocked ErrorCode = "email_domain_blocked"
+
+var ErrEmailDomainBlocked = NewValidationError(CodeEmailDomainBlocked, "email", "email addresses at this domain are not accepted")
+
+// emailLocalSymbols are the characters besides letters and digits allowed
+// in the local part of an unquoted address (RFC 5322 atext, plus dots).
+const emailLocalSymbols = "!#$%&'*+/=?^_`{|}~-."
+
+var emailDomainProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.VerifyDNSLength(true),
+)
+
+// EmailRules decide which addresses count as the same. Set them once at
+// start up with SetEmailRules.
+type EmailRules struct {
+	// FoldPlusAddressing treats "jane+news@example.com" as the same address
+	// as "jane@example.com". The tag is kept for delivery.
+	FoldPlusAddressing bool
+}
+
+var emailRules EmailRules
+
+// SetEmailRules must be called before any address is parsed or compared.
+func SetEmailRules(rules EmailRules) {
+	emailRules = rules
+}
+
+// IdentifierKeysVersion names the rules Email.Key and Username.Skeleton
+// currently apply. Stores that index the keys compare it with the version
+// they were built under to know when to rebuild them.
+func IdentifierKeysVersion() string {
+	return fmt.Sprintf("fold_plus=%t,skeleton=%d", emailRules.FoldPlusAddressing, skeletonVersion)
+}
+
+// Email is an address in canonical form: NFKC-normalized, lower case, with
+// the domain in its ASCII (IDNA) form. Values built with ParseEmail can be
+// stored and compared directly; use Equal or Key where plus-address folding
+// applies.
+type Email string
+
+// ParseEmail validates an address and returns its canonical form. Quoted
+// local parts and IP literal domains are not accepted.
+func ParseEmail(raw string) (Email, error) {
+	address := norm.NFKC.String(strings.TrimSpace(raw))
+	at := strings.LastIndexByte(address, '@')
+	if at <= 0 || at == len(address)-1 {
+		return "", ErrInvalidEmail
+	}
+
+	local := strings.ToLower(address[:at])
+	if !validEmailLocalPart(local) {
+		return "", ErrInvalidEmail
+	}
+	host, err := ParseEmailDomain(address[at+1:])
+	if err != nil {
+		return "", err
+	}
+
+	email := local + "@" + host
+	if len(email) > maxEmailLength {
+		return "", ErrInvalidEmail
+	}
+	return Email(email), nil
+}
+
+// ParseEmailDomain validates the domain of an address and returns its
+// canonical ASCII form.
+func ParseEmailDomain(raw string) (string, error) {
+	host, err := emailDomainProfile.ToASCII(norm.NFKC.String(strings.TrimSpace(raw)))
+	if err != nil || !validEmailDomain(host) {
+		return "", ErrInvalidEmail
+	}
+	return host, nil
+}
+
+func (e Email) String() string {
+	return string(e)
+}
+
+func (e Email) Domain() string {
+	return string(e)[strings.LastIndexByte(string(e), '@')+1:]
+}
+
+// Key is the form addresses are compared and indexed by.
+func (e Email) Key() string {
+	if !emailRules.FoldPlusAddressing {
+		return string(e)
+	}
+	at := strings.LastIndexByte(string(e), '@')
+	if at < 0 {
+		return string(e)
+	}
+	local, host := string(e)[:at], string(e)[at:]
+	if tagless, _, found := strings.Cut(local, "+"); found && tagless != "" {
+		return tagless + host
+	}
+	return string(e)
+}
+
+func (e Email) Equal(other Email) bool {
+	return e.Key() == other.Key()
+}
+
+func validEmailLocalPart(local string) bool {
+	if len(local) > maxEmailLocalLength || strings.HasPrefix(local, ".") || strings.HasSuffix(local, ".") || strings.Contains(local, "..") {
+		return false
+	}
+	for _, r := range local {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) && !strings.ContainsRune(emailLocalSymbols, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// validEmailDomain expects the ASCII form. It requires at least two labels
+// and an alphabetic or IDNA top-level domain.
+func validEmailDomain(host string) bool {
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	tld := labels[len(labels)-1]
+	if strings.HasPrefix(tld, "xn--") {
+		return true
+	}
+	if len(tld) < 2 {
+		return false
+	}
+	for _, r := range tld {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// withEmailRules sets the rules for the rest of the test.
+func withEmailRules(t *testing.T, rules EmailRules) {
+	previous := emailRules
+	SetEmailRules(rules)
+	t.Cleanup(func() { SetEmailRules(previous) })
+}
+
+func TestParseEmail(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want Email
+	}{
+		{"canonical", "jane@example.com", "jane@example.com"},
+		{"trimmed and lower-cased", "  Jane.Doe@Example.COM ", "jane.doe@example.com"},
+		{"full-width characters", "ｊａｎｅ@ｅｘａｍｐｌｅ.ｃｏｍ", "jane@example.com"},
+		{"international domain", "jane@bücher.example", "jane@xn--bcher-kva.example"},
+		{"international top-level domain", "jane@пример.рф", "jane@xn--e1afmkfd.xn--p1ai"},
+		{"unicode local part", "José@example.com", "josé@example.com"},
+		{"plus address kept", "jane+news@example.com", "jane+news@example.com"},
+		{"symbols", "o'neil_{x}@example.com", "o'neil_{x}@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEmail(tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseEmail(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEmailRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"no at sign", "jane.example.com"},
+		{"no local part", "@example.com"},
+		{"no domain", "jane@"},
+		{"single label domain", "jane@localhost"},
+		{"one letter top-level domain", "jane@example.c"},
+		{"numeric top-level domain", "jane@example.123"},
+		{"IP literal", "jane@[192.0.2.1]"},
+		{"quoted local part", `"jane doe"@example.com`},
+		{"space", "jane doe@example.com"},
+		{"leading dot", ".jane@example.com"},
+		{"trailing dot", "jane.@example.com"},
+		{"consecutive dots", "ja..ne@example.com"},
+		{"local part too long", strings.Repeat("a", maxEmailLocalLength+1) + "@example.com"},
+		{"address too long", "jane@" + strings.Repeat("a", 63) + "." + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + "." + strings.Repeat("d", 57) + ".com"},
+		{"invalid domain label", "jane@exa_mple.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := ParseEmail(tt.raw); !errors.Is(err, ErrInvalidEmail) {
+				t.Fatalf("ParseEmail(%q) = %q, %v, want %v", tt.raw, got, err, ErrInvalidEmail)
+			}
+		})
+	}
+}
+
+func TestParseEmailDomain(t *testing.T) {
+	tests := []struct {
+		raw, want string
+		valid     bool
+	}{
+		{"example.com", "example.com", true},
+		{" EXAMPLE.com ", "example.com", true},
+		{"bücher.example", "xn--bcher-kva.example", true},
+		{"BÜCHER.example", "xn--bcher-kva.example", true},
+		{"xn--bcher-kva.example", "xn--bcher-kva.example", true},
+		{"ｅｘａｍｐｌｅ．ｃｏｍ", "example.com", true},
+		{"example", "", false},
+		{"-example.com", "", false},
+		{"exam ple.com", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseEmailDomain(tt.raw)
+			if !tt.valid {
+				if !errors.Is(err, ErrInvalidEmail) {
+					t.Fatalf("ParseEmailDomain(%q) = %q, %v, want %v", tt.raw, got, err, ErrInvalidEmail)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("ParseEmailDomain(%q) = %q, %v, want %q", tt.raw, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailKey(t *testing.T) {
+	tests := []struct {
+		email          Email
+		key, foldedKey string
+	}{
+		{"jane@example.com", "jane@example.com", "jane@example.com"},
+		{"jane+news@example.com", "jane+news@example.com", "jane@example.com"},
+		{"jane+a+b@example.com", "jane+a+b@example.com", "jane@example.com"},
+		// Without anything before the tag there is nothing to fold to.
+		{"+news@example.com", "+news@example.com", "+news@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email.String(), func(t *testing.T) {
+			withEmailRules(t, EmailRules{})
+			if got := tt.email.Key(); got != tt.key {
+				t.Errorf("Key() = %q, want %q", got, tt.key)
+			}
+			withEmailRules(t, EmailRules{FoldPlusAddressing: true})
+			if got := tt.email.Key(); got != tt.foldedKey {
+				t.Errorf("Key() with folding = %q, want %q", got, tt.foldedKey)
+			}
+		})
+	}
+}
+
+func TestEmailEqual(t *testing.T) {
+	tests := []struct {
+		a, b          Email
+		equal, folded bool
+	}{
+		{"jane@example.com", "jane@example.com", true, true},
+		{"jane+news@example.com", "jane@example.com", false, true},
+		{"jane+news@example.com", "jane+shop@example.com", false, true},
+		{"jane+news@example.com", "jane@example.org", false, false},
+		{"jane@example.com", "john@example.com", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a.String()+" "+tt.b.String(), func(t *testing.T) {
+			withEmailRules(t, EmailRules{})
+			if got := tt.a.Equal(tt.b); got != tt.equal {
+				t.Errorf("Equal() = %v, want %v", got, tt.equal)
+			}
+			withEmailRules(t, EmailRules{FoldPlusAddressing: true})
+			if got := tt.a.Equal(tt.b); got != tt.folded {
+				t.Errorf("Equal() with folding = %v, want %v", got, tt.folded)
+			}
+		})
+	}
+}
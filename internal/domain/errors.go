@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+type ErrorCode string
+
+type ErrorKind int
+
+const (
+	KindValidation ErrorKind = iota + 1
+	KindNotFound
+	KindConflict
+	KindUnauthenticated
+	KindForbidden
+	// KindTooManyAttempts errors may carry a "retry_after" param in whole
+	// seconds.
+	KindTooManyAttempts
+)
+
+// Error is the typed error returned by the domain. Code is stable and meant
+// for machines; Message is for humans. Two Errors match under errors.Is when
+// their codes are equal, so a sentinel still matches a copy carrying params.
+type Error struct {
+	Kind    ErrorKind
+	Code    ErrorCode
+	Field   string
+	Message string
+	Params  map[string]any
+}
+
+func NewValidationError(code ErrorCode, field, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Field: field, Message: message}
+}
+
+func NewNotFoundError(code ErrorCode, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func NewConflictError(code ErrorCode, field, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Field: field, Message: message}
+}
+
+func NewUnauthenticatedError(code ErrorCode, message string) *Error {
+	return &Error{Kind: KindUnauthenticated, Code: code, Message: message}
+}
+
+func NewForbiddenError(code ErrorCode, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+func NewTooManyAttemptsError(code ErrorCode, message string) *Error {
+	return &Error{Kind: KindTooManyAttempts, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithParams returns a copy of e carrying the given params.
+func (e *Error) WithParams(params map[string]any) *Error {
+	clone := *e
+	clone.Params = params
+	return &clone
+}
+
+// WithMessage returns a copy of e with a more specific message.
+func (e *Error) WithMessage(message string) *Error {
+	clone := *e
+	clone.Message = message
+	return &clone
+}
+
+// WithField returns a copy of e attributed to the given field.
+func (e *Error) WithField(field string) *Error {
+	clone := *e
+	clone.Field = field
+	return &clone
+}
+
+const CodeValidationFailed ErrorCode = "validation_failed"
+
+// ValidationErrors aggregates every validation failure found in one
+// operation so they can be reported together.
+type ValidationErrors []*Error
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, err := range v {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (v ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(v))
+	for i, err := range v {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Add records err if it is non-nil. Domain errors are kept as-is, nested
+// ValidationErrors are flattened.
+func (v *ValidationErrors) Add(err error) {
+	if err == nil {
+		return
+	}
+	var nested ValidationErrors
+	if errors.As(err, &nested) {
+		*v = append(*v, nested...)
+		return
+	}
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		*v = append(*v, domainErr)
+		return
+	}
+	*v = append(*v, &Error{Kind: KindValidation, Code: CodeValidationFailed, Message: err.Error()})
+}
+
+// Err returns nil when no failures were recorded.
+func (v ValidationErrors) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
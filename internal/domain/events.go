@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// Event is something that happened to an aggregate. Aggregates record events
+// as they change; the application publishes them once the change is stored.
+type Event interface {
+	EventName() string
+}
+
+const (
+	EventPasswordChanged = "user.password_changed"
+	EventUserSuspended   = "user.suspended"
+	EventAccountLocked   = "user.account_locked"
+	EventUserDeleted     = "user.deleted"
+)
+
+// PasswordChanged is recorded whenever a user's password is set or replaced.
+// Anything derived from the old credentials, such as sessions, should be
+// revoked in response.
+type PasswordChanged struct {
+	UserID     UserID
+	OccurredAt time.Time
+}
+
+func (PasswordChanged) EventName() string {
+	return EventPasswordChanged
+}
+
+// UserSuspended is recorded when an administrator suspends an account.
+type UserSuspended struct {
+	UserID     UserID
+	OccurredAt time.Time
+}
+
+func (UserSuspended) EventName() string {
+	return EventUserSuspended
+}
+
+// AccountLocked is raised when repeated failed logins lock an account
+// temporarily.
+type AccountLocked struct {
+	UserID      UserID
+	LockedUntil time.Time
+	OccurredAt  time.Time
+}
+
+func (AccountLocked) EventName() string {
+	return EventAccountLocked
+}
+
+// UserDeleted is raised once a user has been removed. Data that refers to
+// the user elsewhere, such as memberships, should be cleaned up in response.
+type UserDeleted struct {
+	UserID   UserID
+	TenantID TenantID
+	// Avatar is the user's profile picture, if any, whose images are no
+	// longer referenced.
+	Avatar     *Avatar
+	OccurredAt time.Time
+}
+
+func (UserDeleted) EventName() string {
+	return EventUserDeleted
+}
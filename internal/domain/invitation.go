@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CodeInvitationNotFound ErrorCode = "invitation_not_found"
+	CodeInvitationExpired  ErrorCode = "invitation_expired"
+	CodeInvitationRevoked  ErrorCode = "invitation_revoked"
+	CodeInvitationAccepted ErrorCode = "invitation_already_accepted"
+)
+
+var (
+	ErrInvitationNotFound = NewNotFoundError(CodeInvitationNotFound, "invitation not found")
+	ErrInvitationExpired  = NewConflictError(CodeInvitationExpired, "", "the invitation has expired")
+	ErrInvitationRevoked  = NewConflictError(CodeInvitationRevoked, "", "the invitation has been revoked")
+	ErrInvitationAccepted = NewConflictError(CodeInvitationAccepted, "", "the invitation has already been accepted")
+)
+
+type InvitationID string
+
+func NewInvitationID() InvitationID {
+	return InvitationID(uuid.New().String())
+}
+
+func (id InvitationID) String() string {
+	return string(id)
+}
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationExpired  InvitationStatus = "expired"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationRevoked  InvitationStatus = "revoked"
+)
+
+// Invitation asks someone to join the tenant by email. Accepting it creates
+// the account, or attaches it to the account that already uses the address,
+// and applies the role and organization membership it carries. Only a hash
+// of the token is stored, as with password reset tokens.
+type Invitation struct {
+	ID       InvitationID
+	TenantID TenantID
+	Email    Email
+	// Role is given to the account on acceptance; empty leaves new accounts
+	// as RoleUser and existing ones unchanged.
+	Role             Role
+	OrganizationID   OrganizationID
+	OrganizationRole MemberRole
+	InvitedBy        UserID
+	TokenHash        string
+	// UserID is the account the invitation is attached to: the existing
+	// account for the email, or the one created on acceptance.
+	UserID     UserID
+	SendCount  int
+	CreatedAt  time.Time
+	SentAt     time.Time
+	ExpiresAt  time.Time
+	AcceptedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// NewInvitation validates the email. An organization without a role makes
+// the invitee a plain member.
+func NewInvitation(tenant TenantID, email string, role Role, orgID OrganizationID, orgRole MemberRole, invitedBy UserID, tokenHash string, now time.Time, ttl time.Duration) (*Invitation, error) {
+	parsed, err := ParseEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	now = now.UTC()
+	inv := &Invitation{
+		ID:        NewInvitationID(),
+		TenantID:  tenant,
+		Email:     parsed,
+		InvitedBy: invitedBy,
+		TokenHash: tokenHash,
+		SendCount: 1,
+		CreatedAt: now,
+		SentAt:    now,
+		ExpiresAt: now.Add(ttl),
+	}
+	inv.Assign(role, orgID, orgRole)
+	return inv, nil
+}
+
+func (i *Invitation) Status(now time.Time) InvitationStatus {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationAccepted
+	case i.RevokedAt != nil:
+		return InvitationRevoked
+	case !now.Before(i.ExpiresAt):
+		return InvitationExpired
+	}
+	return InvitationPending
+}
+
+// Open reports whether the invitation can still be resent or accepted,
+// possibly after a resend if it has expired.
+func (i *Invitation) Open() bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil
+}
+
+// Assign replaces what the invitation grants.
+func (i *Invitation) Assign(role Role, orgID OrganizationID, orgRole MemberRole) {
+	if orgID != "" && orgRole == "" {
+		orgRole = MemberRoleMember
+	}
+	if orgID == "" {
+		orgRole = ""
+	}
+	i.Role = role
+	i.OrganizationID = orgID
+	i.OrganizationRole = orgRole
+}
+
+// Resend replaces the token and restarts the expiry, so earlier links stop
+// working.
+func (i *Invitation) Resend(tokenHash string, now time.Time, ttl time.Duration) error {
+	if err := i.checkOpen(); err != nil {
+		return err
+	}
+	now = now.UTC()
+	i.TokenHash = tokenHash
+	i.SentAt = now
+	i.ExpiresAt = now.Add(ttl)
+	i.SendCount++
+	return nil
+}
+
+// Revoke withdraws the invitation. Revoking it again changes nothing.
+func (i *Invitation) Revoke(now time.Time) error {
+	if i.AcceptedAt != nil {
+		return ErrInvitationAccepted
+	}
+	if i.RevokedAt == nil {
+		revokedAt := now.UTC()
+		i.RevokedAt = &revokedAt
+	}
+	return nil
+}
+
+// CanAccept reports why the invitation cannot be accepted, if it cannot.
+func (i *Invitation) CanAccept(now time.Time) error {
+	if err := i.checkOpen(); err != nil {
+		return err
+	}
+	if !now.Before(i.ExpiresAt) {
+		return ErrInvitationExpired
+	}
+	return nil
+}
+
+func (i *Invitation) Accept(userID UserID, now time.Time) error {
+	if err := i.CanAccept(now); err != nil {
+		return err
+	}
+	acceptedAt := now.UTC()
+	i.UserID = userID
+	i.AcceptedAt = &acceptedAt
+	return nil
+}
+
+func (i *Invitation) checkOpen() error {
+	if i.AcceptedAt != nil {
+		return ErrInvitationAccepted
+	}
+	if i.RevokedAt != nil {
+		return ErrInvitationRevoked
+	}
+	return nil
+}
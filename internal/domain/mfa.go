@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"crypto/subtle"
+	"time"
+)
+
+const (
+	CodeMFAAlreadyEnabled ErrorCode = "mfa_already_enabled"
+	CodeMFANotPending     ErrorCode = "mfa_enrollment_not_pending"
+	CodeMFANotEnabled     ErrorCode = "mfa_not_enabled"
+	CodeMFACodeReused     ErrorCode = "mfa_code_reused"
+)
+
+var (
+	ErrMFAAlreadyEnabled = NewConflictError(CodeMFAAlreadyEnabled, "", "multi-factor authentication is already enabled")
+	ErrMFANotPending     = NewConflictError(CodeMFANotPending, "", "there is no authenticator enrollment to confirm")
+	ErrMFANotEnabled     = NewConflictError(CodeMFANotEnabled, "", "multi-factor authentication is not enabled")
+	ErrMFACodeReused     = NewValidationError(CodeMFACodeReused, "code", "this code has already been used")
+)
+
+// TOTPFactor is a time-based one-time password authenticator (RFC 6238). It
+// only counts as a second factor once Confirmed.
+type TOTPFactor struct {
+	Secret      string
+	Confirmed   bool
+	ConfirmedAt *time.Time
+	// LastUsedStep is the time step of the last accepted code; codes from
+	// that step or earlier are rejected so each code works once.
+	LastUsedStep int64
+}
+
+// StartTOTPEnrollment attaches a new, unconfirmed authenticator, replacing
+// any earlier unconfirmed one.
+func (u *User) StartTOTPEnrollment(secret string) error {
+	if u.MFAEnabled() {
+		return ErrMFAAlreadyEnabled
+	}
+	u.TOTP = &TOTPFactor{Secret: secret}
+	return nil
+}
+
+// ConfirmTOTP enables the pending authenticator after the user proved they
+// can generate codes for it, and stores the hashes of fresh recovery codes.
+func (u *User) ConfirmTOTP(step int64, at time.Time, recoveryCodeHashes []string) error {
+	if u.TOTP == nil || u.TOTP.Confirmed {
+		return ErrMFANotPending
+	}
+	confirmedAt := at.UTC()
+	u.TOTP.Confirmed = true
+	u.TOTP.ConfirmedAt = &confirmedAt
+	u.TOTP.LastUsedStep = step
+	u.RecoveryCodes = recoveryCodeHashes
+	return nil
+}
+
+func (u *User) MFAEnabled() bool {
+	return u.TOTP != nil && u.TOTP.Confirmed
+}
+
+// UseTOTPStep records that the code for step was accepted.
+func (u *User) UseTOTPStep(step int64) error {
+	if !u.MFAEnabled() {
+		return ErrMFANotEnabled
+	}
+	if step <= u.TOTP.LastUsedStep {
+		return ErrMFACodeReused
+	}
+	u.TOTP.LastUsedStep = step
+	return nil
+}
+
+// UseRecoveryCode removes the recovery code with the given hash and reports
+// whether it was present.
+func (u *User) UseRecoveryCode(hash string) bool {
+	for i, stored := range u.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			u.RecoveryCodes = append(u.RecoveryCodes[:i:i], u.RecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (u *User) ReplaceRecoveryCodes(hashes []string) error {
+	if !u.MFAEnabled() {
+		return ErrMFANotEnabled
+	}
+	u.RecoveryCodes = hashes
+	return nil
+}
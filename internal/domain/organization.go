@@ -0,0 +1,331 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CodeOrganizationNotFound    ErrorCode = "organization_not_found"
+	CodeOrganizationConflict    ErrorCode = "organization_conflict"
+	CodeOrganizationNameInvalid ErrorCode = "organization_name_invalid"
+	CodeOrganizationForbidden   ErrorCode = "organization_forbidden"
+	CodeMemberNotFound          ErrorCode = "member_not_found"
+	CodeMemberRoleInvalid       ErrorCode = "member_role_invalid"
+	CodeMemberExists            ErrorCode = "member_exists"
+	CodeMembershipNotPending    ErrorCode = "membership_not_pending"
+	CodeLastOwner               ErrorCode = "last_owner"
+	CodeTeamNotFound            ErrorCode = "team_not_found"
+	CodeTeamNameInvalid         ErrorCode = "team_name_invalid"
+	CodeTeamExists              ErrorCode = "team_exists"
+	CodeTeamMemberExists        ErrorCode = "team_member_exists"
+)
+
+const maxOrganizationNameLength = 100
+
+var (
+	ErrOrganizationNotFound    = NewNotFoundError(CodeOrganizationNotFound, "organization not found")
+	ErrOrganizationConflict    = NewConflictError(CodeOrganizationConflict, "", "the organization was changed by another request; retry")
+	ErrOrganizationNameInvalid = NewValidationError(CodeOrganizationNameInvalid, "name", "name must be 1 to 100 characters").
+					WithParams(map[string]any{"max": maxOrganizationNameLength})
+	ErrOrganizationForbidden = NewForbiddenError(CodeOrganizationForbidden, "your role in the organization does not allow this")
+	ErrMemberNotFound        = NewNotFoundError(CodeMemberNotFound, "the user is not a member of the organization")
+	ErrMemberRoleInvalid     = NewValidationError(CodeMemberRoleInvalid, "role", "role must be one of: owner, admin, member").
+					WithParams(map[string]any{"allowed": []MemberRole{MemberRoleOwner, MemberRoleAdmin, MemberRoleMember}})
+	ErrMemberExists         = NewConflictError(CodeMemberExists, "user_id", "the user is already a member or has been invited")
+	ErrMembershipNotPending = NewConflictError(CodeMembershipNotPending, "", "there is no invitation to accept")
+	ErrLastOwner            = NewConflictError(CodeLastOwner, "", "an organization must keep at least one owner")
+	ErrTeamNotFound         = NewNotFoundError(CodeTeamNotFound, "team not found")
+	ErrTeamNameInvalid      = NewValidationError(CodeTeamNameInvalid, "name", "name must be 1 to 100 characters").
+				WithParams(map[string]any{"max": maxOrganizationNameLength})
+	ErrTeamExists       = NewConflictError(CodeTeamExists, "name", "the organization already has a team with this name")
+	ErrTeamMemberExists = NewConflictError(CodeTeamMemberExists, "user_id", "the user is already on the team")
+)
+
+type OrganizationID string
+
+func NewOrganizationID() OrganizationID {
+	return OrganizationID(uuid.New().String())
+}
+
+func (id OrganizationID) String() string {
+	return string(id)
+}
+
+type TeamID string
+
+func NewTeamID() TeamID {
+	return TeamID(uuid.New().String())
+}
+
+func (id TeamID) String() string {
+	return string(id)
+}
+
+// MemberRole is a user's role within one organization, unrelated to the
+// user's Role in the service. Each role can do everything the ones below it
+// can.
+type MemberRole string
+
+const (
+	MemberRoleOwner  MemberRole = "owner"
+	MemberRoleAdmin  MemberRole = "admin"
+	MemberRoleMember MemberRole = "member"
+)
+
+func ParseMemberRole(role string) (MemberRole, error) {
+	switch r := MemberRole(strings.ToLower(strings.TrimSpace(role))); r {
+	case MemberRoleOwner, MemberRoleAdmin, MemberRoleMember:
+		return r, nil
+	}
+	return "", ErrMemberRoleInvalid
+}
+
+func (r MemberRole) String() string {
+	return string(r)
+}
+
+// AtLeast reports whether r grants everything other does.
+func (r MemberRole) AtLeast(other MemberRole) bool {
+	return r.rank() >= other.rank()
+}
+
+func (r MemberRole) rank() int {
+	switch r {
+	case MemberRoleOwner:
+		return 3
+	case MemberRoleAdmin:
+		return 2
+	case MemberRoleMember:
+		return 1
+	}
+	return 0
+}
+
+// Membership links a user to an organization. Invited memberships grant
+// nothing until the user accepts them.
+type Membership struct {
+	UserID    UserID
+	Role      MemberRole
+	Invited   bool
+	InvitedBy UserID
+	CreatedAt time.Time
+	JoinedAt  *time.Time
+}
+
+// Team is a named group of an organization's members.
+type Team struct {
+	ID        TeamID
+	Name      string
+	Members   []UserID
+	CreatedAt time.Time
+}
+
+// Organization groups users of one tenant. It always has at least one
+// owner.
+type Organization struct {
+	ID        OrganizationID
+	TenantID  TenantID
+	Name      string
+	Members   []Membership
+	Teams     []Team
+	CreatedAt time.Time
+	// Version counts the stored changes. Repositories only update an
+	// organization still at the version it was read at, so concurrent
+	// changes cannot overwrite each other.
+	Version int64
+}
+
+// NewOrganization creates an organization owned by its creator.
+func NewOrganization(tenant TenantID, name string, owner UserID, now time.Time) (*Organization, error) {
+	name, err := validateGroupName(name, ErrOrganizationNameInvalid)
+	if err != nil {
+		return nil, err
+	}
+
+	now = now.UTC()
+	return &Organization{
+		ID:       NewOrganizationID(),
+		TenantID: tenant,
+		Name:     name,
+		Members: []Membership{{
+			UserID:    owner,
+			Role:      MemberRoleOwner,
+			CreatedAt: now,
+			JoinedAt:  &now,
+		}},
+		CreatedAt: now,
+	}, nil
+}
+
+// Membership returns the user's membership, invited or not.
+func (o *Organization) Membership(userID UserID) (*Membership, bool) {
+	i := o.memberIndex(userID)
+	if i < 0 {
+		return nil, false
+	}
+	return &o.Members[i], true
+}
+
+// RoleOf returns the role of an accepted member.
+func (o *Organization) RoleOf(userID UserID) (MemberRole, bool) {
+	m, ok := o.Membership(userID)
+	if !ok || m.Invited {
+		return "", false
+	}
+	return m.Role, true
+}
+
+// AddMember makes the user a member straight away.
+func (o *Organization) AddMember(userID UserID, role MemberRole, now time.Time) error {
+	if _, exists := o.Membership(userID); exists {
+		return ErrMemberExists
+	}
+	now = now.UTC()
+	o.Members = append(o.Members, Membership{UserID: userID, Role: role, CreatedAt: now, JoinedAt: &now})
+	return nil
+}
+
+// Invite offers the user a membership that they must accept.
+func (o *Organization) Invite(userID UserID, role MemberRole, invitedBy UserID, now time.Time) error {
+	if _, exists := o.Membership(userID); exists {
+		return ErrMemberExists
+	}
+	o.Members = append(o.Members, Membership{
+		UserID:    userID,
+		Role:      role,
+		Invited:   true,
+		InvitedBy: invitedBy,
+		CreatedAt: now.UTC(),
+	})
+	return nil
+}
+
+func (o *Organization) AcceptInvitation(userID UserID, now time.Time) error {
+	m, ok := o.Membership(userID)
+	if !ok {
+		return ErrMemberNotFound
+	}
+	if !m.Invited {
+		return ErrMembershipNotPending
+	}
+	now = now.UTC()
+	m.Invited = false
+	m.JoinedAt = &now
+	return nil
+}
+
+// ChangeRole gives a member another role. The last owner cannot step down.
+func (o *Organization) ChangeRole(userID UserID, role MemberRole) error {
+	m, ok := o.Membership(userID)
+	if !ok {
+		return ErrMemberNotFound
+	}
+	if m.Role == MemberRoleOwner && role != MemberRoleOwner && o.IsLastOwner(userID) {
+		return ErrLastOwner
+	}
+	m.Role = role
+	return nil
+}
+
+// RemoveMember removes a member, or withdraws an invitation, and takes the
+// user off every team. The last owner cannot be removed.
+func (o *Organization) RemoveMember(userID UserID) error {
+	i := o.memberIndex(userID)
+	if i < 0 {
+		return ErrMemberNotFound
+	}
+	if o.IsLastOwner(userID) {
+		return ErrLastOwner
+	}
+	o.Members = slices.Delete(o.Members, i, i+1)
+	for t := range o.Teams {
+		o.Teams[t].Members = slices.DeleteFunc(o.Teams[t].Members, func(id UserID) bool { return id == userID })
+	}
+	return nil
+}
+
+// IsLastOwner reports whether the user is the organization's only accepted
+// owner.
+func (o *Organization) IsLastOwner(userID UserID) bool {
+	if role, ok := o.RoleOf(userID); !ok || role != MemberRoleOwner {
+		return false
+	}
+	for _, m := range o.Members {
+		if m.UserID != userID && !m.Invited && m.Role == MemberRoleOwner {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *Organization) AddTeam(name string, now time.Time) (*Team, error) {
+	name, err := validateGroupName(name, ErrTeamNameInvalid)
+	if err != nil {
+		return nil, err
+	}
+	for _, team := range o.Teams {
+		if strings.EqualFold(team.Name, name) {
+			return nil, ErrTeamExists
+		}
+	}
+	o.Teams = append(o.Teams, Team{ID: NewTeamID(), Name: name, CreatedAt: now.UTC()})
+	return &o.Teams[len(o.Teams)-1], nil
+}
+
+func (o *Organization) RemoveTeam(id TeamID) error {
+	i := o.teamIndex(id)
+	if i < 0 {
+		return ErrTeamNotFound
+	}
+	o.Teams = slices.Delete(o.Teams, i, i+1)
+	return nil
+}
+
+// AddTeamMember puts an accepted member on a team.
+func (o *Organization) AddTeamMember(id TeamID, userID UserID) error {
+	i := o.teamIndex(id)
+	if i < 0 {
+		return ErrTeamNotFound
+	}
+	if _, ok := o.RoleOf(userID); !ok {
+		return ErrMemberNotFound
+	}
+	if slices.Contains(o.Teams[i].Members, userID) {
+		return ErrTeamMemberExists
+	}
+	o.Teams[i].Members = append(o.Teams[i].Members, userID)
+	return nil
+}
+
+func (o *Organization) RemoveTeamMember(id TeamID, userID UserID) error {
+	i := o.teamIndex(id)
+	if i < 0 {
+		return ErrTeamNotFound
+	}
+	j := slices.Index(o.Teams[i].Members, userID)
+	if j < 0 {
+		return ErrMemberNotFound
+	}
+	o.Teams[i].Members = slices.Delete(o.Teams[i].Members, j, j+1)
+	return nil
+}
+
+func (o *Organization) memberIndex(userID UserID) int {
+	return slices.IndexFunc(o.Members, func(m Membership) bool { return m.UserID == userID })
+}
+
+func (o *Organization) teamIndex(id TeamID) int {
+	return slices.IndexFunc(o.Teams, func(t Team) bool { return t.ID == id })
+}
+
+func validateGroupName(name string, invalid *Error) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxOrganizationNameLength {
+		return "", invalid
+	}
+	return name, nil
+}
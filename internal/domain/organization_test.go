@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOrganizationMembershipRules(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	newOrg := func(t *testing.T) *Organization {
+		t.Helper()
+		org, err := NewOrganization(DefaultTenantID, " Acme ", "owner", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := org.AddMember("admin", MemberRoleAdmin, now); err != nil {
+			t.Fatal(err)
+		}
+		if err := org.Invite("invited", MemberRoleOwner, "owner", now); err != nil {
+			t.Fatal(err)
+		}
+		return org
+	}
+
+	tests := []struct {
+		name   string
+		change func(*Organization) error
+		want   error
+	}{
+		{"add existing member", func(o *Organization) error { return o.AddMember("admin", MemberRoleMember, now) }, ErrMemberExists},
+		{"add invited user", func(o *Organization) error { return o.AddMember("invited", MemberRoleMember, now) }, ErrMemberExists},
+		{"invite existing member", func(o *Organization) error { return o.Invite("admin", MemberRoleMember, "owner", now) }, ErrMemberExists},
+		{"accept without invitation", func(o *Organization) error { return o.AcceptInvitation("admin", now) }, ErrMembershipNotPending},
+		{"accept as stranger", func(o *Organization) error { return o.AcceptInvitation("stranger", now) }, ErrMemberNotFound},
+		{"last owner steps down", func(o *Organization) error { return o.ChangeRole("owner", MemberRoleAdmin) }, ErrLastOwner},
+		{"last owner leaves while an owner is invited", func(o *Organization) error { return o.RemoveMember("owner") }, ErrLastOwner},
+		{"change stranger's role", func(o *Organization) error { return o.ChangeRole("stranger", MemberRoleAdmin) }, ErrMemberNotFound},
+		{"remove stranger", func(o *Organization) error { return o.RemoveMember("stranger") }, ErrMemberNotFound},
+		{"owner leaves after promoting", func(o *Organization) error {
+			if err := o.ChangeRole("admin", MemberRoleOwner); err != nil {
+				return err
+			}
+			return o.RemoveMember("owner")
+		}, nil},
+		{"owner leaves after invitee accepts", func(o *Organization) error {
+			if err := o.AcceptInvitation("invited", now); err != nil {
+				return err
+			}
+			return o.RemoveMember("owner")
+		}, nil},
+		{"withdraw invitation", func(o *Organization) error { return o.RemoveMember("invited") }, nil},
+		{"invitee joins a team", func(o *Organization) error {
+			team, err := o.AddTeam("Core", now)
+			if err != nil {
+				return err
+			}
+			return o.AddTeamMember(team.ID, "invited")
+		}, ErrMemberNotFound},
+		{"duplicate team name", func(o *Organization) error {
+			if _, err := o.AddTeam("Core", now); err != nil {
+				return err
+			}
+			_, err := o.AddTeam(" core ", now)
+			return err
+		}, ErrTeamExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.change(newOrg(t)); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrganizationRemoveMemberLeavesTeams(t *testing.T) {
+	now := time.Now()
+	org, err := NewOrganization(DefaultTenantID, "Acme", "owner", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if org.Name != "Acme" || !org.IsLastOwner("owner") {
+		t.Fatalf("new organization = %+v, want the creator as only owner", org)
+	}
+	if err := org.AddMember("dev", MemberRoleMember, now); err != nil {
+		t.Fatal(err)
+	}
+	team, err := org.AddTeam("Core", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := org.AddTeamMember(team.ID, "dev"); err != nil {
+		t.Fatal(err)
+	}
+	if err := org.RemoveMember("dev"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := org.Membership("dev"); ok {
+		t.Fatal("removed member is still a member")
+	}
+	if len(org.Teams[0].Members) != 0 {
+		t.Fatalf("team members = %v, want none", org.Teams[0].Members)
+	}
+}
+
+func TestParseMemberRole(t *testing.T) {
+	for in, want := range map[string]MemberRole{" Owner ": MemberRoleOwner, "admin": MemberRoleAdmin, "MEMBER": MemberRoleMember} {
+		if got, err := ParseMemberRole(in); err != nil || got != want {
+			t.Errorf("ParseMemberRole(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseMemberRole("superuser"); !errors.Is(err, ErrMemberRoleInvalid) {
+		t.Errorf("ParseMemberRole(superuser) err = %v, want %v", err, ErrMemberRoleInvalid)
+	}
+	if !MemberRoleOwner.AtLeast(MemberRoleAdmin) || MemberRoleMember.AtLeast(MemberRoleAdmin) {
+		t.Error("AtLeast does not order owner > admin > member")
+	}
+}
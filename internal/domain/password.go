@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+const (
+	CodePasswordTooShort   ErrorCode = "password_too_short"
+	CodePasswordTooLong    ErrorCode = "password_too_long"
+	CodeResetTokenNotFound ErrorCode = "password_reset_token_not_found"
+)
+
+const (
+	minPasswordLength = 8
+	// maxPasswordLength is in bytes; bcrypt ignores anything past 72.
+	maxPasswordLength = 72
+)
+
+var (
+	ErrPasswordTooShort = NewValidationError(CodePasswordTooShort, "password", "password must be at least 8 characters").
+				WithParams(map[string]any{"min": minPasswordLength})
+	ErrPasswordTooLong = NewValidationError(CodePasswordTooLong, "password", "password must be at most 72 bytes").
+				WithParams(map[string]any{"max": maxPasswordLength})
+	ErrResetTokenNotFound = NewNotFoundError(CodeResetTokenNotFound, "password reset token not found")
+)
+
+func ValidatePassword(password string) error {
+	switch {
+	case len([]rune(password)) < minPasswordLength:
+		return ErrPasswordTooShort
+	case len(password) > maxPasswordLength:
+		return ErrPasswordTooLong
+	}
+	return nil
+}
+
+// PasswordResetToken is a one-time reset credential. Only a hash of the
+// token is stored, so a leaked store cannot be used to reset passwords.
+type PasswordResetToken struct {
+	TokenHash string
+	UserID    UserID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+func (t *PasswordResetToken) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// IssuedBefore reports whether the token predates the user's last password
+// change, which invalidates it.
+func (t *PasswordResetToken) IssuedBefore(changedAt *time.Time) bool {
+	return changedAt != nil && t.CreatedAt.Before(*changedAt)
+}
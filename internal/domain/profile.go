@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	// Time zones are validated against the embedded database so the result
+	// does not depend on the host.
+	_ "time/tzdata"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	CodeTooLong           ErrorCode = "too_long"
+	CodeInvalidCharacters ErrorCode = "invalid_characters"
+	CodePhoneInvalid      ErrorCode = "phone_invalid"
+	CodeLocaleInvalid     ErrorCode = "locale_invalid"
+	CodeTimezoneInvalid   ErrorCode = "timezone_invalid"
+	CodeAvatarURLInvalid  ErrorCode = "avatar_url_invalid"
+)
+
+var (
+	ErrPhoneInvalid     = NewValidationError(CodePhoneInvalid, "phone", "phone must be an E.164 number such as +14155552671")
+	ErrLocaleInvalid    = NewValidationError(CodeLocaleInvalid, "locale", "locale must be a BCP 47 language tag such as en-US")
+	ErrTimezoneInvalid  = NewValidationError(CodeTimezoneInvalid, "timezone", "timezone must be an IANA time zone such as Europe/Madrid")
+	ErrAvatarURLInvalid = NewValidationError(CodeAvatarURLInvalid, "avatar_url", "avatar_url must be an absolute http or https URL")
+)
+
+const (
+	maxProfileNameLength = 64
+	maxBioLength         = 500
+	maxAvatarURLLength   = 2048
+	// maxPhoneDigits is the longest number E.164 allows, country code
+	// included.
+	maxPhoneDigits = 15
+)
+
+// Profile is optional information users share about themselves. Every field
+// may be empty.
+type Profile struct {
+	DisplayName string
+	GivenName   string
+	FamilyName  string
+	// Phone is in E.164 form, such as "+14155552671".
+	Phone string
+	// Locale is a canonical BCP 47 tag, such as "en-US".
+	Locale string
+	// Timezone is an IANA time zone name, such as "Europe/Madrid".
+	Timezone  string
+	Bio       string
+	AvatarURL string
+}
+
+// ParseProfile validates every field, reporting all failures together, and
+// returns the profile in canonical form. Phone numbers may be written with
+// spaces, dashes, dots and parentheses.
+func ParseProfile(raw Profile) (Profile, error) {
+	var errs ValidationErrors
+	var p Profile
+	var err error
+
+	p.DisplayName, err = parseProfileText("display_name", raw.DisplayName, maxProfileNameLength, false)
+	errs.Add(err)
+	p.GivenName, err = parseProfileText("given_name", raw.GivenName, maxProfileNameLength, false)
+	errs.Add(err)
+	p.FamilyName, err = parseProfileText("family_name", raw.FamilyName, maxProfileNameLength, false)
+	errs.Add(err)
+	p.Bio, err = parseProfileText("bio", raw.Bio, maxBioLength, true)
+	errs.Add(err)
+	p.Phone, err = parsePhone(raw.Phone)
+	errs.Add(err)
+	p.Locale, err = parseLocale(raw.Locale)
+	errs.Add(err)
+	p.Timezone, err = parseTimezone(raw.Timezone)
+	errs.Add(err)
+	p.AvatarURL, err = parseAvatarURL(raw.AvatarURL)
+	errs.Add(err)
+
+	if err := errs.Err(); err != nil {
+		return Profile{}, err
+	}
+	return p, nil
+}
+
+// UpdateProfile replaces the whole profile.
+func (u *User) UpdateProfile(raw Profile) error {
+	profile, err := ParseProfile(raw)
+	if err != nil {
+		return err
+	}
+	u.Profile = profile
+	return nil
+}
+
+// parseProfileText trims the text and rejects control characters, other
+// than line breaks where multiline is set.
+func parseProfileText(field, raw string, maxLength int, multiline bool) (string, error) {
+	text := strings.TrimSpace(raw)
+	if utf8.RuneCountInString(text) > maxLength {
+		return "", NewValidationError(CodeTooLong, field, field+" must be at most "+strconv.Itoa(maxLength)+" characters").
+			WithParams(map[string]any{"max": maxLength})
+	}
+	for _, r := range text {
+		if unicode.IsControl(r) && !(multiline && (r == '\n' || r == '\r')) {
+			return "", NewValidationError(CodeInvalidCharacters, field, field+" contains characters that are not allowed")
+		}
+	}
+	return text, nil
+}
+
+func parsePhone(raw string) (string, error) {
+	phone := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" -.()", r) {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(raw))
+	if phone == "" {
+		return "", nil
+	}
+
+	digits, ok := strings.CutPrefix(phone, "+")
+	if !ok || digits == "" || len(digits) > maxPhoneDigits || digits[0] == '0' {
+		return "", ErrPhoneInvalid
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", ErrPhoneInvalid
+		}
+	}
+	return phone, nil
+}
+
+func parseLocale(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	tag, err := language.Parse(raw)
+	if err != nil || tag == language.Und {
+		return "", ErrLocaleInvalid
+	}
+	return tag.String(), nil
+}
+
+func parseTimezone(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	// "Local" names whatever zone the server runs in.
+	if raw == "Local" {
+		return "", ErrTimezoneInvalid
+	}
+	loc, err := time.LoadLocation(raw)
+	if err != nil {
+		return "", ErrTimezoneInvalid
+	}
+	return loc.String(), nil
+}
+
+func parseAvatarURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	if len(raw) > maxAvatarURLLength {
+		return "", ErrAvatarURLInvalid
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", ErrAvatarURLInvalid
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseProfileFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) (string, error)
+		in    string
+		want  string
+		err   error
+	}{
+		{"phone", parsePhone, "+14155552671", "+14155552671", nil},
+		{"phone with separators", parsePhone, " +1 (415) 555-2671 ", "+14155552671", nil},
+		{"phone with dots", parsePhone, "+34.912.345.678", "+34912345678", nil},
+		{"longest phone", parsePhone, "+123456789012345", "+123456789012345", nil},
+		{"empty phone", parsePhone, "  ", "", nil},
+		{"phone without plus", parsePhone, "14155552671", "", ErrPhoneInvalid},
+		{"phone too long", parsePhone, "+1234567890123456", "", ErrPhoneInvalid},
+		{"phone with leading zero", parsePhone, "+0441234567", "", ErrPhoneInvalid},
+		{"phone with letters", parsePhone, "+1415CALLNOW", "", ErrPhoneInvalid},
+		{"phone only plus", parsePhone, "+", "", ErrPhoneInvalid},
+		{"phone with extension", parsePhone, "+14155552671x12", "", ErrPhoneInvalid},
+
+		{"locale", parseLocale, "en-US", "en-US", nil},
+		{"locale case", parseLocale, "EN-us", "en-US", nil},
+		{"locale with script", parseLocale, "zh-hant-tw", "zh-Hant-TW", nil},
+		{"language only", parseLocale, "pt", "pt", nil},
+		{"empty locale", parseLocale, "", "", nil},
+		{"undetermined locale", parseLocale, "und", "", ErrLocaleInvalid},
+		{"locale garbage", parseLocale, "english please", "", ErrLocaleInvalid},
+
+		{"timezone", parseTimezone, "Europe/Madrid", "Europe/Madrid", nil},
+		{"utc", parseTimezone, " UTC ", "UTC", nil},
+		{"timezone with three parts", parseTimezone, "America/Argentina/Buenos_Aires", "America/Argentina/Buenos_Aires", nil},
+		{"empty timezone", parseTimezone, "", "", nil},
+		{"server zone", parseTimezone, "Local", "", ErrTimezoneInvalid},
+		{"unknown timezone", parseTimezone, "Mars/Olympus_Mons", "", ErrTimezoneInvalid},
+		{"utc offset", parseTimezone, "+02:00", "", ErrTimezoneInvalid},
+		{"path traversal", parseTimezone, "../../etc/passwd", "", ErrTimezoneInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse(tt.in)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("parse(%q) err = %v, want %v", tt.in, err, tt.err)
+			}
+			if got != tt.want {
+				t.Fatalf("parse(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProfileReportsEveryField(t *testing.T) {
+	_, err := ParseProfile(Profile{
+		DisplayName: strings.Repeat("a", maxProfileNameLength+1),
+		GivenName:   "Ja\x00ne",
+		Bio:         "line one\nline two",
+		Phone:       "555-2671",
+		Locale:      "??",
+		Timezone:    "Nowhere",
+		AvatarURL:   "ftp://example.com/a.png",
+	})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("err = %v, want ValidationErrors", err)
+	}
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	want := "display_name,given_name,phone,locale,timezone,avatar_url"
+	if strings.Join(fields, ",") != want {
+		t.Fatalf("fields = %v, want %s", fields, want)
+	}
+
+	profile, err := ParseProfile(Profile{Bio: " line one\nline two ", Phone: "+44 20 7946 0958", Locale: "en-gb", Timezone: "Europe/London"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Bio != "line one\nline two" || profile.Phone != "+442079460958" || profile.Locale != "en-GB" {
+		t.Fatalf("profile = %+v", profile)
+	}
+}
@@ -1,15 +1,170 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"slices"
+	"time"
+)
 
+// UserRepository stores users per tenant. Every lookup takes the tenant and
+// never matches users of another one; Save and Update use the user's own
+// TenantID. An empty tenant is rejected with ErrTenantRequired. Emails match
+// by Email.Key.
 type UserRepository interface {
 	Save(ctx context.Context, user *User) error
-	GetByID(ctx context.Context, id UserID) (*User, error)
-	GetByEmail(ctx context.Context, email string) (*User, error)
-	GetByUsername(ctx context.Context, username string) (*User, error)
-	GetAll(ctx context.Context) ([]*User, error)
+	GetByID(ctx context.Context, tenant TenantID, id UserID) (*User, error)
+	GetByEmail(ctx context.Context, tenant TenantID, email Email) (*User, error)
+	GetByUsername(ctx context.Context, tenant TenantID, username Username) (*User, error)
+	// GetAll returns the tenant's users that match the filter.
+	GetAll(ctx context.Context, tenant TenantID, filter UserFilter) ([]*User, error)
 	Update(ctx context.Context, user *User) error
-	Delete(ctx context.Context, id UserID) error
-	ExistsByEmail(ctx context.Context, email string) (bool, error)
-	ExistsByUsername(ctx context.Context, username string) (bool, error)
+	Delete(ctx context.Context, tenant TenantID, id UserID) error
+	ExistsByEmail(ctx context.Context, tenant TenantID, email Email) (bool, error)
+	ExistsByUsername(ctx context.Context, tenant TenantID, username Username) (bool, error)
+	// FindByUsernameSkeleton returns the users whose username has the given
+	// Username.Skeleton.
+	FindByUsernameSkeleton(ctx context.Context, tenant TenantID, skeleton string) ([]*User, error)
+	// UnsetAttribute removes the custom attribute from every user of the
+	// tenant.
+	UnsetAttribute(ctx context.Context, tenant TenantID, name string) error
+}
+
+// UserFilter narrows GetAll. The zero value matches every user.
+type UserFilter struct {
+	// Attributes matches users whose custom attributes hold all of the
+	// given canonical values.
+	Attributes map[string]any
+	// Tags matches users carrying any or, with TagMatchAll, all of the
+	// given canonical tags.
+	Tags     []string
+	TagMatch TagMatch
+}
+
+// Empty reports whether the filter matches every user.
+func (f UserFilter) Empty() bool {
+	return len(f.Attributes) == 0 && len(f.Tags) == 0
+}
+
+// Matches reports whether the user passes the filter.
+func (f UserFilter) Matches(user *User) bool {
+	for name, value := range f.Attributes {
+		if stored, ok := user.Attributes[name]; !ok || stored != value {
+			return false
+		}
+	}
+	if len(f.Tags) == 0 {
+		return true
+	}
+	if f.TagMatch == TagMatchAll {
+		return !slices.ContainsFunc(f.Tags, func(tag string) bool { return !user.HasTag(tag) })
+	}
+	return slices.ContainsFunc(f.Tags, user.HasTag)
+}
+
+// AttributeSchemaRepository stores custom attribute definitions per tenant,
+// with the same tenant rules as UserRepository.
+type AttributeSchemaRepository interface {
+	// Save returns ErrAttributeExists when the name is taken.
+	Save(ctx context.Context, schema *AttributeSchema) error
+	// Get returns ErrAttributeNotFound for an unknown name.
+	Get(ctx context.Context, tenant TenantID, name string) (*AttributeSchema, error)
+	// List returns the tenant's schemas ordered by name.
+	List(ctx context.Context, tenant TenantID) ([]*AttributeSchema, error)
+	Delete(ctx context.Context, tenant TenantID, name string) error
+}
+
+// AttributeIndexer maintains the storage indexes that enforce unique custom
+// attributes. Stores that cannot index need none; uniqueness is then only
+// checked before writing.
+type AttributeIndexer interface {
+	EnsureUniqueAttribute(ctx context.Context, tenant TenantID, name string) error
+	DropUniqueAttribute(ctx context.Context, tenant TenantID, name string) error
+}
+
+// PasswordResetRepository stores reset tokens by hash.
+type PasswordResetRepository interface {
+	Save(ctx context.Context, token *PasswordResetToken) error
+	// Consume removes and returns the token with the given hash, so that it
+	// can be used at most once. It returns ErrResetTokenNotFound when there
+	// is none.
+	Consume(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+	DeleteByUser(ctx context.Context, userID UserID) error
+}
+
+type SessionRepository interface {
+	Save(ctx context.Context, session *Session) error
+	// GetByTokenHash returns ErrSessionNotFound when no session matches.
+	GetByTokenHash(ctx context.Context, tokenHash string) (*Session, error)
+	ListByUser(ctx context.Context, userID UserID) ([]*Session, error)
+	Touch(ctx context.Context, id SessionID, at time.Time) error
+	// Delete removes one of the user's sessions, returning
+	// ErrSessionNotFound if the user has no session with that ID.
+	Delete(ctx context.Context, userID UserID, id SessionID) error
+	DeleteByUser(ctx context.Context, userID UserID) (int, error)
+}
+
+type APIKeyRepository interface {
+	Save(ctx context.Context, key *APIKey) error
+	// GetBySecretHash returns ErrAPIKeyNotFound when no key matches.
+	GetBySecretHash(ctx context.Context, secretHash string) (*APIKey, error)
+	// Get returns one of the owner's keys, or ErrAPIKeyNotFound.
+	Get(ctx context.Context, ownerID UserID, id APIKeyID) (*APIKey, error)
+	ListByOwner(ctx context.Context, ownerID UserID) ([]*APIKey, error)
+	Update(ctx context.Context, key *APIKey) error
+	Touch(ctx context.Context, id APIKeyID, at time.Time) error
+	Delete(ctx context.Context, ownerID UserID, id APIKeyID) error
+}
+
+// OrganizationRepository stores organizations per tenant, with the same
+// tenant rules as UserRepository.
+type OrganizationRepository interface {
+	Save(ctx context.Context, org *Organization) error
+	// Get returns ErrOrganizationNotFound when the tenant has no such
+	// organization.
+	Get(ctx context.Context, tenant TenantID, id OrganizationID) (*Organization, error)
+	// ListByMember returns the organizations the user belongs to or is
+	// invited to, oldest first.
+	ListByMember(ctx context.Context, tenant TenantID, userID UserID) ([]*Organization, error)
+	// Update returns ErrOrganizationConflict when the stored organization
+	// is no longer at org.Version, and increments org.Version otherwise.
+	Update(ctx context.Context, org *Organization) error
+	Delete(ctx context.Context, tenant TenantID, id OrganizationID) error
+}
+
+// InvitationRepository stores invitations per tenant, with the same tenant
+// rules as UserRepository. Lookups return ErrInvitationNotFound when nothing
+// matches.
+type InvitationRepository interface {
+	Save(ctx context.Context, inv *Invitation) error
+	Get(ctx context.Context, tenant TenantID, id InvitationID) (*Invitation, error)
+	GetByTokenHash(ctx context.Context, tenant TenantID, tokenHash string) (*Invitation, error)
+	// FindOpen returns the invitation for the email and organization that
+	// has been neither accepted nor revoked, even if it has expired.
+	FindOpen(ctx context.Context, tenant TenantID, email Email, orgID OrganizationID) (*Invitation, error)
+	// List returns the tenant's invitations, newest first.
+	List(ctx context.Context, tenant TenantID) ([]*Invitation, error)
+	Update(ctx context.Context, inv *Invitation) error
+}
+
+// AuditRepository is an append-only store of audit entries, with the same
+// tenant rules as UserRepository.
+type AuditRepository interface {
+	// Append returns ErrAuditSequenceTaken when the tenant already has an
+	// entry with the same sequence.
+	Append(ctx context.Context, entry *AuditEntry) error
+	// Last returns nil when the tenant has no entries yet.
+	Last(ctx context.Context, tenant TenantID) (*AuditEntry, error)
+	// List returns entries newest first.
+	List(ctx context.Context, tenant TenantID, filter AuditFilter) ([]*AuditEntry, error)
+	// Chain returns every entry of the tenant oldest first, for
+	// VerifyAuditChain.
+	Chain(ctx context.Context, tenant TenantID) ([]*AuditEntry, error)
+}
+
+// AuditFilter narrows AuditRepository.List. Zero fields do not filter.
+type AuditFilter struct {
+	UserID UserID
+	// Before returns only entries with a lower sequence, to page back.
+	Before int64
+	Limit  int
 }
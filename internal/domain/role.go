@@ -0,0 +1,36 @@
+package domain
+
+import "strings"
+
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+	// RoleService marks a service account: a non-human owner of API keys
+	// that cannot sign in interactively.
+	RoleService Role = "service"
+)
+
+const CodeRoleInvalid ErrorCode = "role_invalid"
+
+var ErrInvalidRole = NewValidationError(CodeRoleInvalid, "role", "role must be one of: user, admin, service").
+	WithParams(map[string]any{"allowed": []Role{RoleUser, RoleAdmin, RoleService}})
+
+func ParseRole(role string) (Role, error) {
+	switch r := Role(strings.ToLower(strings.TrimSpace(role))); r {
+	case RoleUser, RoleAdmin, RoleService:
+		return r, nil
+	}
+	return "", ErrInvalidRole
+}
+
+func (r Role) String() string {
+	return string(r)
+}
+
+// Outranks reports whether r grants more than other. Administrators outrank
+// everyone else; users and service accounts are peers.
+func (r Role) Outranks(other Role) bool {
+	return r == RoleAdmin && other != RoleAdmin
+}
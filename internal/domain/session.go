@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const CodeSessionNotFound ErrorCode = "session_not_found"
+
+var ErrSessionNotFound = NewNotFoundError(CodeSessionNotFound, "session not found")
+
+type SessionID string
+
+func NewSessionID() SessionID {
+	return SessionID(uuid.New().String())
+}
+
+func (id SessionID) String() string {
+	return string(id)
+}
+
+// Session is a signed-in device. The bearer token that identifies it is only
+// stored as a hash.
+type Session struct {
+	ID     SessionID
+	UserID UserID
+	// TenantID is the user's tenant. A session token only works there.
+	TenantID  TenantID
+	TokenHash string
+	// Scope limits what the session may do; see dto.ScopeFull.
+	Scope      string
+	Device     string
+	IPAddress  string
+	UserAgent  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	// ExpiresAt is the absolute limit, however active the session is.
+	ExpiresAt time.Time
+}
+
+// Active reports whether the session is within both its absolute lifetime
+// and the idle timeout.
+func (s *Session) Active(now time.Time, idleTimeout time.Duration) bool {
+	return now.Before(s.ExpiresAt) && now.Sub(s.LastSeenAt) < idleTimeout
+}
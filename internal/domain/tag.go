@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	CodeTagInvalid      ErrorCode = "tag_invalid"
+	CodeTooManyTags     ErrorCode = "too_many_tags"
+	CodeTagMatchInvalid ErrorCode = "tag_match_invalid"
+	CodeFilterRequired  ErrorCode = "filter_required"
+)
+
+// MaxUserTags is the most tags a user can carry.
+const MaxUserTags = 50
+
+var (
+	ErrTooManyTags = NewValidationError(CodeTooManyTags, "tags", "a user can have at most "+strconv.Itoa(MaxUserTags)+" tags").
+			WithParams(map[string]any{"max": MaxUserTags})
+	ErrTagMatchInvalid = NewValidationError(CodeTagMatchInvalid, "tag_match", "tag_match must be one of: any, all").
+				WithParams(map[string]any{"allowed": []TagMatch{TagMatchAny, TagMatchAll}})
+	ErrFilterRequired = NewValidationError(CodeFilterRequired, "filter", "filter must name at least one tag or attribute")
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,39}$`)
+
+// ParseTags canonicalizes tags, which are lowercased and must start with a
+// letter or digit followed by up to 39 letters, digits, underscores or
+// dashes. Every invalid tag is reported under field.
+func ParseTags(field string, raw []string) ([]string, error) {
+	var errs ValidationErrors
+	tags := make([]string, 0, len(raw))
+	for _, value := range raw {
+		tag := strings.ToLower(strings.TrimSpace(value))
+		if !tagPattern.MatchString(tag) {
+			errs.Add(NewValidationError(CodeTagInvalid, field,
+				"tags must start with a letter or digit and contain only letters, digits, underscores and dashes, up to 40 characters").
+				WithParams(map[string]any{"tag": value}))
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// TagMatch says whether a tag filter needs any or all of its tags.
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any"
+	TagMatchAll TagMatch = "all"
+)
+
+// ParseTagMatch defaults to TagMatchAny.
+func ParseTagMatch(raw string) (TagMatch, error) {
+	switch m := TagMatch(strings.ToLower(strings.TrimSpace(raw))); m {
+	case "":
+		return TagMatchAny, nil
+	case TagMatchAny, TagMatchAll:
+		return m, nil
+	}
+	return "", ErrTagMatchInvalid
+}
+
+// HasTag expects a canonical tag, as returned by ParseTags.
+func (u *User) HasTag(tag string) bool {
+	_, found := slices.BinarySearch(u.Tags, tag)
+	return found
+}
+
+// UpdateTags removes and then adds canonical tags, so a tag in both lists
+// ends up set. It reports whether the tags changed.
+func (u *User) UpdateTags(add, remove []string) (bool, error) {
+	tags := slices.DeleteFunc(slices.Clone(u.Tags), func(tag string) bool {
+		return slices.Contains(remove, tag)
+	})
+	for _, tag := range add {
+		if i, found := slices.BinarySearch(tags, tag); !found {
+			tags = slices.Insert(tags, i, tag)
+		}
+	}
+	if len(tags) > MaxUserTags {
+		return false, ErrTooManyTags
+	}
+	if slices.Equal(tags, u.Tags) {
+		return false, nil
+	}
+
+	if len(tags) == 0 {
+		tags = nil
+	}
+	u.Tags = tags
+	return true, nil
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// TenantID identifies one customer of a shared deployment. Usernames and
+// email addresses are unique within a tenant, not across tenants.
+type TenantID string
+
+// DefaultTenantID is the tenant of users stored before tenancy existed.
+const DefaultTenantID TenantID = "default"
+
+const CodeTenantInvalid ErrorCode = "tenant_invalid"
+
+var ErrInvalidTenant = NewValidationError(CodeTenantInvalid, "tenant",
+	"tenant must be 1 to 63 lowercase letters, digits or hyphens, starting with a letter or digit")
+
+// ErrTenantRequired is returned by repositories asked to work outside of any
+// tenant. It signals a programming error, not bad input.
+var ErrTenantRequired = errors.New("tenant is required")
+
+var tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// ParseTenantID normalizes a tenant ID, which doubles as a DNS label so that
+// tenants can be addressed by subdomain.
+func ParseTenantID(tenant string) (TenantID, error) {
+	tenant = strings.ToLower(strings.TrimSpace(tenant))
+	if !tenantPattern.MatchString(tenant) {
+		return "", ErrInvalidTenant
+	}
+	return TenantID(tenant), nil
+}
+
+func (id TenantID) String() string {
+	return string(id)
+}
@@ -1,10 +1,9 @@
 package domain
 
 import (
-	"errors"
 	"fmt"
-	"regexp"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -20,37 +19,79 @@ func (id UserID) String() string {
 }
 
 type User struct {
-	ID       UserID `json:"id"`
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Username string `json:"username"`
+	ID              UserID     `json:"id"`
+	TenantID        TenantID   `json:"tenant_id"`
+	Name            string     `json:"name"`
+	Email           Email      `json:"email"`
+	Username        Username   `json:"username"`
+	EmailVerified   bool       `json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// PendingEmail is a requested new address awaiting confirmation. Email
+	// stays active until it is verified.
+	PendingEmail      Email       `json:"pending_email,omitempty"`
+	Role              Role        `json:"role"`
+	PasswordHash      string      `json:"-"`
+	PasswordChangedAt *time.Time  `json:"password_changed_at,omitempty"`
+	TOTP              *TOTPFactor `json:"-"`
+	// RecoveryCodes holds hashes of the unused MFA recovery codes.
+	RecoveryCodes       []string             `json:"-"`
+	WebAuthnCredentials []WebAuthnCredential `json:"-"`
+	// SuspendedAt is set while an administrator has suspended the account.
+	SuspendedAt *time.Time `json:"suspended_at,omitempty"`
+	Profile     Profile    `json:"-"`
+	// Avatar is the last uploaded profile picture.
+	Avatar *Avatar `json:"-"`
+	// Attributes holds the custom attributes defined by AttributeSchema,
+	// by name.
+	Attributes map[string]any `json:"-"`
+	// Tags is the sorted set of labels attached to the user.
+	Tags []string `json:"-"`
+
+	events []Event
 }
 
+const (
+	CodeNameRequired     ErrorCode = "name_required"
+	CodeEmailInvalid     ErrorCode = "email_invalid"
+	CodeUsernameTooShort ErrorCode = "username_too_short"
+	CodeUserNotFound     ErrorCode = "user_not_found"
+	CodeEmailExists      ErrorCode = "email_exists"
+	CodeUsernameExists   ErrorCode = "username_exists"
+	CodeEmailNotPending  ErrorCode = "email_verification_not_pending"
+	CodeUserSuspended    ErrorCode = "account_suspended"
+)
+
 var (
-	ErrInvalidName     = errors.New("name cannot be empty")
-	ErrInvalidEmail    = errors.New("email format is invalid")
-	ErrInvalidUsername = errors.New("username must be at least 3 characters")
-	ErrUserNotFound    = errors.New("user not found")
-	ErrEmailExists     = errors.New("email already exists")
-	ErrUsernameExists  = errors.New("username already exists")
+	ErrInvalidName     = NewValidationError(CodeNameRequired, "name", "name cannot be empty")
+	ErrInvalidEmail    = NewValidationError(CodeEmailInvalid, "email", "email format is invalid")
+	ErrUserNotFound    = NewNotFoundError(CodeUserNotFound, "user not found")
+	ErrEmailExists     = NewConflictError(CodeEmailExists, "email", "email already exists")
+	ErrUsernameExists  = NewConflictError(CodeUsernameExists, "username", "username already exists")
+	ErrEmailNotPending = NewConflictError(CodeEmailNotPending, "token",
+		"this email address is not awaiting verification")
+	ErrUserSuspended = NewForbiddenError(CodeUserSuspended, "this account is suspended")
 )
 
-func NewUser(name, email, username string) (*User, error) {
-	if err := validateName(name); err != nil {
-		return nil, err
-	}
-	if err := validateEmail(email); err != nil {
-		return nil, err
-	}
-	if err := validateUsername(username); err != nil {
+// NewUser validates every field and reports all failures together as
+// ValidationErrors.
+func NewUser(tenant TenantID, name, email, username string) (*User, error) {
+	var errs ValidationErrors
+	errs.Add(validateName(name))
+	parsedEmail, err := ParseEmail(email)
+	errs.Add(err)
+	parsedUsername, err := ParseUsername(username)
+	errs.Add(err)
+	if err := errs.Err(); err != nil {
 		return nil, err
 	}
 
 	return &User{
 		ID:       NewUserID(),
+		TenantID: tenant,
 		Name:     strings.TrimSpace(name),
-		Email:    strings.ToLower(strings.TrimSpace(email)),
-		Username: strings.ToLower(strings.TrimSpace(username)),
+		Email:    parsedEmail,
+		Username: parsedUsername,
+		Role:     RoleUser,
 	}, nil
 }
 
@@ -62,46 +103,118 @@ func (u *User) UpdateName(name string) error {
 	return nil
 }
 
-func (u *User) UpdateEmail(email string) error {
-	if err := validateEmail(email); err != nil {
+// UpdateEmail requests a change of address. An unverified address is simply
+// replaced; a verified one stays active and the new address is held in
+// PendingEmail until it is confirmed. Setting the current address again
+// cancels a pending change.
+func (u *User) UpdateEmail(raw string) error {
+	email, err := ParseEmail(raw)
+	if err != nil {
 		return err
 	}
-	u.Email = strings.ToLower(strings.TrimSpace(email))
+
+	switch {
+	case email == u.Email:
+		u.PendingEmail = ""
+	case !u.EmailVerified:
+		u.Email = email
+		u.PendingEmail = ""
+	default:
+		u.PendingEmail = email
+	}
 	return nil
 }
 
-func (u *User) UpdateUsername(username string) error {
-	if err := validateUsername(username); err != nil {
-		return err
+// EmailToVerify returns the address that still needs confirming, if any.
+func (u *User) EmailToVerify() Email {
+	if u.PendingEmail != "" {
+		return u.PendingEmail
 	}
-	u.Username = strings.ToLower(strings.TrimSpace(username))
-	return nil
+	if !u.EmailVerified {
+		return u.Email
+	}
+	return ""
 }
 
-func validateName(name string) error {
-	if strings.TrimSpace(name) == "" {
-		return ErrInvalidName
+// VerifyEmail confirms ownership of email. It only succeeds for the address
+// currently awaiting verification, which makes each token single use.
+func (u *User) VerifyEmail(email Email, at time.Time) error {
+	if email == "" || email != u.EmailToVerify() {
+		return ErrEmailNotPending
 	}
+
+	u.Email = email
+	u.PendingEmail = ""
+	u.EmailVerified = true
+	verifiedAt := at.UTC()
+	u.EmailVerifiedAt = &verifiedAt
 	return nil
 }
 
-func validateEmail(email string) error {
-	email = strings.TrimSpace(email)
-	if email == "" {
-		return ErrInvalidEmail
+// SetPassword stores a new password hash. Hashing happens outside the domain;
+// callers validate the plain password with ValidatePassword first.
+func (u *User) SetPassword(hash string, at time.Time) {
+	changedAt := at.UTC()
+	u.PasswordHash = hash
+	u.PasswordChangedAt = &changedAt
+	u.record(PasswordChanged{UserID: u.ID, OccurredAt: changedAt})
+}
+
+func (u *User) HasPassword() bool {
+	return u.PasswordHash != ""
+}
+
+// PullEvents returns the events recorded since the last call and clears them.
+func (u *User) PullEvents() []Event {
+	events := u.events
+	u.events = nil
+	return events
+}
+
+func (u *User) record(event Event) {
+	u.events = append(u.events, event)
+}
+
+func (u *User) AssignRole(role Role) {
+	u.Role = role
+}
+
+// Suspend blocks the account from signing in. Suspending an already
+// suspended account changes nothing.
+func (u *User) Suspend(at time.Time) {
+	if u.Suspended() {
+		return
 	}
+	suspendedAt := at.UTC()
+	u.SuspendedAt = &suspendedAt
+	u.record(UserSuspended{UserID: u.ID, OccurredAt: suspendedAt})
+}
 
-	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-	if !emailRegex.MatchString(email) {
-		return ErrInvalidEmail
+func (u *User) Reinstate() {
+	u.SuspendedAt = nil
+}
+
+func (u *User) Suspended() bool {
+	return u.SuspendedAt != nil
+}
+
+// UpdateUsername checks the username against the current rules unless it
+// is the user's own, so usernames that predate the rules can be kept.
+func (u *User) UpdateUsername(raw string) error {
+	if NormalizeUsername(raw) == u.Username {
+		return nil
 	}
+	username, err := ParseUsername(raw)
+	if err != nil {
+		return err
+	}
+	u.Username = username
 	return nil
 }
 
-func validateUsername(username string) error {
-	username = strings.TrimSpace(username)
-	if len(username) < 3 {
-		return ErrInvalidUsername
+func validateName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrInvalidName
 	}
 	return nil
 }
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	CodeUsernameTooLong      ErrorCode = "username_too_long"
+	CodeUsernameCharsInvalid ErrorCode = "username_invalid_characters"
+	CodeUsernameReserved     ErrorCode = "username_reserved"
+	CodeUsernameBlocked      ErrorCode = "username_blocked"
+	CodeUsernameConfusable   ErrorCode = "username_confusable"
+)
+
+const minUsernameLength = 3
+
+var (
+	ErrInvalidUsername = NewValidationError(CodeUsernameTooShort, "username", "username must be at least 3 characters").
+				WithParams(map[string]any{"min": minUsernameLength})
+	ErrUsernameTooLong      = NewValidationError(CodeUsernameTooLong, "username", "username is too long")
+	ErrUsernameCharsInvalid = NewValidationError(CodeUsernameCharsInvalid, "username", "username contains characters that are not allowed")
+	ErrUsernameReserved     = NewValidationError(CodeUsernameReserved, "username", "this username is reserved")
+	ErrUsernameBlocked      = NewValidationError(CodeUsernameBlocked, "username", "username contains a blocked word")
+	ErrUsernameConfusable   = NewConflictError(CodeUsernameConfusable, "username", "username looks too similar to an existing one")
+)
+
+// UsernameCharset names the characters usernames may contain. Both allow
+// '.', '_' and '-' besides letters and digits.
+type UsernameCharset string
+
+const (
+	// UsernameCharsetASCII allows a-z and 0-9.
+	UsernameCharsetASCII UsernameCharset = "ascii"
+	// UsernameCharsetUnicode allows letters and digits of any script.
+	UsernameCharsetUnicode UsernameCharset = "unicode"
+)
+
+func ParseUsernameCharset(charset string) (UsernameCharset, bool) {
+	switch c := UsernameCharset(strings.ToLower(strings.TrimSpace(charset))); c {
+	case UsernameCharsetASCII, UsernameCharsetUnicode:
+		return c, true
+	}
+	return "", false
+}
+
+// UsernameRules decide which usernames can be chosen. They apply to new and
+// changed usernames only; existing ones keep working. Set them once at start
+// up with SetUsernameRules. Reserved usernames are a policy list, checked
+// outside the domain.
+type UsernameRules struct {
+	Charset   UsernameCharset
+	MaxLength int
+}
+
+func DefaultUsernameRules() UsernameRules {
+	return UsernameRules{
+		Charset:   UsernameCharsetUnicode,
+		MaxLength: 32,
+	}
+}
+
+var usernameRules = DefaultUsernameRules()
+
+// SetUsernameRules must be called before any username is parsed.
+func SetUsernameRules(rules UsernameRules) {
+	usernameRules = rules
+}
+
+// Username is a username in canonical form: NFKC-normalized and lower case.
+type Username string
+
+// ParseUsername checks a new username against the current rules and
+// returns its canonical form.
+func ParseUsername(raw string) (Username, error) {
+	username := NormalizeUsername(raw)
+
+	length := utf8.RuneCountInString(string(username))
+	if length < minUsernameLength {
+		return "", ErrInvalidUsername
+	}
+	if length > usernameRules.MaxLength {
+		return "", ErrUsernameTooLong.WithMessage("username must be at most " + strconv.Itoa(usernameRules.MaxLength) + " characters").
+			WithParams(map[string]any{"max": usernameRules.MaxLength})
+	}
+	for _, r := range string(username) {
+		if !usernameRules.allows(r) {
+			return "", ErrUsernameCharsInvalid.WithParams(map[string]any{"charset": usernameRules.Charset})
+		}
+	}
+	return username, nil
+}
+
+// NormalizeUsername returns the canonical form without checking the rules,
+// for looking up usernames that may predate them.
+func NormalizeUsername(raw string) Username {
+	return Username(strings.ToLower(norm.NFKC.String(strings.TrimSpace(raw))))
+}
+
+func (u Username) String() string {
+	return string(u)
+}
+
+func (r UsernameRules) allows(c rune) bool {
+	if c == '.' || c == '_' || c == '-' {
+		return true
+	}
+	if r.Charset == UsernameCharsetASCII {
+		return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+	}
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || unicode.IsMark(c)
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// withUsernameRules sets the rules for the rest of the test.
+func withUsernameRules(t *testing.T, rules UsernameRules) {
+	previous := usernameRules
+	SetUsernameRules(rules)
+	t.Cleanup(func() { SetUsernameRules(previous) })
+}
+
+func TestParseUsername(t *testing.T) {
+	ascii := UsernameRules{Charset: UsernameCharsetASCII, MaxLength: 8}
+	unicode := UsernameRules{Charset: UsernameCharsetUnicode, MaxLength: 8}
+
+	tests := []struct {
+		name    string
+		rules   UsernameRules
+		raw     string
+		want    Username
+		wantErr error
+	}{
+		{"canonical", ascii, "jane", "jane", nil},
+		{"trimmed and lower-cased", ascii, "  Jane ", "jane", nil},
+		{"separators", ascii, "j.a_n-e", "j.a_n-e", nil},
+		{"full-width folded to ASCII", ascii, "ｊａｎｅ", "jane", nil},
+		{"shortest", ascii, "abc", "abc", nil},
+		{"too short", ascii, "ab", "", ErrInvalidUsername},
+		{"longest", ascii, "abcdefgh", "abcdefgh", nil},
+		{"too long", ascii, "abcdefghi", "", ErrUsernameTooLong},
+		{"length counts characters, not bytes", unicode, "éééééééé", "éééééééé", nil},
+		{"accented letters under ascii", ascii, "josé", "", ErrUsernameCharsInvalid},
+		{"accented letters under unicode", unicode, "josé", "josé", nil},
+		{"other scripts under unicode", unicode, "ユーザー", "ユーザー", nil},
+		{"space", unicode, "ja ne", "", ErrUsernameCharsInvalid},
+		{"at sign", unicode, "ja@ne", "", ErrUsernameCharsInvalid},
+		{"emoji", unicode, "jane🙂", "", ErrUsernameCharsInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withUsernameRules(t, tt.rules)
+			got, err := ParseUsername(tt.raw)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseUsername(%q) = %q, %v, want %v", tt.raw, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("ParseUsername(%q) = %q, %v, want %q", tt.raw, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUsernameReportsLimits(t *testing.T) {
+	withUsernameRules(t, UsernameRules{Charset: UsernameCharsetASCII, MaxLength: 5})
+
+	_, err := ParseUsername(strings.Repeat("a", 6))
+	var domainErr *Error
+	if !errors.As(err, &domainErr) || domainErr.Params["max"] != 5 {
+		t.Fatalf("err = %v, want max 5 in params", err)
+	}
+	_, err = ParseUsername("josé")
+	if !errors.As(err, &domainErr) || domainErr.Params["charset"] != UsernameCharsetASCII {
+		t.Fatalf("err = %v, want charset ascii in params", err)
+	}
+}
+
+func TestParseUsernameCharset(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want UsernameCharset
+		ok   bool
+	}{
+		{"ascii", UsernameCharsetASCII, true},
+		{" Unicode ", UsernameCharsetUnicode, true},
+		{"latin1", "", false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := ParseUsernameCharset(tt.raw); got != tt.want || ok != tt.ok {
+			t.Errorf("ParseUsernameCharset(%q) = %q, %v, want %q, %v", tt.raw, got, ok, tt.want, tt.ok)
+		}
+	}
+}
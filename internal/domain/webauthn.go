@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+const (
+	CodeCredentialNotFound     ErrorCode = "webauthn_credential_not_found"
+	CodeCredentialExists       ErrorCode = "webauthn_credential_exists"
+	CodeCredentialNameInvalid  ErrorCode = "webauthn_credential_name_invalid"
+	CodeCredentialCounterReuse ErrorCode = "webauthn_sign_count_invalid"
+)
+
+const maxCredentialNameLength = 64
+
+var (
+	ErrCredentialNotFound    = NewNotFoundError(CodeCredentialNotFound, "authenticator not found")
+	ErrCredentialExists      = NewConflictError(CodeCredentialExists, "", "this authenticator is already registered")
+	ErrCredentialNameInvalid = NewValidationError(CodeCredentialNameInvalid, "name", "name must be 1 to 64 characters").
+					WithParams(map[string]any{"max": maxCredentialNameLength})
+	// ErrCredentialCloned is returned when an authenticator's signature
+	// counter went backwards, which suggests its key was copied.
+	ErrCredentialCloned = NewConflictError(CodeCredentialCounterReuse, "",
+		"the authenticator's signature counter did not increase; it may have been cloned")
+)
+
+// WebAuthnCredential is a registered authenticator (security key or
+// passkey). Only its public key is stored.
+type WebAuthnCredential struct {
+	ID              []byte
+	Name            string
+	PublicKey       []byte
+	AttestationType string
+	Transports      []string
+	AAGUID          []byte
+	SignCount       uint32
+	BackupEligible  bool
+	BackupState     bool
+	CreatedAt       time.Time
+	LastUsedAt      *time.Time
+}
+
+func (u *User) AddWebAuthnCredential(credential WebAuthnCredential) error {
+	name, err := ValidateCredentialName(credential.Name)
+	if err != nil {
+		return err
+	}
+	if u.WebAuthnCredential(credential.ID) != nil {
+		return ErrCredentialExists
+	}
+	credential.Name = name
+	credential.CreatedAt = credential.CreatedAt.UTC()
+	u.WebAuthnCredentials = append(u.WebAuthnCredentials, credential)
+	return nil
+}
+
+// WebAuthnCredential returns the credential with the given ID, or nil.
+func (u *User) WebAuthnCredential(id []byte) *WebAuthnCredential {
+	for i := range u.WebAuthnCredentials {
+		if bytes.Equal(u.WebAuthnCredentials[i].ID, id) {
+			return &u.WebAuthnCredentials[i]
+		}
+	}
+	return nil
+}
+
+func (u *User) RenameWebAuthnCredential(id []byte, name string) error {
+	credential := u.WebAuthnCredential(id)
+	if credential == nil {
+		return ErrCredentialNotFound
+	}
+	name, err := ValidateCredentialName(name)
+	if err != nil {
+		return err
+	}
+	credential.Name = name
+	return nil
+}
+
+func (u *User) RemoveWebAuthnCredential(id []byte) error {
+	for i := range u.WebAuthnCredentials {
+		if bytes.Equal(u.WebAuthnCredentials[i].ID, id) {
+			u.WebAuthnCredentials = append(u.WebAuthnCredentials[:i:i], u.WebAuthnCredentials[i+1:]...)
+			return nil
+		}
+	}
+	return ErrCredentialNotFound
+}
+
+// RecordWebAuthnUse stores the signature counter from a successful
+// assertion. Authenticators that do not implement a counter always report
+// zero; any other counter must increase with every use.
+func (u *User) RecordWebAuthnUse(id []byte, signCount uint32, at time.Time) error {
+	credential := u.WebAuthnCredential(id)
+	if credential == nil {
+		return ErrCredentialNotFound
+	}
+	if (signCount != 0 || credential.SignCount != 0) && signCount <= credential.SignCount {
+		return ErrCredentialCloned
+	}
+	usedAt := at.UTC()
+	credential.SignCount = signCount
+	credential.LastUsedAt = &usedAt
+	return nil
+}
+
+// ValidateCredentialName trims the name and checks its length.
+func ValidateCredentialName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxCredentialNameLength {
+		return "", ErrCredentialNameInvalid
+	}
+	return name, nil
+}
@@ -0,0 +1,96 @@
+package blob
+
+import (
+	"context"
+	"ddd-user-service/internal/application/blob"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+)
+
+// FilesystemStore keeps each object as a file under a directory. The content
+// type is derived from the key's extension, so keys should carry one.
+type FilesystemStore struct {
+	dir string
+}
+
+func NewFilesystemStore(dir string) (*FilesystemStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	return &FilesystemStore{dir: dir}, nil
+}
+
+func (s *FilesystemStore) Put(ctx context.Context, key string, obj blob.Object) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	// Written aside and renamed, so readers never see a partial file.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".blob-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(obj.Data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write blob file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write blob file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store blob file: %w", err)
+	}
+	return nil
+}
+
+func (s *FilesystemStore) Get(ctx context.Context, key string) (blob.Object, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return blob.Object{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return blob.Object{}, blob.ErrNotFound
+	}
+	if err != nil {
+		return blob.Object{}, fmt.Errorf("failed to read blob file: %w", err)
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return blob.Object{ContentType: contentType, Data: data}, nil
+}
+
+func (s *FilesystemStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob file: %w", err)
+	}
+	// The directory goes with its last file; this fails harmlessly while
+	// other files remain.
+	os.Remove(filepath.Dir(path))
+	return nil
+}
+
+// path refuses keys that would leave the directory.
+func (s *FilesystemStore) path(key string) (string, error) {
+	rel := filepath.FromSlash(key)
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, rel), nil
+}
@@ -0,0 +1,112 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"ddd-user-service/internal/application/blob"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GridFSStore keeps objects in a MongoDB GridFS bucket, using the key as the
+// file name.
+type GridFSStore struct {
+	bucket *gridfs.Bucket
+}
+
+type gridFSFile struct {
+	ID       any `bson:"_id"`
+	Metadata struct {
+		ContentType string `bson:"content_type"`
+	} `bson:"metadata"`
+}
+
+func NewGridFSStore(db *mongo.Database, bucketName string) (*GridFSStore, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(bucketName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GridFS bucket: %w", err)
+	}
+	return &GridFSStore{bucket: bucket}, nil
+}
+
+func (s *GridFSStore) Put(ctx context.Context, key string, obj blob.Object) error {
+	previous, err := s.files(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	opts := options.GridFSUpload().SetMetadata(bson.M{"content_type": obj.ContentType})
+	if _, err := s.bucket.UploadFromStream(key, bytes.NewReader(obj.Data), opts); err != nil {
+		return fmt.Errorf("failed to upload blob: %w", err)
+	}
+
+	// Older revisions are removed once the new one is readable.
+	for _, file := range previous {
+		if err := s.bucket.DeleteContext(ctx, file.ID); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return fmt.Errorf("failed to delete blob revision: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *GridFSStore) Get(ctx context.Context, key string) (blob.Object, error) {
+	opts := options.GridFSFind().SetSort(bson.D{{Key: "uploadDate", Value: -1}}).SetLimit(1)
+	cursor, err := s.bucket.FindContext(ctx, bson.M{"filename": key}, opts)
+	if err != nil {
+		return blob.Object{}, fmt.Errorf("failed to find blob: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		if err := cursor.Err(); err != nil {
+			return blob.Object{}, fmt.Errorf("failed to find blob: %w", err)
+		}
+		return blob.Object{}, blob.ErrNotFound
+	}
+	var file gridFSFile
+	if err := cursor.Decode(&file); err != nil {
+		return blob.Object{}, fmt.Errorf("failed to decode blob: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := s.bucket.DownloadToStream(file.ID, &buf); err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return blob.Object{}, blob.ErrNotFound
+		}
+		return blob.Object{}, fmt.Errorf("failed to download blob: %w", err)
+	}
+	return blob.Object{ContentType: file.Metadata.ContentType, Data: buf.Bytes()}, nil
+}
+
+func (s *GridFSStore) Delete(ctx context.Context, key string) error {
+	files, err := s.files(ctx, key)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := s.bucket.DeleteContext(ctx, file.ID); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return fmt.Errorf("failed to delete blob: %w", err)
+		}
+	}
+	return nil
+}
+
+// files returns every stored revision of the key.
+func (s *GridFSStore) files(ctx context.Context, key string) ([]gridFSFile, error) {
+	cursor, err := s.bucket.FindContext(ctx, bson.M{"filename": key})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find blob: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var files []gridFSFile
+	if err := cursor.All(ctx, &files); err != nil {
+		return nil, fmt.Errorf("failed to decode blob: %w", err)
+	}
+	return files, nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// AdminConfig describes the administrator created at startup, so that a new
+// deployment has someone who can use the admin API. Nothing is created when
+// Email is empty.
+type AdminConfig struct {
+	Email    string
+	Username string
+	Password string
+	Name     string
+}
+
+func NewAdminConfig() (*AdminConfig, error) {
+	cfg := &AdminConfig{
+		Email:    os.Getenv("ADMIN_EMAIL"),
+		Username: os.Getenv("ADMIN_USERNAME"),
+		Password: os.Getenv("ADMIN_PASSWORD"),
+		Name:     getEnv("ADMIN_NAME", "Administrator"),
+	}
+
+	if cfg.Email == "" {
+		return cfg, nil
+	}
+	if cfg.Username == "" {
+		return nil, fmt.Errorf("ADMIN_USERNAME: required with ADMIN_EMAIL")
+	}
+	if cfg.Password == "" {
+		return nil, fmt.Errorf("ADMIN_PASSWORD: required with ADMIN_EMAIL")
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type APIKeyConfig struct {
+	// RotationOverlap is how long a rotated key keeps working by default.
+	RotationOverlap time.Duration
+}
+
+func NewAPIKeyConfig() *APIKeyConfig {
+	cfg := &APIKeyConfig{
+		RotationOverlap: 24 * time.Hour,
+	}
+
+	if overlap, err := time.ParseDuration(os.Getenv("API_KEY_ROTATION_OVERLAP")); err == nil && overlap >= 0 {
+		cfg.RotationOverlap = overlap
+	}
+
+	return cfg
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"crypto/rand"
+	"os"
+)
+
+type AuditConfig struct {
+	// HMACKey keys the hashes that chain the audit log.
+	HMACKey []byte
+	// Ephemeral is true when no key was configured and a random one was
+	// generated, so entries written before a restart will no longer verify.
+	Ephemeral bool
+}
+
+func NewAuditConfig() (*AuditConfig, error) {
+	cfg := &AuditConfig{
+		HMACKey: []byte(os.Getenv("AUDIT_HMAC_KEY")),
+	}
+
+	if len(cfg.HMACKey) == 0 {
+		cfg.HMACKey = make([]byte, 32)
+		if _, err := rand.Read(cfg.HMACKey); err != nil {
+			return nil, err
+		}
+		cfg.Ephemeral = true
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"crypto/rand"
+	"ddd-user-service/internal/domain"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+type AuthConfig struct {
+	// Secret signs MFA challenge tokens.
+	Secret []byte
+	// Ephemeral is true when no secret was configured and a random one was
+	// generated, so tokens will not survive a restart.
+	Ephemeral       bool
+	MFAChallengeTTL time.Duration
+	// MFARequiredRoles lists the roles that may not sign in without a second
+	// factor.
+	MFARequiredRoles []domain.Role
+	TOTPIssuer       string
+}
+
+func NewAuthConfig() (*AuthConfig, error) {
+	cfg := &AuthConfig{
+		Secret:          []byte(os.Getenv("AUTH_TOKEN_SECRET")),
+		MFAChallengeTTL: 5 * time.Minute,
+		TOTPIssuer:      getEnv("MFA_TOTP_ISSUER", "DDD User Service"),
+	}
+
+	if ttl, err := time.ParseDuration(os.Getenv("MFA_CHALLENGE_TTL")); err == nil && ttl > 0 {
+		cfg.MFAChallengeTTL = ttl
+	}
+
+	for _, raw := range strings.Split(getEnv("MFA_REQUIRED_ROLES", "admin"), ",") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		role, err := domain.ParseRole(raw)
+		if err != nil {
+			return nil, fmt.Errorf("MFA_REQUIRED_ROLES: unknown role %q", raw)
+		}
+		cfg.MFARequiredRoles = append(cfg.MFARequiredRoles, role)
+	}
+
+	if len(cfg.Secret) == 0 {
+		cfg.Secret = make([]byte, 32)
+		if _, err := rand.Read(cfg.Secret); err != nil {
+			return nil, err
+		}
+		cfg.Ephemeral = true
+	}
+
+	return cfg, nil
+}
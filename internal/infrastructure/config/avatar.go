@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	AvatarStorageFilesystem = "filesystem"
+	AvatarStorageGridFS     = "gridfs"
+
+	minAvatarSize = 16
+	maxAvatarSize = 2048
+)
+
+type AvatarConfig struct {
+	// Storage is empty to use GridFS when MongoDB is connected and the
+	// filesystem otherwise.
+	Storage string
+	Dir     string
+	// BaseURL is where clients reach this service; avatar URLs are built on
+	// it.
+	BaseURL   string
+	MaxBytes  int64
+	MaxPixels int
+	Sizes     []int
+}
+
+func NewAvatarConfig() (*AvatarConfig, error) {
+	cfg := &AvatarConfig{
+		Storage:   os.Getenv("AVATAR_STORAGE"),
+		Dir:       getEnv("AVATAR_DIR", "avatars"),
+		BaseURL:   getEnv("AVATAR_BASE_URL", "http://localhost:"+getEnv("PORT", "8080")),
+		MaxBytes:  5 << 20,
+		MaxPixels: 40_000_000,
+		Sizes:     []int{512, 256, 128, 64},
+	}
+
+	switch cfg.Storage {
+	case "", AvatarStorageFilesystem, AvatarStorageGridFS:
+	default:
+		return nil, fmt.Errorf("AVATAR_STORAGE: unknown storage %q", cfg.Storage)
+	}
+
+	if u, err := url.Parse(cfg.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("AVATAR_BASE_URL: expected an absolute http or https URL, got %q", cfg.BaseURL)
+	}
+
+	if raw := os.Getenv("AVATAR_MAX_BYTES"); raw != "" {
+		maxBytes, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || maxBytes <= 0 {
+			return nil, fmt.Errorf("AVATAR_MAX_BYTES: invalid number %q", raw)
+		}
+		cfg.MaxBytes = maxBytes
+	}
+
+	if raw := os.Getenv("AVATAR_MAX_PIXELS"); raw != "" {
+		maxPixels, err := strconv.Atoi(raw)
+		if err != nil || maxPixels <= 0 {
+			return nil, fmt.Errorf("AVATAR_MAX_PIXELS: invalid number %q", raw)
+		}
+		cfg.MaxPixels = maxPixels
+	}
+
+	if raw := os.Getenv("AVATAR_SIZES"); raw != "" {
+		cfg.Sizes = nil
+		for _, field := range strings.Split(raw, ",") {
+			size, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || size < minAvatarSize || size > maxAvatarSize {
+				return nil, fmt.Errorf("AVATAR_SIZES: expected sizes from %d to %d pixels, got %q", minAvatarSize, maxAvatarSize, field)
+			}
+			cfg.Sizes = append(cfg.Sizes, size)
+		}
+	}
+
+	return cfg, nil
+}
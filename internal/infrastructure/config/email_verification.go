@@ -0,0 +1,36 @@
+package config
+
+import (
+	"crypto/rand"
+	"os"
+	"time"
+)
+
+type EmailVerificationConfig struct {
+	Secret []byte
+	TTL    time.Duration
+	// Ephemeral is true when no secret was configured and a random one was
+	// generated, so tokens will not survive a restart.
+	Ephemeral bool
+}
+
+func NewEmailVerificationConfig() (*EmailVerificationConfig, error) {
+	cfg := &EmailVerificationConfig{
+		Secret: []byte(os.Getenv("EMAIL_VERIFICATION_SECRET")),
+		TTL:    24 * time.Hour,
+	}
+
+	if ttl, err := time.ParseDuration(os.Getenv("EMAIL_VERIFICATION_TTL")); err == nil && ttl > 0 {
+		cfg.TTL = ttl
+	}
+
+	if len(cfg.Secret) == 0 {
+		cfg.Secret = make([]byte, 32)
+		if _, err := rand.Read(cfg.Secret); err != nil {
+			return nil, err
+		}
+		cfg.Ephemeral = true
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+type HTTPConfig struct {
+	// TrustedProxies lists the IPs and CIDRs of the reverse proxies whose
+	// X-Forwarded-For and X-Real-IP headers are believed. Empty trusts none,
+	// so the client IP is always the connection's peer.
+	TrustedProxies []string
+}
+
+func NewHTTPConfig() (*HTTPConfig, error) {
+	cfg := &HTTPConfig{}
+
+	for _, raw := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(raw); err != nil && net.ParseIP(raw) == nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES: %q is not an IP address or CIDR", raw)
+		}
+		cfg.TrustedProxies = append(cfg.TrustedProxies, raw)
+	}
+
+	return cfg, nil
+}
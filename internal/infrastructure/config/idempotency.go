@@ -0,0 +1,22 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type IdempotencyConfig struct {
+	TTL time.Duration
+}
+
+func NewIdempotencyConfig() *IdempotencyConfig {
+	cfg := &IdempotencyConfig{
+		TTL: 24 * time.Hour,
+	}
+
+	if ttl, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL")); err == nil && ttl > 0 {
+		cfg.TTL = ttl
+	}
+
+	return cfg
+}
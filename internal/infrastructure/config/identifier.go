@@ -0,0 +1,41 @@
+package config
+
+import (
+	"ddd-user-service/internal/domain"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// IdentifierConfig holds the rules for email addresses and usernames.
+type IdentifierConfig struct {
+	Email    domain.EmailRules
+	Username domain.UsernameRules
+}
+
+func NewIdentifierConfig() (*IdentifierConfig, error) {
+	cfg := &IdentifierConfig{
+		Email: domain.EmailRules{
+			FoldPlusAddressing: os.Getenv("EMAIL_FOLD_PLUS_ADDRESSING") == "true",
+		},
+		Username: domain.DefaultUsernameRules(),
+	}
+
+	if raw := os.Getenv("USERNAME_CHARSET"); raw != "" {
+		charset, ok := domain.ParseUsernameCharset(raw)
+		if !ok {
+			return nil, fmt.Errorf("USERNAME_CHARSET: unknown charset %q", raw)
+		}
+		cfg.Username.Charset = charset
+	}
+
+	if raw := os.Getenv("USERNAME_MAX_LENGTH"); raw != "" {
+		maxLength, err := strconv.Atoi(raw)
+		if err != nil || maxLength < 3 {
+			return nil, fmt.Errorf("USERNAME_MAX_LENGTH: expected a number of at least 3, got %q", raw)
+		}
+		cfg.Username.MaxLength = maxLength
+	}
+
+	return cfg, nil
+}
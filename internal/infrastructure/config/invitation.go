@@ -0,0 +1,23 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type InvitationConfig struct {
+	// TTL is how long an invitation can be accepted after it was last sent.
+	TTL time.Duration
+}
+
+func NewInvitationConfig() *InvitationConfig {
+	cfg := &InvitationConfig{
+		TTL: 7 * 24 * time.Hour,
+	}
+
+	if ttl, err := time.ParseDuration(os.Getenv("INVITATION_TTL")); err == nil && ttl > 0 {
+		cfg.TTL = ttl
+	}
+
+	return cfg
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"ddd-user-service/internal/application/lockout"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+type LockoutConfig struct {
+	Account lockout.Policy
+	IP      lockout.Policy
+}
+
+func NewLockoutConfig() (*LockoutConfig, error) {
+	cfg := &LockoutConfig{
+		Account: lockout.Policy{
+			Threshold: 5,
+			Window:    15 * time.Minute,
+			Duration:  15 * time.Minute,
+			BaseDelay: time.Second,
+			MaxDelay:  30 * time.Second,
+		},
+		IP: lockout.Policy{
+			Threshold: 50,
+			Window:    15 * time.Minute,
+			Duration:  15 * time.Minute,
+		},
+	}
+
+	thresholds := map[string]*int{
+		"LOCKOUT_THRESHOLD":    &cfg.Account.Threshold,
+		"LOCKOUT_IP_THRESHOLD": &cfg.IP.Threshold,
+	}
+	for env, target := range thresholds {
+		raw := os.Getenv(env)
+		if raw == "" {
+			continue
+		}
+		threshold, err := strconv.Atoi(raw)
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("%s: expected a non-negative number, got %q", env, raw)
+		}
+		*target = threshold
+	}
+
+	durations := map[string]*time.Duration{
+		"LOCKOUT_WINDOW":     &cfg.Account.Window,
+		"LOCKOUT_DURATION":   &cfg.Account.Duration,
+		"LOCKOUT_BASE_DELAY": &cfg.Account.BaseDelay,
+		"LOCKOUT_MAX_DELAY":  &cfg.Account.MaxDelay,
+	}
+	for env, target := range durations {
+		raw := os.Getenv(env)
+		if raw == "" {
+			continue
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("%s: invalid duration %q", env, raw)
+		}
+		*target = d
+	}
+	cfg.IP.Window = cfg.Account.Window
+	cfg.IP.Duration = cfg.Account.Duration
+
+	return cfg, nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+type LoggingConfig struct {
+	Level           string
+	Format          string
+	RedactPII       bool
+	ComponentLevels map[string]string
+}
+
+func NewLoggingConfig() *LoggingConfig {
+	return &LoggingConfig{
+		Level:           getEnv("LOG_LEVEL", "info"),
+		Format:          getEnv("LOG_FORMAT", "json"),
+		RedactPII:       getEnv("LOG_REDACT_PII", "true") != "false",
+		ComponentLevels: parseComponentLevels(os.Getenv("LOG_COMPONENT_LEVELS")),
+	}
+}
+
+// parseComponentLevels reads a list such as "repository=debug,service=warn".
+func parseComponentLevels(raw string) map[string]string {
+	levels := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		component, level, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || component == "" {
+			continue
+		}
+		levels[strings.TrimSpace(component)] = strings.TrimSpace(level)
+	}
+	return levels
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	MailTransportLog  = "log"
+	MailTransportFile = "file"
+	MailTransportSMTP = "smtp"
+)
+
+type MailConfig struct {
+	Transport     string
+	From          string
+	DefaultLocale string
+	FileDir       string
+
+	SMTPHost       string
+	SMTPPort       int
+	SMTPUsername   string
+	SMTPPassword   string
+	SMTPRequireTLS bool
+
+	QueueWorkers   int
+	QueueSize      int
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func NewMailConfig() (*MailConfig, error) {
+	cfg := &MailConfig{
+		Transport:      getEnv("MAIL_TRANSPORT", MailTransportLog),
+		From:           getEnv("MAIL_FROM", "User Service <no-reply@localhost>"),
+		DefaultLocale:  getEnv("MAIL_DEFAULT_LOCALE", "en"),
+		FileDir:        getEnv("MAIL_FILE_DIR", "mail"),
+		SMTPHost:       getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:       25,
+		SMTPUsername:   os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:   os.Getenv("SMTP_PASSWORD"),
+		SMTPRequireTLS: os.Getenv("SMTP_REQUIRE_TLS") == "true",
+		QueueWorkers:   2,
+		QueueSize:      100,
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	}
+
+	ints := []struct {
+		target *int
+		env    string
+	}{
+		{&cfg.SMTPPort, "SMTP_PORT"},
+		{&cfg.QueueWorkers, "MAIL_QUEUE_WORKERS"},
+		{&cfg.QueueSize, "MAIL_QUEUE_SIZE"},
+		{&cfg.MaxAttempts, "MAIL_MAX_ATTEMPTS"},
+	}
+	for _, i := range ints {
+		raw := os.Getenv(i.env)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("%s: invalid number %q", i.env, raw)
+		}
+		*i.target = value
+	}
+
+	if backoff, err := time.ParseDuration(os.Getenv("MAIL_RETRY_BACKOFF")); err == nil && backoff > 0 {
+		cfg.InitialBackoff = backoff
+	}
+
+	switch cfg.Transport {
+	case MailTransportLog, MailTransportFile, MailTransportSMTP:
+	default:
+		return nil, fmt.Errorf("MAIL_TRANSPORT: unknown transport %q", cfg.Transport)
+	}
+
+	return cfg, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -13,6 +14,7 @@ type MongoConfig struct {
 	URI        string
 	Database   string
 	Collection string
+	Monitor    *event.CommandMonitor
 }
 
 func NewMongoConfig() *MongoConfig {
@@ -27,7 +29,12 @@ func (c *MongoConfig) Connect() (*mongo.Database, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(c.URI))
+	clientOpts := options.Client().ApplyURI(c.URI)
+	if c.Monitor != nil {
+		clientOpts.SetMonitor(c.Monitor)
+	}
+
+	client, err := mongo.Connect(ctx, clientOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
 	}
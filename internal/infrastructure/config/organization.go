@@ -0,0 +1,24 @@
+package config
+
+import (
+	"ddd-user-service/internal/application/service"
+	"fmt"
+)
+
+type OrganizationConfig struct {
+	// LastOwnerPolicy decides whether deleting the last owner of an
+	// organization is refused or deletes the organization too.
+	LastOwnerPolicy service.LastOwnerPolicy
+}
+
+func NewOrganizationConfig() (*OrganizationConfig, error) {
+	cfg := &OrganizationConfig{
+		LastOwnerPolicy: service.LastOwnerPolicy(getEnv("ORGANIZATION_LAST_OWNER_POLICY", string(service.LastOwnerBlock))),
+	}
+
+	if cfg.LastOwnerPolicy != service.LastOwnerBlock && cfg.LastOwnerPolicy != service.LastOwnerCascade {
+		return nil, fmt.Errorf("ORGANIZATION_LAST_OWNER_POLICY: unknown policy %q", cfg.LastOwnerPolicy)
+	}
+
+	return cfg, nil
+}
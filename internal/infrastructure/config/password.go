@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+type PasswordConfig struct {
+	// HashCost is the bcrypt work factor.
+	HashCost int
+	ResetTTL time.Duration
+}
+
+func NewPasswordConfig() *PasswordConfig {
+	cfg := &PasswordConfig{
+		HashCost: 12,
+		ResetTTL: 30 * time.Minute,
+	}
+
+	if cost, err := strconv.Atoi(os.Getenv("PASSWORD_HASH_COST")); err == nil && cost > 0 {
+		cfg.HashCost = cost
+	}
+
+	if ttl, err := time.ParseDuration(os.Getenv("PASSWORD_RESET_TTL")); err == nil && ttl > 0 {
+		cfg.ResetTTL = ttl
+	}
+
+	return cfg
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// defaultReservedUsernames applies while USERNAME_RESERVED is unset.
+var defaultReservedUsernames = []string{"admin", "administrator", "root", "system", "support", "api", "null", "undefined"}
+
+// PolicyConfig holds the lists the policy engine loads at start up.
+type PolicyConfig struct {
+	ReservedUsernames []string
+	BlockedWords      []string
+	BlockedDomains    []string
+	// DetectConfusables rejects usernames that look like an existing one.
+	DetectConfusables bool
+}
+
+func NewPolicyConfig() (*PolicyConfig, error) {
+	cfg := &PolicyConfig{
+		ReservedUsernames: defaultReservedUsernames,
+		DetectConfusables: getEnv("POLICY_DETECT_CONFUSABLES", "true") == "true",
+	}
+
+	// Set but empty reserves nothing.
+	if raw, ok := os.LookupEnv("USERNAME_RESERVED"); ok {
+		cfg.ReservedUsernames = nil
+		for _, word := range strings.Split(raw, ",") {
+			if word = strings.TrimSpace(word); word != "" {
+				cfg.ReservedUsernames = append(cfg.ReservedUsernames, word)
+			}
+		}
+	}
+
+	var err error
+	if cfg.BlockedWords, err = readListFile("POLICY_BLOCKED_WORDS_FILE"); err != nil {
+		return nil, err
+	}
+	if cfg.BlockedDomains, err = readListFile("POLICY_BLOCKED_DOMAINS_FILE"); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// readListFile reads one value per line from the file named by env, if
+// set. Blank lines and lines starting with '#' are skipped.
+func readListFile(env string) ([]string, error) {
+	path := os.Getenv(env)
+	if path == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", env, err)
+	}
+	defer file.Close()
+
+	var values []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		values = append(values, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", env, err)
+	}
+
+	return values, nil
+}
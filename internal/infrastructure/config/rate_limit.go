@@ -0,0 +1,58 @@
+package config
+
+import (
+	"ddd-user-service/internal/application/ratelimit"
+	"fmt"
+	"strings"
+)
+
+type RateLimitConfig struct {
+	Enabled bool
+	PerIP   ratelimit.Policy
+	PerKey  ratelimit.Policy
+	PerUser ratelimit.Policy
+	// Routes holds stricter per-IP policies for sensitive endpoints, keyed by
+	// route name ("register", "login", "password_reset").
+	Routes map[string]ratelimit.Policy
+}
+
+func NewRateLimitConfig() (*RateLimitConfig, error) {
+	cfg := &RateLimitConfig{
+		Enabled: getEnv("RATE_LIMIT_ENABLED", "true") != "false",
+		Routes:  make(map[string]ratelimit.Policy),
+	}
+
+	policies := []struct {
+		target   *ratelimit.Policy
+		name     string
+		env      string
+		fallback string
+	}{
+		{&cfg.PerIP, "ip", "RATE_LIMIT_IP", "120/1m"},
+		{&cfg.PerKey, "api_key", "RATE_LIMIT_API_KEY", "1200/1m"},
+		{&cfg.PerUser, "user", "RATE_LIMIT_USER", "600/1m"},
+	}
+	for _, p := range policies {
+		policy, err := ratelimit.ParsePolicy(p.name, getEnv(p.env, p.fallback))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.env, err)
+		}
+		*p.target = policy
+	}
+
+	routes := map[string]string{
+		"register":       "5/1m",
+		"login":          "10/1m",
+		"password_reset": "5/15m",
+	}
+	for name, fallback := range routes {
+		env := "RATE_LIMIT_" + strings.ToUpper(name)
+		policy, err := ratelimit.ParsePolicy(name, getEnv(env, fallback))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", env, err)
+		}
+		cfg.Routes[name] = policy
+	}
+
+	return cfg, nil
+}
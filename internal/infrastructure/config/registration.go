@@ -0,0 +1,28 @@
+package config
+
+import "fmt"
+
+const (
+	// RegistrationModeExplicit reports duplicate emails and usernames as 409.
+	RegistrationModeExplicit = "explicit"
+	// RegistrationModePrivate always answers 202 and tells the address owner
+	// by email what happened, so registration cannot be used to probe
+	// which accounts exist.
+	RegistrationModePrivate = "private"
+)
+
+type RegistrationConfig struct {
+	Mode string
+}
+
+func NewRegistrationConfig() (*RegistrationConfig, error) {
+	cfg := &RegistrationConfig{
+		Mode: getEnv("REGISTRATION_MODE", RegistrationModeExplicit),
+	}
+
+	if cfg.Mode != RegistrationModeExplicit && cfg.Mode != RegistrationModePrivate {
+		return nil, fmt.Errorf("REGISTRATION_MODE: unknown mode %q", cfg.Mode)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type SessionConfig struct {
+	IdleTimeout     time.Duration
+	AbsoluteTimeout time.Duration
+}
+
+func NewSessionConfig() *SessionConfig {
+	cfg := &SessionConfig{
+		IdleTimeout:     30 * time.Minute,
+		AbsoluteTimeout: 12 * time.Hour,
+	}
+
+	if timeout, err := time.ParseDuration(os.Getenv("SESSION_IDLE_TIMEOUT")); err == nil && timeout > 0 {
+		cfg.IdleTimeout = timeout
+	}
+	if timeout, err := time.ParseDuration(os.Getenv("SESSION_ABSOLUTE_TIMEOUT")); err == nil && timeout > 0 {
+		cfg.AbsoluteTimeout = timeout
+	}
+
+	return cfg
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"ddd-user-service/internal/domain"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type TenantConfig struct {
+	// Default is the tenant of requests that name none. Empty makes the
+	// tenant mandatory.
+	Default domain.TenantID
+	// BaseDomain lets tenants be addressed by subdomain: with
+	// "users.example.com", "acme.users.example.com" is tenant "acme".
+	BaseDomain string
+	// Allowed lists the tenants this deployment serves. Empty serves only
+	// Default.
+	Allowed []domain.TenantID
+}
+
+func NewTenantConfig() (*TenantConfig, error) {
+	cfg := &TenantConfig{
+		BaseDomain: strings.ToLower(strings.TrimPrefix(strings.TrimSpace(os.Getenv("TENANT_BASE_DOMAIN")), ".")),
+	}
+
+	raw, set := os.LookupEnv("TENANT_DEFAULT")
+	if !set {
+		raw = domain.DefaultTenantID.String()
+	}
+	if raw != "" {
+		tenant, err := domain.ParseTenantID(raw)
+		if err != nil {
+			return nil, fmt.Errorf("TENANT_DEFAULT: invalid tenant ID %q", raw)
+		}
+		cfg.Default = tenant
+	}
+
+	for _, raw := range strings.Split(os.Getenv("TENANTS"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		tenant, err := domain.ParseTenantID(raw)
+		if err != nil {
+			return nil, fmt.Errorf("TENANTS: invalid tenant ID %q", raw)
+		}
+		cfg.Allowed = append(cfg.Allowed, tenant)
+	}
+	if cfg.Default == "" && len(cfg.Allowed) == 0 {
+		return nil, fmt.Errorf("TENANTS: must list the tenants served when TENANT_DEFAULT is empty")
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+const (
+	TracingExporterNone   = "none"
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+)
+
+type TracingConfig struct {
+	ServiceName  string
+	Exporter     string
+	OTLPEndpoint string
+	OTLPInsecure bool
+	FilePath     string
+	SampleRatio  float64
+}
+
+func NewTracingConfig() *TracingConfig {
+	cfg := &TracingConfig{
+		ServiceName:  getEnv("OTEL_SERVICE_NAME", "ddd-user-service"),
+		Exporter:     getEnv("OTEL_TRACES_EXPORTER", TracingExporterNone),
+		OTLPEndpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4318"),
+		OTLPInsecure: getEnv("OTEL_EXPORTER_OTLP_INSECURE", "true") == "true",
+		FilePath:     getEnv("OTEL_TRACES_FILE", "traces.json"),
+		SampleRatio:  1,
+	}
+
+	if ratio, err := strconv.ParseFloat(os.Getenv("OTEL_TRACES_SAMPLER_ARG"), 64); err == nil {
+		cfg.SampleRatio = ratio
+	}
+
+	return cfg
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
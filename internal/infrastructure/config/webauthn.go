@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+type WebAuthnConfig struct {
+	// RPID is the relying party ID: the site's registrable domain. Passkeys
+	// are bound to it, so changing it orphans every registered credential.
+	RPID        string
+	RPName      string
+	Origins     []string
+	CeremonyTTL time.Duration
+}
+
+func NewWebAuthnConfig() *WebAuthnConfig {
+	cfg := &WebAuthnConfig{
+		RPID:        getEnv("WEBAUTHN_RP_ID", "localhost"),
+		RPName:      getEnv("WEBAUTHN_RP_NAME", "DDD User Service"),
+		CeremonyTTL: 5 * time.Minute,
+	}
+
+	for _, origin := range strings.Split(getEnv("WEBAUTHN_RP_ORIGINS", "http://localhost:8080"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			cfg.Origins = append(cfg.Origins, origin)
+		}
+	}
+
+	if ttl, err := time.ParseDuration(os.Getenv("WEBAUTHN_CEREMONY_TTL")); err == nil && ttl > 0 {
+		cfg.CeremonyTTL = ttl
+	}
+
+	return cfg
+}
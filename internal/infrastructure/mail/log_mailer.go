@@ -0,0 +1,28 @@
+package mail
+
+import (
+	"context"
+	"ddd-user-service/internal/application/mail"
+	"ddd-user-service/internal/logging"
+	"log/slog"
+)
+
+// LogMailer writes messages to the log instead of sending them. It is meant
+// for local development.
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg mail.Message) error {
+	logger := logging.FromContext(ctx).With(slog.String(logging.KeyComponent, "mail"))
+	logger.LogAttrs(ctx, slog.LevelInfo, "mail sent",
+		slog.String("email", msg.To),
+		slog.String("subject", msg.Subject),
+	)
+	// The body may carry personal data or tokens, so it is only logged at
+	// debug level.
+	logger.LogAttrs(ctx, slog.LevelDebug, "mail body", slog.String("body", msg.Text))
+	return nil
+}
@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"context"
+	"ddd-user-service/internal/application/mail"
+	"sync"
+)
+
+// MemoryMailer records messages instead of sending them, for tests.
+type MemoryMailer struct {
+	messages []mail.Message
+	mutex    sync.Mutex
+	// Err, when set, is returned from Send instead of recording the message.
+	Err error
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func (m *MemoryMailer) Sent() []mail.Message {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return append([]mail.Message(nil), m.messages...)
+}
+
+func (m *MemoryMailer) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.messages = nil
+}
@@ -0,0 +1,103 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"ddd-user-service/internal/application/mail"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// RequireTLS fails delivery when the server does not offer STARTTLS.
+	RequireTLS bool
+	Timeout    time.Duration
+}
+
+// SMTPMailer delivers messages over SMTP, upgrading to TLS with STARTTLS
+// when the server offers it. 5xx replies are reported as permanent failures.
+type SMTPMailer struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &SMTPMailer{cfg: cfg}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg mail.Message) error {
+	body, err := buildMIME(m.cfg.From, msg, time.Now())
+	if err != nil {
+		return &mail.PermanentError{Err: err}
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	dialer := net.Dialer{Timeout: m.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(m.cfg.Timeout))
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return classifySMTPError(err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	} else if m.cfg.RequireTLS {
+		return errors.New("SMTP server does not support STARTTLS")
+	}
+
+	if m.cfg.Username != "" {
+		auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return classifySMTPError(err)
+		}
+	}
+
+	if err := client.Mail(addressOnly(m.cfg.From)); err != nil {
+		return classifySMTPError(err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return classifySMTPError(err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return classifySMTPError(err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return classifySMTPError(err)
+	}
+	if err := w.Close(); err != nil {
+		return classifySMTPError(err)
+	}
+
+	return client.Quit()
+}
+
+func classifySMTPError(err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 && protoErr.Code < 600 {
+		return &mail.PermanentError{Err: err}
+	}
+	return err
+}
@@ -0,0 +1,19 @@
+package mail
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Templates returns the built-in mail templates, laid out as
+// <locale>/<name>.{subject.txt,txt,html}.
+func Templates() fs.FS {
+	sub, err := fs.Sub(templateFS, "templates")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
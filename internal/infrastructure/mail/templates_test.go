@@ -0,0 +1,64 @@
+package mail
+
+import (
+	"context"
+	"ddd-user-service/internal/application/mail"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+// TestBuiltInTemplatesRender renders every built-in template in every locale
+// through the templating mailer into a memory transport.
+func TestBuiltInTemplatesRender(t *testing.T) {
+	templates := Templates()
+	names, err := fs.Glob(templates, "*/*.subject.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) == 0 {
+		t.Fatal("no templates found")
+	}
+
+	data := map[string]any{
+		"Name":            "Jane Doe",
+		"Email":           "jane@example.com",
+		"Username":        "jane",
+		"Attribute":       "employee_no",
+		"Organization":    "Acme",
+		"ExistingAccount": true,
+		"Token":           "tok-123",
+		"ExpiresIn":       "24h0m0s",
+	}
+
+	for _, name := range names {
+		locale, file, _ := strings.Cut(name, "/")
+		template := strings.TrimSuffix(file, ".subject.txt")
+		t.Run(locale+"/"+template, func(t *testing.T) {
+			transport := NewMemoryMailer()
+			mailer := mail.NewTemplatingMailer(mail.NewTemplateRenderer(templates, "en"), transport)
+
+			msg := mail.Message{To: "jane@example.com", Template: template, Locale: locale, Data: data}
+			if err := mailer.Send(context.Background(), msg); err != nil {
+				t.Fatal(err)
+			}
+
+			sent := transport.Sent()
+			if len(sent) != 1 {
+				t.Fatalf("sent %d messages, want 1", len(sent))
+			}
+			got := sent[0]
+			if got.Locale != locale {
+				t.Errorf("locale = %q, want %q", got.Locale, locale)
+			}
+			if got.Subject == "" || got.Text == "" {
+				t.Errorf("subject %q or text %q is empty", got.Subject, got.Text)
+			}
+			for _, part := range []string{got.Subject, got.Text, got.HTML} {
+				if strings.Contains(part, "<no value>") {
+					t.Errorf("template uses data it was not given: %q", part)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/application/audit"
+	"ddd-user-service/internal/domain"
+	"fmt"
+	"hash/maphash"
+	"maps"
+	"sync"
+)
+
+// userLockStripes bounds the per-user locks: users that hash to the same
+// stripe share one.
+const userLockStripes = 64
+
+// AuditingUserRepository decorates a UserRepository so every change it
+// stores is recorded in the audit log, with the fields that changed.
+// Reads pass straight through. The entry is written after the change, so a
+// failure to record it is returned even though the change was stored.
+//
+// Each change reads the stored user, writes and records while holding the
+// user's lock, so the recorded before state is the one the write replaced.
+// The locks are per process; like the recorder's, they assume one writer
+// per tenant at a time for exact diffs.
+type AuditingUserRepository struct {
+	domain.UserRepository
+	recorder *audit.Recorder
+	seed     maphash.Seed
+	locks    map[domain.TenantID]*tenantLocks
+	mutex    sync.Mutex
+}
+
+// tenantLocks holds a tenant's per-user locks. Changes to one user hold all
+// for reading; changes across the tenant hold it for writing.
+type tenantLocks struct {
+	all   sync.RWMutex
+	users [userLockStripes]sync.Mutex
+}
+
+func NewAuditingUserRepository(next domain.UserRepository, recorder *audit.Recorder) *AuditingUserRepository {
+	return &AuditingUserRepository{
+		UserRepository: next,
+		recorder:       recorder,
+		seed:           maphash.MakeSeed(),
+		locks:          make(map[domain.TenantID]*tenantLocks),
+	}
+}
+
+func (r *AuditingUserRepository) Save(ctx context.Context, user *domain.User) error {
+	unlock := r.lockUser(user.TenantID, user.ID)
+	defer unlock()
+
+	if err := r.UserRepository.Save(ctx, user); err != nil {
+		return err
+	}
+	return r.record(ctx, user.TenantID, user.ID, domain.AuditUserCreated, nil, user)
+}
+
+// Update records suspending and reinstating as their own actions.
+func (r *AuditingUserRepository) Update(ctx context.Context, user *domain.User) error {
+	unlock := r.lockUser(user.TenantID, user.ID)
+	defer unlock()
+
+	before, err := r.UserRepository.GetByID(ctx, user.TenantID, user.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.UserRepository.Update(ctx, user); err != nil {
+		return err
+	}
+
+	action := domain.AuditUserUpdated
+	switch {
+	case user.Suspended() && !before.Suspended():
+		action = domain.AuditUserSuspended
+	case !user.Suspended() && before.Suspended():
+		action = domain.AuditUserReinstated
+	}
+	return r.record(ctx, user.TenantID, user.ID, action, before, user)
+}
+
+func (r *AuditingUserRepository) Delete(ctx context.Context, tenant domain.TenantID, id domain.UserID) error {
+	unlock := r.lockUser(tenant, id)
+	defer unlock()
+
+	before, err := r.UserRepository.GetByID(ctx, tenant, id)
+	if err != nil {
+		return err
+	}
+	if err := r.UserRepository.Delete(ctx, tenant, id); err != nil {
+		return err
+	}
+	return r.record(ctx, tenant, id, domain.AuditUserDeleted, before, nil)
+}
+
+// UnsetAttribute records an update for each user that held the attribute.
+func (r *AuditingUserRepository) UnsetAttribute(ctx context.Context, tenant domain.TenantID, name string) error {
+	locks := r.tenantLocks(tenant)
+	locks.all.Lock()
+	defer locks.all.Unlock()
+
+	users, err := r.UserRepository.GetAll(ctx, tenant, domain.UserFilter{})
+	if err != nil {
+		return err
+	}
+	if err := r.UserRepository.UnsetAttribute(ctx, tenant, name); err != nil {
+		return err
+	}
+
+	for _, before := range users {
+		if _, ok := before.Attributes[name]; !ok {
+			continue
+		}
+		after := *before
+		after.Attributes = maps.Clone(before.Attributes)
+		delete(after.Attributes, name)
+		if err := r.record(ctx, tenant, before.ID, domain.AuditUserUpdated, before, &after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *AuditingUserRepository) record(ctx context.Context, tenant domain.TenantID, id domain.UserID, action domain.AuditAction, before, after *domain.User) error {
+	if err := r.recorder.Record(ctx, tenant, id, action, domain.DiffUser(before, after)); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditingUserRepository) lockUser(tenant domain.TenantID, id domain.UserID) (unlock func()) {
+	locks := r.tenantLocks(tenant)
+	user := &locks.users[maphash.String(r.seed, id.String())%userLockStripes]
+	locks.all.RLock()
+	user.Lock()
+	return func() {
+		user.Unlock()
+		locks.all.RUnlock()
+	}
+}
+
+func (r *AuditingUserRepository) tenantLocks(tenant domain.TenantID) *tenantLocks {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	locks, ok := r.locks[tenant]
+	if !ok {
+		locks = &tenantLocks{}
+		r.locks[tenant] = locks
+	}
+	return locks
+}
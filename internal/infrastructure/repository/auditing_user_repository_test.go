@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/application/audit"
+	"ddd-user-service/internal/domain"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowUpdates widens the gap between reading the before state and writing,
+// where concurrent updates would interleave.
+type slowUpdates struct {
+	domain.UserRepository
+}
+
+func (r slowUpdates) Update(ctx context.Context, user *domain.User) error {
+	time.Sleep(time.Millisecond)
+	return r.UserRepository.Update(ctx, user)
+}
+
+func TestAuditedUpdatesRecordWhatTheyReplaced(t *testing.T) {
+	ctx := context.Background()
+	entries := NewMemoryAuditRepository()
+	users := NewAuditingUserRepository(slowUpdates{NewMemoryUserRepository()}, audit.NewRecorder(entries, []byte("key")))
+
+	user, err := domain.NewUser(domain.DefaultTenantID, "Name 0", "jane@example.com", "jane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Save(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			update := *user
+			update.Name = fmt.Sprintf("Name %d", i)
+			if err := users.Update(ctx, &update); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	chain, err := entries.Chain(ctx, domain.DefaultTenantID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := `"Name 0"`
+	for _, entry := range chain[1:] {
+		for _, change := range entry.Changes {
+			if change.Field != "name" {
+				continue
+			}
+			if string(change.Before) != name {
+				t.Fatalf("entry %d: before = %s, want %s", entry.Sequence, change.Before, name)
+			}
+			name = string(change.After)
+		}
+	}
+
+	stored, err := users.GetByID(ctx, domain.DefaultTenantID, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := json.Marshal(stored.Name); string(want) != name {
+		t.Fatalf("last recorded name = %s, stored %s", name, want)
+	}
+}
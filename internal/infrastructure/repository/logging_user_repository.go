@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/logging"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// LoggingUserRepository decorates a UserRepository with one debug line per
+// call. Unexpected errors are logged at error level.
+type LoggingUserRepository struct {
+	next domain.UserRepository
+}
+
+func NewLoggingUserRepository(next domain.UserRepository) *LoggingUserRepository {
+	return &LoggingUserRepository{next: next}
+}
+
+func (r *LoggingUserRepository) log(ctx context.Context, op string, start time.Time, err error, attrs ...slog.Attr) {
+	logger := logging.FromContext(ctx).With(slog.String(logging.KeyComponent, "repository"))
+
+	attrs = append(attrs,
+		slog.String(logging.KeyOperation, op),
+		slog.Int64(logging.KeyDuration, time.Since(start).Milliseconds()),
+	)
+
+	level := slog.LevelDebug
+	if err != nil {
+		attrs = append(attrs, slog.String(logging.KeyError, err.Error()))
+		if !errors.Is(err, domain.ErrUserNotFound) &&
+			!errors.Is(err, domain.ErrEmailExists) &&
+			!errors.Is(err, domain.ErrUsernameExists) {
+			level = slog.LevelError
+		}
+	}
+
+	logger.LogAttrs(ctx, level, "repository call", attrs...)
+}
+
+func (r *LoggingUserRepository) Save(ctx context.Context, user *domain.User) (err error) {
+	defer func(start time.Time) {
+		r.log(ctx, "Save", start, err, slog.String(logging.KeyUserID, user.ID.String()))
+	}(time.Now())
+
+	return r.next.Save(ctx, user)
+}
+
+func (r *LoggingUserRepository) GetByID(ctx context.Context, tenant domain.TenantID, id domain.UserID) (_ *domain.User, err error) {
+	defer func(start time.Time) {
+		r.log(ctx, "GetByID", start, err, slog.String(logging.KeyUserID, id.String()))
+	}(time.Now())
+
+	return r.next.GetByID(ctx, tenant, id)
+}
+
+func (r *LoggingUserRepository) GetByEmail(ctx context.Context, tenant domain.TenantID, email domain.Email) (_ *domain.User, err error) {
+	defer func(start time.Time) {
+		r.log(ctx, "GetByEmail", start, err, slog.String("email", email.String()))
+	}(time.Now())
+
+	return r.next.GetByEmail(ctx, tenant, email)
+}
+
+func (r *LoggingUserRepository) GetByUsername(ctx context.Context, tenant domain.TenantID, username domain.Username) (_ *domain.User, err error) {
+	defer func(start time.Time) {
+		r.log(ctx, "GetByUsername", start, err, slog.String("username", username.String()))
+	}(time.Now())
+
+	return r.next.GetByUsername(ctx, tenant, username)
+}
+
+func (r *LoggingUserRepository) GetAll(ctx context.Context, tenant domain.TenantID, filter domain.UserFilter) (users []*domain.User, err error) {
+	defer func(start time.Time) {
+		r.log(ctx, "GetAll", start, err, slog.Int("count", len(users)))
+	}(time.Now())
+
+	return r.next.GetAll(ctx, tenant, filter)
+}
+
+func (r *LoggingUserRepository) Update(ctx context.Context, user *domain.User) (err error) {
+	defer func(start time.Time) {
+		r.log(ctx, "Update", start, err, slog.String(logging.KeyUserID, user.ID.String()))
+	}(time.Now())
+
+	return r.next.Update(ctx, user)
+}
+
+func (r *LoggingUserRepository) Delete(ctx context.Context, tenant domain.TenantID, id domain.UserID) (err error) {
+	defer func(start time.Time) {
+		r.log(ctx, "Delete", start, err, slog.String(logging.KeyUserID, id.String()))
+	}(time.Now())
+
+	return r.next.Delete(ctx, tenant, id)
+}
+
+func (r *LoggingUserRepository) ExistsByEmail(ctx context.Context, tenant domain.TenantID, email domain.Email) (exists bool, err error) {
+	defer func(start time.Time) {
+		r.log(ctx, "ExistsByEmail", start, err, slog.String("email", email.String()), slog.Bool("exists", exists))
+	}(time.Now())
+
+	return r.next.ExistsByEmail(ctx, tenant, email)
+}
+
+func (r *LoggingUserRepository) ExistsByUsername(ctx context.Context, tenant domain.TenantID, username domain.Username) (exists bool, err error) {
+	defer func(start time.Time) {
+		r.log(ctx, "ExistsByUsername", start, err, slog.String("username", username.String()), slog.Bool("exists", exists))
+	}(time.Now())
+
+	return r.next.ExistsByUsername(ctx, tenant, username)
+}
+
+func (r *LoggingUserRepository) FindByUsernameSkeleton(ctx context.Context, tenant domain.TenantID, skeleton string) (users []*domain.User, err error) {
+	defer func(start time.Time) {
+		r.log(ctx, "FindByUsernameSkeleton", start, err, slog.String("skeleton", skeleton), slog.Int("count", len(users)))
+	}(time.Now())
+
+	return r.next.FindByUsernameSkeleton(ctx, tenant, skeleton)
+}
+
+func (r *LoggingUserRepository) UnsetAttribute(ctx context.Context, tenant domain.TenantID, name string) (err error) {
+	defer func(start time.Time) {
+		r.log(ctx, "UnsetAttribute", start, err, slog.String("attribute", name))
+	}(time.Now())
+
+	return r.next.UnsetAttribute(ctx, tenant, name)
+}
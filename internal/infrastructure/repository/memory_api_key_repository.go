@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+type MemoryAPIKeyRepository struct {
+	keys  map[domain.APIKeyID]*domain.APIKey
+	mutex sync.RWMutex
+}
+
+func NewMemoryAPIKeyRepository() *MemoryAPIKeyRepository {
+	return &MemoryAPIKeyRepository{
+		keys: make(map[domain.APIKeyID]*domain.APIKey),
+	}
+}
+
+func (r *MemoryAPIKeyRepository) Save(ctx context.Context, key *domain.APIKey) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.keys[key.ID] = cloneAPIKey(key)
+	return nil
+}
+
+func (r *MemoryAPIKeyRepository) GetBySecretHash(ctx context.Context, secretHash string) (*domain.APIKey, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, key := range r.keys {
+		if key.SecretHash == secretHash {
+			return cloneAPIKey(key), nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *MemoryAPIKeyRepository) Get(ctx context.Context, ownerID domain.UserID, id domain.APIKeyID) (*domain.APIKey, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	key, exists := r.keys[id]
+	if !exists || key.OwnerID != ownerID {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return cloneAPIKey(key), nil
+}
+
+func (r *MemoryAPIKeyRepository) ListByOwner(ctx context.Context, ownerID domain.UserID) ([]*domain.APIKey, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var keys []*domain.APIKey
+	for _, key := range r.keys {
+		if key.OwnerID == ownerID {
+			keys = append(keys, cloneAPIKey(key))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+func (r *MemoryAPIKeyRepository) Update(ctx context.Context, key *domain.APIKey) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.keys[key.ID]; !exists {
+		return domain.ErrAPIKeyNotFound
+	}
+	r.keys[key.ID] = cloneAPIKey(key)
+	return nil
+}
+
+func (r *MemoryAPIKeyRepository) Touch(ctx context.Context, id domain.APIKeyID, at time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key, exists := r.keys[id]
+	if !exists {
+		return domain.ErrAPIKeyNotFound
+	}
+	key.LastUsedAt = &at
+	return nil
+}
+
+func (r *MemoryAPIKeyRepository) Delete(ctx context.Context, ownerID domain.UserID, id domain.APIKeyID) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key, exists := r.keys[id]
+	if !exists || key.OwnerID != ownerID {
+		return domain.ErrAPIKeyNotFound
+	}
+	delete(r.keys, id)
+	return nil
+}
+
+func cloneAPIKey(key *domain.APIKey) *domain.APIKey {
+	clone := *key
+	clone.Scopes = slices.Clone(key.Scopes)
+	return &clone
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"slices"
+	"sort"
+	"sync"
+)
+
+type attributeKey struct {
+	tenant domain.TenantID
+	name   string
+}
+
+type MemoryAttributeSchemaRepository struct {
+	schemas map[attributeKey]*domain.AttributeSchema
+	mutex   sync.RWMutex
+}
+
+func NewMemoryAttributeSchemaRepository() *MemoryAttributeSchemaRepository {
+	return &MemoryAttributeSchemaRepository{
+		schemas: make(map[attributeKey]*domain.AttributeSchema),
+	}
+}
+
+func (r *MemoryAttributeSchemaRepository) Save(ctx context.Context, schema *domain.AttributeSchema) error {
+	if schema.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := attributeKey{schema.TenantID, schema.Name}
+	if _, exists := r.schemas[key]; exists {
+		return domain.ErrAttributeExists
+	}
+	r.schemas[key] = cloneAttributeSchema(schema)
+	return nil
+}
+
+func (r *MemoryAttributeSchemaRepository) Get(ctx context.Context, tenant domain.TenantID, name string) (*domain.AttributeSchema, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	schema, exists := r.schemas[attributeKey{tenant, name}]
+	if !exists {
+		return nil, domain.ErrAttributeNotFound
+	}
+	return cloneAttributeSchema(schema), nil
+}
+
+func (r *MemoryAttributeSchemaRepository) List(ctx context.Context, tenant domain.TenantID) ([]*domain.AttributeSchema, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var schemas []*domain.AttributeSchema
+	for key, schema := range r.schemas {
+		if key.tenant == tenant {
+			schemas = append(schemas, cloneAttributeSchema(schema))
+		}
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Name < schemas[j].Name
+	})
+	return schemas, nil
+}
+
+func (r *MemoryAttributeSchemaRepository) Delete(ctx context.Context, tenant domain.TenantID, name string) error {
+	if tenant == "" {
+		return domain.ErrTenantRequired
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := attributeKey{tenant, name}
+	if _, exists := r.schemas[key]; !exists {
+		return domain.ErrAttributeNotFound
+	}
+	delete(r.schemas, key)
+	return nil
+}
+
+func cloneAttributeSchema(schema *domain.AttributeSchema) *domain.AttributeSchema {
+	schemaCopy := *schema
+	schemaCopy.Enum = slices.Clone(schema.Enum)
+	return &schemaCopy
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"slices"
+	"sync"
+)
+
+// MemoryAuditRepository keeps each tenant's entries in sequence order.
+type MemoryAuditRepository struct {
+	entries map[domain.TenantID][]*domain.AuditEntry
+	mutex   sync.RWMutex
+}
+
+func NewMemoryAuditRepository() *MemoryAuditRepository {
+	return &MemoryAuditRepository{
+		entries: make(map[domain.TenantID][]*domain.AuditEntry),
+	}
+}
+
+func (r *MemoryAuditRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	if entry.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	chain := r.entries[entry.TenantID]
+	if entry.Sequence != int64(len(chain))+1 {
+		return domain.ErrAuditSequenceTaken
+	}
+	r.entries[entry.TenantID] = append(chain, cloneAuditEntry(entry))
+	return nil
+}
+
+func (r *MemoryAuditRepository) Last(ctx context.Context, tenant domain.TenantID) (*domain.AuditEntry, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	chain := r.entries[tenant]
+	if len(chain) == 0 {
+		return nil, nil
+	}
+	return cloneAuditEntry(chain[len(chain)-1]), nil
+}
+
+func (r *MemoryAuditRepository) List(ctx context.Context, tenant domain.TenantID, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var entries []*domain.AuditEntry
+	for _, entry := range slices.Backward(r.entries[tenant]) {
+		if filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+		if (filter.Before > 0 && entry.Sequence >= filter.Before) || (filter.UserID != "" && entry.UserID != filter.UserID) {
+			continue
+		}
+		entries = append(entries, cloneAuditEntry(entry))
+	}
+	return entries, nil
+}
+
+func (r *MemoryAuditRepository) Chain(ctx context.Context, tenant domain.TenantID) ([]*domain.AuditEntry, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entries := make([]*domain.AuditEntry, len(r.entries[tenant]))
+	for i, entry := range r.entries[tenant] {
+		entries[i] = cloneAuditEntry(entry)
+	}
+	return entries, nil
+}
+
+func cloneAuditEntry(entry *domain.AuditEntry) *domain.AuditEntry {
+	entryCopy := *entry
+	entryCopy.Changes = slices.Clone(entry.Changes)
+	return &entryCopy
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"container/heap"
+	"context"
+	"ddd-user-service/internal/application/idempotency"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MemoryIdempotencyStore keeps records in a map and their expiry times in a
+// min-heap, so Reserve only looks at the records that have expired.
+type MemoryIdempotencyStore struct {
+	records map[string]*idempotency.Record
+	expiry  idempotencyExpiry
+	mutex   sync.Mutex
+	now     func() time.Time
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		records: make(map[string]*idempotency.Record),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(ctx context.Context, rec *idempotency.Record) (*idempotency.Record, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.evictExpired()
+
+	if existing, exists := s.records[rec.Key]; exists {
+		recordCopy := *existing
+		return &recordCopy, nil
+	}
+
+	recordCopy := *rec
+	s.records[rec.Key] = &recordCopy
+	heap.Push(&s.expiry, idempotencyDeadline{key: rec.Key, at: rec.ExpiresAt})
+	return nil, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(ctx context.Context, key string, statusCode int, header http.Header, body []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rec, exists := s.records[key]
+	if !exists {
+		return idempotency.ErrNotFound
+	}
+
+	rec.StatusCode = statusCode
+	rec.Header = header.Clone()
+	rec.Body = append([]byte(nil), body...)
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// evictExpired pops deadlines that have passed. A deadline whose key was
+// released, or reserved again since, no longer matches the stored record and
+// is dropped without touching it.
+func (s *MemoryIdempotencyStore) evictExpired() {
+	now := s.now()
+	for len(s.expiry) > 0 && !s.expiry[0].at.After(now) {
+		deadline := heap.Pop(&s.expiry).(idempotencyDeadline)
+		if rec, exists := s.records[deadline.key]; exists && rec.ExpiresAt.Equal(deadline.at) {
+			delete(s.records, deadline.key)
+		}
+	}
+}
+
+type idempotencyDeadline struct {
+	key string
+	at  time.Time
+}
+
+// idempotencyExpiry implements heap.Interface, earliest deadline first.
+type idempotencyExpiry []idempotencyDeadline
+
+func (h idempotencyExpiry) Len() int           { return len(h) }
+func (h idempotencyExpiry) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h idempotencyExpiry) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *idempotencyExpiry) Push(x any)        { *h = append(*h, x.(idempotencyDeadline)) }
+
+func (h *idempotencyExpiry) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/application/idempotency"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyStoreEvictsExpiredRecords(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	store := NewMemoryIdempotencyStore()
+	store.now = func() time.Time { return now }
+	reserve := func(key string, ttl time.Duration) *idempotency.Record {
+		t.Helper()
+		existing, err := store.Reserve(ctx, &idempotency.Record{Key: key, CreatedAt: now, ExpiresAt: now.Add(ttl)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return existing
+	}
+
+	for i := range 50 {
+		reserve(fmt.Sprintf("short-%d", i), time.Minute)
+	}
+	reserve("long", time.Hour)
+	reserve("released", time.Minute)
+	if err := store.Release(ctx, "released"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(30 * time.Second)
+	reserve("released", time.Hour)
+
+	now = now.Add(time.Minute)
+	if existing := reserve("fresh", time.Hour); existing != nil {
+		t.Fatalf("fresh key found an existing record: %+v", existing)
+	}
+	if len(store.records) != 3 {
+		t.Fatalf("%d records kept, want long, released and fresh", len(store.records))
+	}
+	if len(store.expiry) != len(store.records) {
+		t.Fatalf("%d deadlines for %d records", len(store.expiry), len(store.records))
+	}
+	if existing := reserve("released", time.Hour); existing == nil {
+		t.Fatal("re-reserved key was evicted by its released deadline")
+	}
+	if existing := reserve("short-0", time.Hour); existing != nil {
+		t.Fatalf("expired key still reserved: %+v", existing)
+	}
+}
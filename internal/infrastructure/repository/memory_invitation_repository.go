@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"sort"
+	"sync"
+)
+
+type MemoryInvitationRepository struct {
+	invitations map[domain.InvitationID]*domain.Invitation
+	mutex       sync.RWMutex
+}
+
+func NewMemoryInvitationRepository() *MemoryInvitationRepository {
+	return &MemoryInvitationRepository{
+		invitations: make(map[domain.InvitationID]*domain.Invitation),
+	}
+}
+
+func (r *MemoryInvitationRepository) Save(ctx context.Context, inv *domain.Invitation) error {
+	if inv.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.invitations[inv.ID] = cloneInvitation(inv)
+	return nil
+}
+
+func (r *MemoryInvitationRepository) Get(ctx context.Context, tenant domain.TenantID, id domain.InvitationID) (*domain.Invitation, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	inv, exists := r.invitations[id]
+	if !exists || inv.TenantID != tenant {
+		return nil, domain.ErrInvitationNotFound
+	}
+	return cloneInvitation(inv), nil
+}
+
+func (r *MemoryInvitationRepository) GetByTokenHash(ctx context.Context, tenant domain.TenantID, tokenHash string) (*domain.Invitation, error) {
+	return r.find(tenant, func(inv *domain.Invitation) bool {
+		return inv.TokenHash == tokenHash
+	})
+}
+
+func (r *MemoryInvitationRepository) FindOpen(ctx context.Context, tenant domain.TenantID, email domain.Email, orgID domain.OrganizationID) (*domain.Invitation, error) {
+	return r.find(tenant, func(inv *domain.Invitation) bool {
+		return inv.Email == email && inv.OrganizationID == orgID && inv.Open()
+	})
+}
+
+func (r *MemoryInvitationRepository) find(tenant domain.TenantID, match func(*domain.Invitation) bool) (*domain.Invitation, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, inv := range r.invitations {
+		if inv.TenantID == tenant && match(inv) {
+			return cloneInvitation(inv), nil
+		}
+	}
+	return nil, domain.ErrInvitationNotFound
+}
+
+func (r *MemoryInvitationRepository) List(ctx context.Context, tenant domain.TenantID) ([]*domain.Invitation, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var invitations []*domain.Invitation
+	for _, inv := range r.invitations {
+		if inv.TenantID == tenant {
+			invitations = append(invitations, cloneInvitation(inv))
+		}
+	}
+	sort.Slice(invitations, func(i, j int) bool {
+		return invitations[i].CreatedAt.After(invitations[j].CreatedAt)
+	})
+	return invitations, nil
+}
+
+func (r *MemoryInvitationRepository) Update(ctx context.Context, inv *domain.Invitation) error {
+	if inv.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, exists := r.invitations[inv.ID]
+	if !exists || stored.TenantID != inv.TenantID {
+		return domain.ErrInvitationNotFound
+	}
+	r.invitations[inv.ID] = cloneInvitation(inv)
+	return nil
+}
+
+func cloneInvitation(inv *domain.Invitation) *domain.Invitation {
+	invCopy := *inv
+	if inv.AcceptedAt != nil {
+		acceptedAt := *inv.AcceptedAt
+		invCopy.AcceptedAt = &acceptedAt
+	}
+	if inv.RevokedAt != nil {
+		revokedAt := *inv.RevokedAt
+		invCopy.RevokedAt = &revokedAt
+	}
+	return &invCopy
+}
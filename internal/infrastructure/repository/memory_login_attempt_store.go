@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/application/lockout"
+	"sync"
+	"time"
+)
+
+const loginAttemptSweepInterval = time.Minute
+
+type memoryLoginAttempts struct {
+	attempts  lockout.Attempts
+	expiresAt time.Time
+}
+
+type MemoryLoginAttemptStore struct {
+	entries   map[string]*memoryLoginAttempts
+	mutex     sync.Mutex
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{
+		entries: make(map[string]*memoryLoginAttempts),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryLoginAttemptStore) Get(ctx context.Context, key string) (lockout.Attempts, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry := s.entry(key)
+	if entry == nil {
+		return lockout.Attempts{}, nil
+	}
+	return entry.attempts, nil
+}
+
+func (s *MemoryLoginAttemptStore) RecordFailure(ctx context.Context, key string, at time.Time, window time.Duration) (lockout.Attempts, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sweep(s.now())
+	entry := s.entry(key)
+	if entry == nil {
+		entry = &memoryLoginAttempts{}
+		s.entries[key] = entry
+	}
+	if at.Sub(entry.attempts.LastFailureAt) > window {
+		entry.attempts.Failures = 0
+	}
+	entry.attempts.Failures++
+	entry.attempts.LastFailureAt = at
+	entry.expiresAt = laterOf(entry.attempts.LockedUntil, at.Add(window))
+	return entry.attempts, nil
+}
+
+func (s *MemoryLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sweep(s.now())
+	entry := s.entry(key)
+	if entry == nil {
+		entry = &memoryLoginAttempts{}
+		s.entries[key] = entry
+	}
+	entry.attempts.Failures = 0
+	entry.attempts.LockedUntil = until
+	entry.expiresAt = laterOf(entry.expiresAt, until)
+	return nil
+}
+
+func (s *MemoryLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// entry returns the live entry for key, dropping it once it has expired.
+func (s *MemoryLoginAttemptStore) entry(key string) *memoryLoginAttempts {
+	entry, exists := s.entries[key]
+	if !exists {
+		return nil
+	}
+	if !s.now().Before(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil
+	}
+	return entry
+}
+
+// sweep drops expired entries, which entry only removes for keys that are
+// looked up again.
+func (s *MemoryLoginAttemptStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < loginAttemptSweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLoginAttemptStoreSweepsExpiredEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	store := NewMemoryLoginAttemptStore()
+	store.now = func() time.Time { return now }
+
+	for i := range 100 {
+		if _, err := store.RecordFailure(ctx, fmt.Sprintf("login:%d", i), now, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Lock(ctx, "login:locked", now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := store.RecordFailure(ctx, "login:new", now, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.entries) != 2 {
+		t.Fatalf("%d entries kept, want the lock and the new failure", len(store.entries))
+	}
+	attempts, err := store.Get(ctx, "login:locked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !attempts.LockedUntil.After(now) {
+		t.Fatalf("lock was swept: %+v", attempts)
+	}
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"slices"
+	"sort"
+	"sync"
+)
+
+type MemoryOrganizationRepository struct {
+	orgs  map[domain.OrganizationID]*domain.Organization
+	mutex sync.RWMutex
+}
+
+func NewMemoryOrganizationRepository() *MemoryOrganizationRepository {
+	return &MemoryOrganizationRepository{
+		orgs: make(map[domain.OrganizationID]*domain.Organization),
+	}
+}
+
+func (r *MemoryOrganizationRepository) Save(ctx context.Context, org *domain.Organization) error {
+	if org.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.orgs[org.ID] = cloneOrganization(org)
+	return nil
+}
+
+func (r *MemoryOrganizationRepository) Get(ctx context.Context, tenant domain.TenantID, id domain.OrganizationID) (*domain.Organization, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	org, exists := r.orgs[id]
+	if !exists || org.TenantID != tenant {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	return cloneOrganization(org), nil
+}
+
+func (r *MemoryOrganizationRepository) ListByMember(ctx context.Context, tenant domain.TenantID, userID domain.UserID) ([]*domain.Organization, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var orgs []*domain.Organization
+	for _, org := range r.orgs {
+		if _, member := org.Membership(userID); member && org.TenantID == tenant {
+			orgs = append(orgs, cloneOrganization(org))
+		}
+	}
+	sort.Slice(orgs, func(i, j int) bool {
+		return orgs[i].CreatedAt.Before(orgs[j].CreatedAt)
+	})
+	return orgs, nil
+}
+
+func (r *MemoryOrganizationRepository) Update(ctx context.Context, org *domain.Organization) error {
+	if org.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, exists := r.orgs[org.ID]
+	if !exists || stored.TenantID != org.TenantID {
+		return domain.ErrOrganizationNotFound
+	}
+	if stored.Version != org.Version {
+		return domain.ErrOrganizationConflict
+	}
+	org.Version++
+	r.orgs[org.ID] = cloneOrganization(org)
+	return nil
+}
+
+func (r *MemoryOrganizationRepository) Delete(ctx context.Context, tenant domain.TenantID, id domain.OrganizationID) error {
+	if tenant == "" {
+		return domain.ErrTenantRequired
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	org, exists := r.orgs[id]
+	if !exists || org.TenantID != tenant {
+		return domain.ErrOrganizationNotFound
+	}
+	delete(r.orgs, id)
+	return nil
+}
+
+func cloneOrganization(org *domain.Organization) *domain.Organization {
+	orgCopy := *org
+	orgCopy.Members = make([]domain.Membership, len(org.Members))
+	for i, m := range org.Members {
+		if m.JoinedAt != nil {
+			joinedAt := *m.JoinedAt
+			m.JoinedAt = &joinedAt
+		}
+		orgCopy.Members[i] = m
+	}
+	orgCopy.Teams = make([]domain.Team, len(org.Teams))
+	for i, team := range org.Teams {
+		team.Members = slices.Clone(team.Members)
+		orgCopy.Teams[i] = team
+	}
+	return &orgCopy
+}
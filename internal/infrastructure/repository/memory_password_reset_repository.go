@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"sync"
+)
+
+type MemoryPasswordResetRepository struct {
+	tokens map[string]*domain.PasswordResetToken
+	mutex  sync.Mutex
+}
+
+func NewMemoryPasswordResetRepository() *MemoryPasswordResetRepository {
+	return &MemoryPasswordResetRepository{
+		tokens: make(map[string]*domain.PasswordResetToken),
+	}
+}
+
+func (r *MemoryPasswordResetRepository) Save(ctx context.Context, token *domain.PasswordResetToken) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	tokenCopy := *token
+	r.tokens[token.TokenHash] = &tokenCopy
+	return nil
+}
+
+func (r *MemoryPasswordResetRepository) Consume(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	token, exists := r.tokens[tokenHash]
+	if !exists {
+		return nil, domain.ErrResetTokenNotFound
+	}
+
+	delete(r.tokens, tokenHash)
+	return token, nil
+}
+
+func (r *MemoryPasswordResetRepository) DeleteByUser(ctx context.Context, userID domain.UserID) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for hash, token := range r.tokens {
+		if token.UserID == userID {
+			delete(r.tokens, hash)
+		}
+	}
+	return nil
+}
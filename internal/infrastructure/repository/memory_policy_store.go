@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/application/policy"
+	"ddd-user-service/internal/domain"
+	"sort"
+	"sync"
+)
+
+type policyKey struct {
+	tenant domain.TenantID
+	list   policy.List
+	value  string
+}
+
+type MemoryPolicyStore struct {
+	entries map[policyKey]policy.Entry
+	mutex   sync.RWMutex
+}
+
+func NewMemoryPolicyStore() *MemoryPolicyStore {
+	return &MemoryPolicyStore{
+		entries: make(map[policyKey]policy.Entry),
+	}
+}
+
+func (s *MemoryPolicyStore) List(ctx context.Context, tenant domain.TenantID, list policy.List) ([]policy.Entry, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var entries []policy.Entry
+	for key, entry := range s.entries {
+		if key.tenant == tenant && key.list == list {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Value < entries[j].Value
+	})
+	return entries, nil
+}
+
+func (s *MemoryPolicyStore) Contains(ctx context.Context, tenant domain.TenantID, list policy.List, value string) (bool, error) {
+	if tenant == "" {
+		return false, domain.ErrTenantRequired
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, exists := s.entries[policyKey{tenant, list, value}]
+	return exists, nil
+}
+
+func (s *MemoryPolicyStore) Add(ctx context.Context, tenant domain.TenantID, entry policy.Entry) (policy.Entry, bool, error) {
+	if tenant == "" {
+		return policy.Entry{}, false, domain.ErrTenantRequired
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := policyKey{tenant, entry.List, entry.Value}
+	if existing, exists := s.entries[key]; exists {
+		return existing, false, nil
+	}
+	s.entries[key] = entry
+	return entry, true, nil
+}
+
+func (s *MemoryPolicyStore) Remove(ctx context.Context, tenant domain.TenantID, list policy.List, value string) error {
+	if tenant == "" {
+		return domain.ErrTenantRequired
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := policyKey{tenant, list, value}
+	if _, exists := s.entries[key]; !exists {
+		return policy.ErrEntryNotFound
+	}
+	delete(s.entries, key)
+	return nil
+}
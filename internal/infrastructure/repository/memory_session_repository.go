@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+type MemorySessionRepository struct {
+	sessions map[domain.SessionID]*domain.Session
+	mutex    sync.RWMutex
+}
+
+func NewMemorySessionRepository() *MemorySessionRepository {
+	return &MemorySessionRepository{
+		sessions: make(map[domain.SessionID]*domain.Session),
+	}
+}
+
+func (r *MemorySessionRepository) Save(ctx context.Context, session *domain.Session) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	sessionCopy := *session
+	r.sessions[session.ID] = &sessionCopy
+	return nil
+}
+
+func (r *MemorySessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Session, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, session := range r.sessions {
+		if session.TokenHash == tokenHash {
+			sessionCopy := *session
+			return &sessionCopy, nil
+		}
+	}
+	return nil, domain.ErrSessionNotFound
+}
+
+func (r *MemorySessionRepository) ListByUser(ctx context.Context, userID domain.UserID) ([]*domain.Session, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var sessions []*domain.Session
+	for _, session := range r.sessions {
+		if session.UserID == userID {
+			sessionCopy := *session
+			sessions = append(sessions, &sessionCopy)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+func (r *MemorySessionRepository) Touch(ctx context.Context, id domain.SessionID, at time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return domain.ErrSessionNotFound
+	}
+	session.LastSeenAt = at
+	return nil
+}
+
+func (r *MemorySessionRepository) Delete(ctx context.Context, userID domain.UserID, id domain.SessionID) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists || session.UserID != userID {
+		return domain.ErrSessionNotFound
+	}
+	delete(r.sessions, id)
+	return nil
+}
+
+func (r *MemorySessionRepository) DeleteByUser(ctx context.Context, userID domain.UserID) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	deleted := 0
+	for id, session := range r.sessions {
+		if session.UserID == userID {
+			delete(r.sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/application/mail"
+	"strings"
+	"sync"
+)
+
+type MemorySuppressionList struct {
+	suppressions map[string]mail.Suppression
+	mutex        sync.RWMutex
+}
+
+func NewMemorySuppressionList() *MemorySuppressionList {
+	return &MemorySuppressionList{
+		suppressions: make(map[string]mail.Suppression),
+	}
+}
+
+func (l *MemorySuppressionList) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	_, exists := l.suppressions[strings.ToLower(strings.TrimSpace(email))]
+	return exists, nil
+}
+
+func (l *MemorySuppressionList) Suppress(ctx context.Context, suppression mail.Suppression) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	suppression.Email = strings.ToLower(strings.TrimSpace(suppression.Email))
+	l.suppressions[suppression.Email] = suppression
+	return nil
+}
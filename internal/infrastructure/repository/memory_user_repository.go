@@ -3,135 +3,286 @@ package repository
 import (
 	"context"
 	"ddd-user-service/internal/domain"
-	"strings"
+	"maps"
+	"slices"
 	"sync"
 )
 
+type tagKey struct {
+	tenant domain.TenantID
+	tag    string
+}
+
 type MemoryUserRepository struct {
 	users map[domain.UserID]*domain.User
+	// tags indexes the users carrying each tag, so tag filters need not
+	// scan every user.
+	tags  map[tagKey]map[domain.UserID]struct{}
 	mutex sync.RWMutex
 }
 
 func NewMemoryUserRepository() *MemoryUserRepository {
 	return &MemoryUserRepository{
 		users: make(map[domain.UserID]*domain.User),
+		tags:  make(map[tagKey]map[domain.UserID]struct{}),
 	}
 }
 
 func (r *MemoryUserRepository) Save(ctx context.Context, user *domain.User) error {
+	if user.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	r.users[user.ID] = user
+	r.store(user)
 	return nil
 }
 
-func (r *MemoryUserRepository) GetByID(ctx context.Context, id domain.UserID) (*domain.User, error) {
+func (r *MemoryUserRepository) GetByID(ctx context.Context, tenant domain.TenantID, id domain.UserID) (*domain.User, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
 	user, exists := r.users[id]
-	if !exists {
+	if !exists || user.TenantID != tenant {
 		return nil, domain.ErrUserNotFound
 	}
 
-	userCopy := *user
-	return &userCopy, nil
+	return cloneUser(user), nil
 }
 
-func (r *MemoryUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+func (r *MemoryUserRepository) GetByEmail(ctx context.Context, tenant domain.TenantID, email domain.Email) (*domain.User, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	email = strings.ToLower(strings.TrimSpace(email))
-	for _, user := range r.users {
-		if user.Email == email {
-			userCopy := *user
-			return &userCopy, nil
-		}
+	if user := r.find(tenant, func(u *domain.User) bool { return u.Email.Equal(email) }); user != nil {
+		return cloneUser(user), nil
 	}
 
 	return nil, domain.ErrUserNotFound
 }
 
-func (r *MemoryUserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+func (r *MemoryUserRepository) GetByUsername(ctx context.Context, tenant domain.TenantID, username domain.Username) (*domain.User, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	username = strings.ToLower(strings.TrimSpace(username))
-	for _, user := range r.users {
-		if user.Username == username {
-			userCopy := *user
-			return &userCopy, nil
-		}
+	if user := r.find(tenant, func(u *domain.User) bool { return u.Username == username }); user != nil {
+		return cloneUser(user), nil
 	}
 
 	return nil, domain.ErrUserNotFound
 }
 
-func (r *MemoryUserRepository) GetAll(ctx context.Context) ([]*domain.User, error) {
+func (r *MemoryUserRepository) GetAll(ctx context.Context, tenant domain.TenantID, filter domain.UserFilter) ([]*domain.User, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
 	users := make([]*domain.User, 0, len(r.users))
-	for _, user := range r.users {
-		userCopy := *user
-		users = append(users, &userCopy)
+	for _, user := range r.candidates(tenant, filter) {
+		if user.TenantID == tenant && filter.Matches(user) {
+			users = append(users, cloneUser(user))
+		}
 	}
 
 	return users, nil
 }
 
+// candidates narrows the users to check against filter through the tag
+// index: the union of the tags' users for TagMatchAny, or the users of the
+// rarest tag for TagMatchAll. The caller holds the lock.
+func (r *MemoryUserRepository) candidates(tenant domain.TenantID, filter domain.UserFilter) map[domain.UserID]*domain.User {
+	if len(filter.Tags) == 0 {
+		return r.users
+	}
+
+	var ids []map[domain.UserID]struct{}
+	for _, tag := range filter.Tags {
+		ids = append(ids, r.tags[tagKey{tenant, tag}])
+	}
+	if filter.TagMatch == domain.TagMatchAll {
+		ids = []map[domain.UserID]struct{}{slices.MinFunc(ids, func(a, b map[domain.UserID]struct{}) int {
+			return len(a) - len(b)
+		})}
+	}
+
+	users := make(map[domain.UserID]*domain.User)
+	for _, set := range ids {
+		for id := range set {
+			users[id] = r.users[id]
+		}
+	}
+	return users
+}
+
 func (r *MemoryUserRepository) Update(ctx context.Context, user *domain.User) error {
+	if user.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	_, exists := r.users[user.ID]
-	if !exists {
+	stored, exists := r.users[user.ID]
+	if !exists || stored.TenantID != user.TenantID {
 		return domain.ErrUserNotFound
 	}
 
-	r.users[user.ID] = user
+	r.store(user)
 	return nil
 }
 
-func (r *MemoryUserRepository) Delete(ctx context.Context, id domain.UserID) error {
+func (r *MemoryUserRepository) Delete(ctx context.Context, tenant domain.TenantID, id domain.UserID) error {
+	if tenant == "" {
+		return domain.ErrTenantRequired
+	}
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	_, exists := r.users[id]
-	if !exists {
+	user, exists := r.users[id]
+	if !exists || user.TenantID != tenant {
 		return domain.ErrUserNotFound
 	}
 
+	r.unindexTags(user)
 	delete(r.users, id)
 	return nil
 }
 
-func (r *MemoryUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+func (r *MemoryUserRepository) ExistsByEmail(ctx context.Context, tenant domain.TenantID, email domain.Email) (bool, error) {
+	if tenant == "" {
+		return false, domain.ErrTenantRequired
+	}
+
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	email = strings.ToLower(strings.TrimSpace(email))
-	for _, user := range r.users {
-		if user.Email == email {
-			return true, nil
-		}
+	return r.find(tenant, func(u *domain.User) bool { return u.Email.Equal(email) }) != nil, nil
+}
+
+func (r *MemoryUserRepository) ExistsByUsername(ctx context.Context, tenant domain.TenantID, username domain.Username) (bool, error) {
+	if tenant == "" {
+		return false, domain.ErrTenantRequired
 	}
 
-	return false, nil
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.find(tenant, func(u *domain.User) bool { return u.Username == username }) != nil, nil
 }
 
-func (r *MemoryUserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+func (r *MemoryUserRepository) FindByUsernameSkeleton(ctx context.Context, tenant domain.TenantID, skeleton string) ([]*domain.User, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	username = strings.ToLower(strings.TrimSpace(username))
+	var users []*domain.User
+	for _, user := range r.users {
+		if user.TenantID == tenant && user.Username.Skeleton() == skeleton {
+			users = append(users, cloneUser(user))
+		}
+	}
+	return users, nil
+}
+
+func (r *MemoryUserRepository) UnsetAttribute(ctx context.Context, tenant domain.TenantID, name string) error {
+	if tenant == "" {
+		return domain.ErrTenantRequired
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, user := range r.users {
+		if user.TenantID == tenant {
+			delete(user.Attributes, name)
+		}
+	}
+	return nil
+}
+
+// store saves a copy of user and reindexes its tags. The caller holds the
+// lock.
+func (r *MemoryUserRepository) store(user *domain.User) {
+	if stored, exists := r.users[user.ID]; exists {
+		r.unindexTags(stored)
+	}
+	stored := cloneUser(user)
+	r.users[user.ID] = stored
+	for _, tag := range stored.Tags {
+		key := tagKey{stored.TenantID, tag}
+		if r.tags[key] == nil {
+			r.tags[key] = make(map[domain.UserID]struct{})
+		}
+		r.tags[key][stored.ID] = struct{}{}
+	}
+}
+
+func (r *MemoryUserRepository) unindexTags(user *domain.User) {
+	for _, tag := range user.Tags {
+		key := tagKey{user.TenantID, tag}
+		delete(r.tags[key], user.ID)
+		if len(r.tags[key]) == 0 {
+			delete(r.tags, key)
+		}
+	}
+}
+
+// find returns the first of the tenant's users that matches. The caller
+// holds the lock.
+func (r *MemoryUserRepository) find(tenant domain.TenantID, match func(*domain.User) bool) *domain.User {
 	for _, user := range r.users {
-		if user.Username == username {
-			return true, nil
+		if user.TenantID == tenant && match(user) {
+			return user
 		}
 	}
+	return nil
+}
 
-	return false, nil
+// cloneUser copies user deeply enough that callers cannot change stored
+// state without going through Save or Update.
+func cloneUser(user *domain.User) *domain.User {
+	userCopy := *user
+	if user.TOTP != nil {
+		totp := *user.TOTP
+		userCopy.TOTP = &totp
+	}
+	if user.Avatar != nil {
+		avatar := *user.Avatar
+		avatar.Sizes = append([]int(nil), user.Avatar.Sizes...)
+		userCopy.Avatar = &avatar
+	}
+	userCopy.RecoveryCodes = append([]string(nil), user.RecoveryCodes...)
+	userCopy.Attributes = maps.Clone(user.Attributes)
+	userCopy.Tags = slices.Clone(user.Tags)
+	userCopy.WebAuthnCredentials = make([]domain.WebAuthnCredential, len(user.WebAuthnCredentials))
+	for i, credential := range user.WebAuthnCredentials {
+		if credential.LastUsedAt != nil {
+			lastUsedAt := *credential.LastUsedAt
+			credential.LastUsedAt = &lastUsedAt
+		}
+		userCopy.WebAuthnCredentials[i] = credential
+	}
+	userCopy.PullEvents()
+	return &userCopy
 }
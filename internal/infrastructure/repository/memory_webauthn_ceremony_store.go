@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/application/passkey"
+	"sync"
+	"time"
+)
+
+type MemoryWebAuthnCeremonyStore struct {
+	ceremonies map[string]*passkey.Ceremony
+	mutex      sync.Mutex
+	now        func() time.Time
+}
+
+func NewMemoryWebAuthnCeremonyStore() *MemoryWebAuthnCeremonyStore {
+	return &MemoryWebAuthnCeremonyStore{
+		ceremonies: make(map[string]*passkey.Ceremony),
+		now:        time.Now,
+	}
+}
+
+func (s *MemoryWebAuthnCeremonyStore) Save(ctx context.Context, ceremony *passkey.Ceremony) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.evictExpired()
+
+	ceremonyCopy := *ceremony
+	s.ceremonies[ceremony.ID] = &ceremonyCopy
+	return nil
+}
+
+func (s *MemoryWebAuthnCeremonyStore) Take(ctx context.Context, id string) (*passkey.Ceremony, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ceremony, exists := s.ceremonies[id]
+	if !exists {
+		return nil, passkey.ErrCeremonyNotFound
+	}
+
+	delete(s.ceremonies, id)
+	return ceremony, nil
+}
+
+func (s *MemoryWebAuthnCeremonyStore) evictExpired() {
+	now := s.now()
+	for id, ceremony := range s.ceremonies {
+		if !ceremony.ExpiresAt.After(now) {
+			delete(s.ceremonies, id)
+		}
+	}
+}
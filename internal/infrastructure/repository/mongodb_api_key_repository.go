@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoAPIKeyRepository struct {
+	collection *mongo.Collection
+}
+
+type mongoAPIKey struct {
+	ID         string     `bson:"_id"`
+	OwnerID    string     `bson:"owner_id"`
+	TenantID   string     `bson:"tenant_id"`
+	Name       string     `bson:"name"`
+	Prefix     string     `bson:"prefix"`
+	SecretHash string     `bson:"secret_hash"`
+	Scopes     []string   `bson:"scopes"`
+	CreatedAt  time.Time  `bson:"created_at"`
+	ExpiresAt  *time.Time `bson:"expires_at,omitempty"`
+	LastUsedAt *time.Time `bson:"last_used_at,omitempty"`
+	ReplacedBy string     `bson:"replaced_by,omitempty"`
+}
+
+func NewMongoAPIKeyRepository(db *mongo.Database) *MongoAPIKeyRepository {
+	collection := db.Collection("api_keys")
+
+	// Expired keys are kept so their owners can still see them in the list;
+	// they are deleted explicitly.
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.M{"secret_hash": 1},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.M{"owner_id": 1},
+		},
+	}
+
+	collection.Indexes().CreateMany(context.Background(), indexModels)
+
+	return &MongoAPIKeyRepository{
+		collection: collection,
+	}
+}
+
+func (r *MongoAPIKeyRepository) Save(ctx context.Context, key *domain.APIKey) error {
+	if _, err := r.collection.InsertOne(ctx, r.apiKeyToMongo(key)); err != nil {
+		return fmt.Errorf("failed to save API key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoAPIKeyRepository) GetBySecretHash(ctx context.Context, secretHash string) (*domain.APIKey, error) {
+	return r.findOne(ctx, bson.M{"secret_hash": secretHash})
+}
+
+func (r *MongoAPIKeyRepository) Get(ctx context.Context, ownerID domain.UserID, id domain.APIKeyID) (*domain.APIKey, error) {
+	return r.findOne(ctx, bson.M{"_id": id.String(), "owner_id": ownerID.String()})
+}
+
+func (r *MongoAPIKeyRepository) ListByOwner(ctx context.Context, ownerID domain.UserID) ([]*domain.APIKey, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+	cursor, err := r.collection.Find(ctx, bson.M{"owner_id": ownerID.String()}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []mongoAPIKey
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode API keys: %w", err)
+	}
+
+	keys := make([]*domain.APIKey, len(docs))
+	for i := range docs {
+		keys[i] = r.mongoToAPIKey(&docs[i])
+	}
+	return keys, nil
+}
+
+func (r *MongoAPIKeyRepository) Update(ctx context.Context, key *domain.APIKey) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": key.ID.String()}, r.apiKeyToMongo(key))
+	if err != nil {
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoAPIKeyRepository) Touch(ctx context.Context, id domain.APIKeyID, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id.String()},
+		bson.M{"$set": bson.M{"last_used_at": at}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to touch API key: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoAPIKeyRepository) Delete(ctx context.Context, ownerID domain.UserID, id domain.APIKeyID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id.String(), "owner_id": ownerID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to delete API key: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoAPIKeyRepository) findOne(ctx context.Context, filter bson.M) (*domain.APIKey, error) {
+	var doc mongoAPIKey
+	err := r.collection.FindOne(ctx, filter).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	return r.mongoToAPIKey(&doc), nil
+}
+
+func (r *MongoAPIKeyRepository) apiKeyToMongo(key *domain.APIKey) *mongoAPIKey {
+	return &mongoAPIKey{
+		ID:         key.ID.String(),
+		OwnerID:    key.OwnerID.String(),
+		TenantID:   key.TenantID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		SecretHash: key.SecretHash,
+		Scopes:     key.Scopes,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		ReplacedBy: key.ReplacedBy.String(),
+	}
+}
+
+func (r *MongoAPIKeyRepository) mongoToAPIKey(doc *mongoAPIKey) *domain.APIKey {
+	// Keys issued before tenancy belong to the default tenant.
+	tenant := domain.TenantID(doc.TenantID)
+	if tenant == "" {
+		tenant = domain.DefaultTenantID
+	}
+
+	return &domain.APIKey{
+		ID:         domain.APIKeyID(doc.ID),
+		OwnerID:    domain.UserID(doc.OwnerID),
+		TenantID:   tenant,
+		Name:       doc.Name,
+		Prefix:     doc.Prefix,
+		SecretHash: doc.SecretHash,
+		Scopes:     doc.Scopes,
+		CreatedAt:  doc.CreatedAt,
+		ExpiresAt:  doc.ExpiresAt,
+		LastUsedAt: doc.LastUsedAt,
+		ReplacedBy: domain.APIKeyID(doc.ReplacedBy),
+	}
+}
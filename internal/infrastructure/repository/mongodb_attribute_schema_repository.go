@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoAttributeSchemaRepository struct {
+	collection *mongo.Collection
+}
+
+type mongoAttributeSchema struct {
+	TenantID  string    `bson:"tenant_id"`
+	Name      string    `bson:"name"`
+	Type      string    `bson:"type"`
+	Required  bool      `bson:"required"`
+	Unique    bool      `bson:"unique"`
+	Pattern   string    `bson:"pattern,omitempty"`
+	Enum      []string  `bson:"enum,omitempty"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func NewMongoAttributeSchemaRepository(db *mongo.Database) *MongoAttributeSchemaRepository {
+	collection := db.Collection("attribute_schemas")
+
+	collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return &MongoAttributeSchemaRepository{
+		collection: collection,
+	}
+}
+
+func (r *MongoAttributeSchemaRepository) Save(ctx context.Context, schema *domain.AttributeSchema) error {
+	if schema.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
+	doc := mongoAttributeSchema{
+		TenantID:  schema.TenantID.String(),
+		Name:      schema.Name,
+		Type:      string(schema.Type),
+		Required:  schema.Required,
+		Unique:    schema.Unique,
+		Pattern:   schema.Pattern,
+		Enum:      schema.Enum,
+		CreatedAt: schema.CreatedAt,
+	}
+	if _, err := r.collection.InsertOne(ctx, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrAttributeExists
+		}
+		return fmt.Errorf("failed to save attribute schema: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoAttributeSchemaRepository) Get(ctx context.Context, tenant domain.TenantID, name string) (*domain.AttributeSchema, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	var doc mongoAttributeSchema
+	err := r.collection.FindOne(ctx, tenantFilter(tenant, "name", name)).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrAttributeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attribute schema: %w", err)
+	}
+	return mongoToAttributeSchema(doc), nil
+}
+
+func (r *MongoAttributeSchemaRepository) List(ctx context.Context, tenant domain.TenantID) ([]*domain.AttributeSchema, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	opts := options.Find().SetSort(bson.M{"name": 1})
+	cursor, err := r.collection.Find(ctx, bson.M{"tenant_id": tenant.String()}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attribute schemas: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []mongoAttributeSchema
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode attribute schemas: %w", err)
+	}
+
+	schemas := make([]*domain.AttributeSchema, len(docs))
+	for i, doc := range docs {
+		schemas[i] = mongoToAttributeSchema(doc)
+	}
+	return schemas, nil
+}
+
+func (r *MongoAttributeSchemaRepository) Delete(ctx context.Context, tenant domain.TenantID, name string) error {
+	if tenant == "" {
+		return domain.ErrTenantRequired
+	}
+
+	result, err := r.collection.DeleteOne(ctx, tenantFilter(tenant, "name", name))
+	if err != nil {
+		return fmt.Errorf("failed to delete attribute schema: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrAttributeNotFound
+	}
+	return nil
+}
+
+func mongoToAttributeSchema(doc mongoAttributeSchema) *domain.AttributeSchema {
+	return &domain.AttributeSchema{
+		TenantID:  domain.TenantID(doc.TenantID),
+		Name:      doc.Name,
+		Type:      domain.AttributeType(doc.Type),
+		Required:  doc.Required,
+		Unique:    doc.Unique,
+		Pattern:   doc.Pattern,
+		Enum:      doc.Enum,
+		CreatedAt: doc.CreatedAt,
+	}
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoAuditRepository only ever inserts; the unique index on the sequence
+// keeps concurrent writers from forking a tenant's chain.
+type MongoAuditRepository struct {
+	collection *mongo.Collection
+}
+
+type mongoAuditEntry struct {
+	ID         string             `bson:"_id"`
+	TenantID   string             `bson:"tenant_id"`
+	Sequence   int64              `bson:"sequence"`
+	UserID     string             `bson:"user_id"`
+	Action     string             `bson:"action"`
+	Actor      mongoAuditActor    `bson:"actor"`
+	Changes    []mongoAuditChange `bson:"changes"`
+	OccurredAt time.Time          `bson:"occurred_at"`
+	PrevHash   string             `bson:"prev_hash,omitempty"`
+	Hash       string             `bson:"hash"`
+}
+
+type mongoAuditActor struct {
+	UserID    string `bson:"user_id,omitempty"`
+	APIKeyID  string `bson:"api_key_id,omitempty"`
+	RequestID string `bson:"request_id,omitempty"`
+	IP        string `bson:"ip,omitempty"`
+}
+
+// mongoAuditChange keeps values as JSON text, so they hash the same after
+// the round trip.
+type mongoAuditChange struct {
+	Field  string `bson:"field"`
+	Before string `bson:"before,omitempty"`
+	After  string `bson:"after,omitempty"`
+}
+
+func NewMongoAuditRepository(db *mongo.Database) *MongoAuditRepository {
+	collection := db.Collection("audit_log")
+
+	collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "sequence", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "sequence", Value: -1}},
+		},
+	})
+
+	return &MongoAuditRepository{
+		collection: collection,
+	}
+}
+
+func (r *MongoAuditRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	if entry.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
+	doc := mongoAuditEntry{
+		ID:       entry.ID,
+		TenantID: entry.TenantID.String(),
+		Sequence: entry.Sequence,
+		UserID:   entry.UserID.String(),
+		Action:   string(entry.Action),
+		Actor: mongoAuditActor{
+			UserID:    entry.Actor.UserID,
+			APIKeyID:  entry.Actor.APIKeyID,
+			RequestID: entry.Actor.RequestID,
+			IP:        entry.Actor.IP,
+		},
+		Changes:    make([]mongoAuditChange, len(entry.Changes)),
+		OccurredAt: entry.OccurredAt,
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	}
+	for i, change := range entry.Changes {
+		doc.Changes[i] = mongoAuditChange{Field: change.Field, Before: string(change.Before), After: string(change.After)}
+	}
+
+	if _, err := r.collection.InsertOne(ctx, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrAuditSequenceTaken
+		}
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoAuditRepository) Last(ctx context.Context, tenant domain.TenantID) (*domain.AuditEntry, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	var doc mongoAuditEntry
+	opts := options.FindOne().SetSort(bson.M{"sequence": -1})
+	err := r.collection.FindOne(ctx, bson.M{"tenant_id": tenant.String()}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last audit entry: %w", err)
+	}
+	return mongoToAuditEntry(doc), nil
+}
+
+func (r *MongoAuditRepository) List(ctx context.Context, tenant domain.TenantID, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	query := bson.M{"tenant_id": tenant.String()}
+	if filter.UserID != "" {
+		query["user_id"] = filter.UserID.String()
+	}
+	if filter.Before > 0 {
+		query["sequence"] = bson.M{"$lt": filter.Before}
+	}
+	opts := options.Find().SetSort(bson.M{"sequence": -1})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+	return r.find(ctx, query, opts)
+}
+
+func (r *MongoAuditRepository) Chain(ctx context.Context, tenant domain.TenantID) ([]*domain.AuditEntry, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	return r.find(ctx, bson.M{"tenant_id": tenant.String()}, options.Find().SetSort(bson.M{"sequence": 1}))
+}
+
+func (r *MongoAuditRepository) find(ctx context.Context, query bson.M, opts *options.FindOptions) ([]*domain.AuditEntry, error) {
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []mongoAuditEntry
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode audit entries: %w", err)
+	}
+
+	entries := make([]*domain.AuditEntry, len(docs))
+	for i, doc := range docs {
+		entries[i] = mongoToAuditEntry(doc)
+	}
+	return entries, nil
+}
+
+func mongoToAuditEntry(doc mongoAuditEntry) *domain.AuditEntry {
+	entry := &domain.AuditEntry{
+		ID:       doc.ID,
+		TenantID: domain.TenantID(doc.TenantID),
+		Sequence: doc.Sequence,
+		UserID:   domain.UserID(doc.UserID),
+		Action:   domain.AuditAction(doc.Action),
+		Actor: domain.AuditActor{
+			UserID:    doc.Actor.UserID,
+			APIKeyID:  doc.Actor.APIKeyID,
+			RequestID: doc.Actor.RequestID,
+			IP:        doc.Actor.IP,
+		},
+		OccurredAt: doc.OccurredAt,
+		PrevHash:   doc.PrevHash,
+		Hash:       doc.Hash,
+	}
+	for _, change := range doc.Changes {
+		entry.Changes = append(entry.Changes, domain.FieldChange{
+			Field:  change.Field,
+			Before: rawJSON(change.Before),
+			After:  rawJSON(change.After),
+		})
+	}
+	return entry
+}
+
+func rawJSON(text string) json.RawMessage {
+	if text == "" {
+		return nil
+	}
+	return json.RawMessage(text)
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/application/idempotency"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoIdempotencyStore struct {
+	collection *mongo.Collection
+}
+
+type mongoIdempotencyRecord struct {
+	Key         string              `bson:"_id"`
+	Fingerprint string              `bson:"fingerprint"`
+	StatusCode  int                 `bson:"status_code"`
+	Header      map[string][]string `bson:"header,omitempty"`
+	Body        []byte              `bson:"body,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at"`
+	ExpiresAt   time.Time           `bson:"expires_at"`
+}
+
+func NewMongoIdempotencyStore(db *mongo.Database) *MongoIdempotencyStore {
+	collection := db.Collection("idempotency_keys")
+
+	// Let MongoDB reap expired records
+	collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.M{"expires_at": 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+
+	return &MongoIdempotencyStore{
+		collection: collection,
+	}
+}
+
+func (s *MongoIdempotencyStore) Reserve(ctx context.Context, rec *idempotency.Record) (*idempotency.Record, error) {
+	doc := mongoIdempotencyRecord{
+		Key:         rec.Key,
+		Fingerprint: rec.Fingerprint,
+		CreatedAt:   rec.CreatedAt,
+		ExpiresAt:   rec.ExpiresAt,
+	}
+
+	// The TTL monitor runs about once a minute, so expired records may still
+	// be present; remove them before trying to insert.
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": rec.Key, "expires_at": bson.M{"$lte": rec.CreatedAt}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove expired idempotency record: %w", err)
+	}
+
+	_, err = s.collection.InsertOne(ctx, doc)
+	if err == nil {
+		return nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	var existing mongoIdempotencyRecord
+	if err := s.collection.FindOne(ctx, bson.M{"_id": rec.Key}).Decode(&existing); err != nil {
+		return nil, fmt.Errorf("failed to load idempotency record: %w", err)
+	}
+
+	return &idempotency.Record{
+		Key:         existing.Key,
+		Fingerprint: existing.Fingerprint,
+		StatusCode:  existing.StatusCode,
+		Header:      http.Header(existing.Header),
+		Body:        existing.Body,
+		CreatedAt:   existing.CreatedAt,
+		ExpiresAt:   existing.ExpiresAt,
+	}, nil
+}
+
+func (s *MongoIdempotencyStore) Complete(ctx context.Context, key string, statusCode int, header http.Header, body []byte) error {
+	update := bson.M{
+		"$set": bson.M{
+			"status_code": statusCode,
+			"header":      map[string][]string(header),
+			"body":        body,
+		},
+	}
+
+	result, err := s.collection.UpdateOne(ctx, bson.M{"_id": key}, update)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency record: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return idempotency.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *MongoIdempotencyStore) Release(ctx context.Context, key string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": key})
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
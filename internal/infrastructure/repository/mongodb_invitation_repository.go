@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoInvitationRepository struct {
+	collection *mongo.Collection
+}
+
+type mongoInvitation struct {
+	ID               string     `bson:"_id"`
+	TenantID         string     `bson:"tenant_id"`
+	Email            string     `bson:"email"`
+	Role             string     `bson:"role,omitempty"`
+	OrganizationID   string     `bson:"organization_id"`
+	OrganizationRole string     `bson:"organization_role,omitempty"`
+	InvitedBy        string     `bson:"invited_by,omitempty"`
+	TokenHash        string     `bson:"token_hash"`
+	UserID           string     `bson:"user_id,omitempty"`
+	SendCount        int        `bson:"send_count"`
+	CreatedAt        time.Time  `bson:"created_at"`
+	SentAt           time.Time  `bson:"sent_at"`
+	ExpiresAt        time.Time  `bson:"expires_at"`
+	AcceptedAt       *time.Time `bson:"accepted_at,omitempty"`
+	RevokedAt        *time.Time `bson:"revoked_at,omitempty"`
+}
+
+func NewMongoInvitationRepository(db *mongo.Database) *MongoInvitationRepository {
+	collection := db.Collection("invitations")
+
+	// Invitations outlive their expiry: they stay listed and accepted ones
+	// keep answering repeated accepts, so there is no TTL index.
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.M{"token_hash": 1},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "email", Value: 1}, {Key: "organization_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+
+	collection.Indexes().CreateMany(context.Background(), indexModels)
+
+	return &MongoInvitationRepository{
+		collection: collection,
+	}
+}
+
+func (r *MongoInvitationRepository) Save(ctx context.Context, inv *domain.Invitation) error {
+	if inv.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
+	if _, err := r.collection.InsertOne(ctx, invitationToMongo(inv)); err != nil {
+		return fmt.Errorf("failed to save invitation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoInvitationRepository) Get(ctx context.Context, tenant domain.TenantID, id domain.InvitationID) (*domain.Invitation, error) {
+	return r.findOne(ctx, tenant, bson.M{"_id": id.String()})
+}
+
+func (r *MongoInvitationRepository) GetByTokenHash(ctx context.Context, tenant domain.TenantID, tokenHash string) (*domain.Invitation, error) {
+	return r.findOne(ctx, tenant, bson.M{"token_hash": tokenHash})
+}
+
+func (r *MongoInvitationRepository) FindOpen(ctx context.Context, tenant domain.TenantID, email domain.Email, orgID domain.OrganizationID) (*domain.Invitation, error) {
+	return r.findOne(ctx, tenant, bson.M{
+		"email":           email.String(),
+		"organization_id": orgID.String(),
+		"accepted_at":     bson.M{"$exists": false},
+		"revoked_at":      bson.M{"$exists": false},
+	})
+}
+
+func (r *MongoInvitationRepository) findOne(ctx context.Context, tenant domain.TenantID, filter bson.M) (*domain.Invitation, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+	filter["tenant_id"] = tenant.String()
+
+	var doc mongoInvitation
+	err := r.collection.FindOne(ctx, filter).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvitationNotFound
+		}
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	return mongoToInvitation(&doc), nil
+}
+
+func (r *MongoInvitationRepository) List(ctx context.Context, tenant domain.TenantID) ([]*domain.Invitation, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+	cursor, err := r.collection.Find(ctx, bson.M{"tenant_id": tenant.String()}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []mongoInvitation
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode invitations: %w", err)
+	}
+
+	invitations := make([]*domain.Invitation, len(docs))
+	for i := range docs {
+		invitations[i] = mongoToInvitation(&docs[i])
+	}
+	return invitations, nil
+}
+
+func (r *MongoInvitationRepository) Update(ctx context.Context, inv *domain.Invitation) error {
+	if inv.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
+	result, err := r.collection.ReplaceOne(ctx, tenantFilter(inv.TenantID, "_id", inv.ID.String()), invitationToMongo(inv))
+	if err != nil {
+		return fmt.Errorf("failed to update invitation: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+func invitationToMongo(inv *domain.Invitation) *mongoInvitation {
+	return &mongoInvitation{
+		ID:               inv.ID.String(),
+		TenantID:         inv.TenantID.String(),
+		Email:            inv.Email.String(),
+		Role:             inv.Role.String(),
+		OrganizationID:   inv.OrganizationID.String(),
+		OrganizationRole: inv.OrganizationRole.String(),
+		InvitedBy:        inv.InvitedBy.String(),
+		TokenHash:        inv.TokenHash,
+		UserID:           inv.UserID.String(),
+		SendCount:        inv.SendCount,
+		CreatedAt:        inv.CreatedAt,
+		SentAt:           inv.SentAt,
+		ExpiresAt:        inv.ExpiresAt,
+		AcceptedAt:       inv.AcceptedAt,
+		RevokedAt:        inv.RevokedAt,
+	}
+}
+
+func mongoToInvitation(doc *mongoInvitation) *domain.Invitation {
+	return &domain.Invitation{
+		ID:               domain.InvitationID(doc.ID),
+		TenantID:         domain.TenantID(doc.TenantID),
+		Email:            domain.Email(doc.Email),
+		Role:             domain.Role(doc.Role),
+		OrganizationID:   domain.OrganizationID(doc.OrganizationID),
+		OrganizationRole: domain.MemberRole(doc.OrganizationRole),
+		InvitedBy:        domain.UserID(doc.InvitedBy),
+		TokenHash:        doc.TokenHash,
+		UserID:           domain.UserID(doc.UserID),
+		SendCount:        doc.SendCount,
+		CreatedAt:        doc.CreatedAt,
+		SentAt:           doc.SentAt,
+		ExpiresAt:        doc.ExpiresAt,
+		AcceptedAt:       doc.AcceptedAt,
+		RevokedAt:        doc.RevokedAt,
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/application/lockout"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoLoginAttemptStore struct {
+	collection *mongo.Collection
+}
+
+type mongoLoginAttempts struct {
+	Key           string    `bson:"_id"`
+	Failures      int       `bson:"failures"`
+	LastFailureAt time.Time `bson:"last_failure_at,omitempty"`
+	LockedUntil   time.Time `bson:"locked_until,omitempty"`
+	ExpiresAt     time.Time `bson:"expires_at"`
+}
+
+func NewMongoLoginAttemptStore(db *mongo.Database) *MongoLoginAttemptStore {
+	collection := db.Collection("login_attempts")
+
+	// Counters and locks disappear once they no longer matter.
+	indexModel := mongo.IndexModel{
+		Keys:    bson.M{"expires_at": 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+
+	collection.Indexes().CreateOne(context.Background(), indexModel)
+
+	return &MongoLoginAttemptStore{
+		collection: collection,
+	}
+}
+
+func (s *MongoLoginAttemptStore) Get(ctx context.Context, key string) (lockout.Attempts, error) {
+	var doc mongoLoginAttempts
+	err := s.collection.FindOne(ctx, bson.M{"_id": key, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return lockout.Attempts{}, nil
+		}
+		return lockout.Attempts{}, fmt.Errorf("failed to get login attempts: %w", err)
+	}
+
+	return s.mongoToAttempts(&doc), nil
+}
+
+// RecordFailure uses an update pipeline so that resetting a stale counter
+// and incrementing it happen in one atomic write.
+func (s *MongoLoginAttemptStore) RecordFailure(ctx context.Context, key string, at time.Time, window time.Duration) (lockout.Attempts, error) {
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"failures": bson.M{"$cond": bson.A{
+				bson.M{"$gte": bson.A{"$last_failure_at", at.Add(-window)}},
+				bson.M{"$add": bson.A{"$failures", 1}},
+				1,
+			}},
+			"last_failure_at": at,
+			"expires_at":      bson.M{"$max": bson.A{"$locked_until", at.Add(window)}},
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var doc mongoLoginAttempts
+	if err := s.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&doc); err != nil {
+		return lockout.Attempts{}, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	return s.mongoToAttempts(&doc), nil
+}
+
+func (s *MongoLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"failures":     0,
+			"locked_until": until,
+			"expires_at":   bson.M{"$max": bson.A{"$expires_at", until}},
+		}}},
+	}
+
+	if _, err := s.collection.UpdateOne(ctx, bson.M{"_id": key}, update, options.Update().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to lock login: %w", err)
+	}
+
+	return nil
+}
+
+func (s *MongoLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	if _, err := s.collection.DeleteOne(ctx, bson.M{"_id": key}); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+
+	return nil
+}
+
+func (s *MongoLoginAttemptStore) mongoToAttempts(doc *mongoLoginAttempts) lockout.Attempts {
+	return lockout.Attempts{
+		Failures:      doc.Failures,
+		LastFailureAt: doc.LastFailureAt,
+		LockedUntil:   doc.LockedUntil,
+	}
+}
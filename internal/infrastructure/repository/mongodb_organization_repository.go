@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoOrganizationRepository struct {
+	collection *mongo.Collection
+}
+
+type mongoOrganization struct {
+	ID        string            `bson:"_id"`
+	TenantID  string            `bson:"tenant_id"`
+	Name      string            `bson:"name"`
+	Members   []mongoMembership `bson:"members"`
+	Teams     []mongoTeam       `bson:"teams"`
+	CreatedAt time.Time         `bson:"created_at"`
+	Version   int64             `bson:"version"`
+}
+
+type mongoMembership struct {
+	UserID    string     `bson:"user_id"`
+	Role      string     `bson:"role"`
+	Invited   bool       `bson:"invited"`
+	InvitedBy string     `bson:"invited_by,omitempty"`
+	CreatedAt time.Time  `bson:"created_at"`
+	JoinedAt  *time.Time `bson:"joined_at,omitempty"`
+}
+
+type mongoTeam struct {
+	ID        string    `bson:"id"`
+	Name      string    `bson:"name"`
+	Members   []string  `bson:"members"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func NewMongoOrganizationRepository(db *mongo.Database) *MongoOrganizationRepository {
+	collection := db.Collection("organizations")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "members.user_id", Value: 1}},
+		},
+	}
+
+	collection.Indexes().CreateMany(context.Background(), indexModels)
+
+	return &MongoOrganizationRepository{
+		collection: collection,
+	}
+}
+
+func (r *MongoOrganizationRepository) Save(ctx context.Context, org *domain.Organization) error {
+	if org.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
+	if _, err := r.collection.InsertOne(ctx, organizationToMongo(org)); err != nil {
+		return fmt.Errorf("failed to save organization: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoOrganizationRepository) Get(ctx context.Context, tenant domain.TenantID, id domain.OrganizationID) (*domain.Organization, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	var doc mongoOrganization
+	err := r.collection.FindOne(ctx, tenantFilter(tenant, "_id", id.String())).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	return mongoToOrganization(&doc), nil
+}
+
+func (r *MongoOrganizationRepository) ListByMember(ctx context.Context, tenant domain.TenantID, userID domain.UserID) ([]*domain.Organization, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+	cursor, err := r.collection.Find(ctx, tenantFilter(tenant, "members.user_id", userID.String()), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []mongoOrganization
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode organizations: %w", err)
+	}
+
+	orgs := make([]*domain.Organization, len(docs))
+	for i := range docs {
+		orgs[i] = mongoToOrganization(&docs[i])
+	}
+	return orgs, nil
+}
+
+func (r *MongoOrganizationRepository) Update(ctx context.Context, org *domain.Organization) error {
+	if org.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
+	filter := tenantFilter(org.TenantID, "_id", org.ID.String())
+	current := bson.M{"version": org.Version}
+	if org.Version == 0 {
+		// Organizations stored before versioning have no version field.
+		current = bson.M{"version": bson.M{"$in": bson.A{0, nil}}}
+	}
+
+	doc := organizationToMongo(org)
+	doc.Version++
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"$and": bson.A{filter, current}}, doc)
+	if err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		count, err := r.collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to update organization: %w", err)
+		}
+		if count == 0 {
+			return domain.ErrOrganizationNotFound
+		}
+		return domain.ErrOrganizationConflict
+	}
+
+	org.Version = doc.Version
+	return nil
+}
+
+func (r *MongoOrganizationRepository) Delete(ctx context.Context, tenant domain.TenantID, id domain.OrganizationID) error {
+	if tenant == "" {
+		return domain.ErrTenantRequired
+	}
+
+	result, err := r.collection.DeleteOne(ctx, tenantFilter(tenant, "_id", id.String()))
+	if err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrOrganizationNotFound
+	}
+
+	return nil
+}
+
+func organizationToMongo(org *domain.Organization) *mongoOrganization {
+	doc := &mongoOrganization{
+		ID:        org.ID.String(),
+		TenantID:  org.TenantID.String(),
+		Name:      org.Name,
+		Members:   make([]mongoMembership, len(org.Members)),
+		Teams:     make([]mongoTeam, len(org.Teams)),
+		CreatedAt: org.CreatedAt,
+		Version:   org.Version,
+	}
+	for i, m := range org.Members {
+		doc.Members[i] = mongoMembership{
+			UserID:    m.UserID.String(),
+			Role:      m.Role.String(),
+			Invited:   m.Invited,
+			InvitedBy: m.InvitedBy.String(),
+			CreatedAt: m.CreatedAt,
+			JoinedAt:  m.JoinedAt,
+		}
+	}
+	for i, team := range org.Teams {
+		members := make([]string, len(team.Members))
+		for j, id := range team.Members {
+			members[j] = id.String()
+		}
+		doc.Teams[i] = mongoTeam{
+			ID:        team.ID.String(),
+			Name:      team.Name,
+			Members:   members,
+			CreatedAt: team.CreatedAt,
+		}
+	}
+	return doc
+}
+
+func mongoToOrganization(doc *mongoOrganization) *domain.Organization {
+	org := &domain.Organization{
+		ID:        domain.OrganizationID(doc.ID),
+		TenantID:  domain.TenantID(doc.TenantID),
+		Name:      doc.Name,
+		CreatedAt: doc.CreatedAt,
+		Version:   doc.Version,
+	}
+	for _, m := range doc.Members {
+		org.Members = append(org.Members, domain.Membership{
+			UserID:    domain.UserID(m.UserID),
+			Role:      domain.MemberRole(m.Role),
+			Invited:   m.Invited,
+			InvitedBy: domain.UserID(m.InvitedBy),
+			CreatedAt: m.CreatedAt,
+			JoinedAt:  m.JoinedAt,
+		})
+	}
+	for _, team := range doc.Teams {
+		members := make([]domain.UserID, len(team.Members))
+		for j, id := range team.Members {
+			members[j] = domain.UserID(id)
+		}
+		org.Teams = append(org.Teams, domain.Team{
+			ID:        domain.TeamID(team.ID),
+			Name:      team.Name,
+			Members:   members,
+			CreatedAt: team.CreatedAt,
+		})
+	}
+	return org
+}
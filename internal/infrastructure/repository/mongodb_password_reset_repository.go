@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoPasswordResetRepository struct {
+	collection *mongo.Collection
+}
+
+type mongoPasswordResetToken struct {
+	TokenHash string    `bson:"_id"`
+	UserID    string    `bson:"user_id"`
+	CreatedAt time.Time `bson:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+func NewMongoPasswordResetRepository(db *mongo.Database) *MongoPasswordResetRepository {
+	collection := db.Collection("password_reset_tokens")
+
+	// MongoDB removes expired tokens in the background; Consume callers still
+	// check expiry because the TTL monitor only runs once a minute.
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.M{"expires_at": 1},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{
+			Keys: bson.M{"user_id": 1},
+		},
+	}
+
+	collection.Indexes().CreateMany(context.Background(), indexModels)
+
+	return &MongoPasswordResetRepository{
+		collection: collection,
+	}
+}
+
+func (r *MongoPasswordResetRepository) Save(ctx context.Context, token *domain.PasswordResetToken) error {
+	doc := mongoPasswordResetToken{
+		TokenHash: token.TokenHash,
+		UserID:    token.UserID.String(),
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+	}
+
+	if _, err := r.collection.InsertOne(ctx, doc); err != nil {
+		return fmt.Errorf("failed to save password reset token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoPasswordResetRepository) Consume(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	var doc mongoPasswordResetToken
+	err := r.collection.FindOneAndDelete(ctx, bson.M{"_id": tokenHash}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrResetTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to consume password reset token: %w", err)
+	}
+
+	return &domain.PasswordResetToken{
+		TokenHash: doc.TokenHash,
+		UserID:    domain.UserID(doc.UserID),
+		CreatedAt: doc.CreatedAt,
+		ExpiresAt: doc.ExpiresAt,
+	}, nil
+}
+
+func (r *MongoPasswordResetRepository) DeleteByUser(ctx context.Context, userID domain.UserID) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID.String()}); err != nil {
+		return fmt.Errorf("failed to delete password reset tokens: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/application/policy"
+	"ddd-user-service/internal/domain"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoPolicyStore struct {
+	collection *mongo.Collection
+}
+
+type mongoPolicyEntry struct {
+	TenantID  string    `bson:"tenant_id"`
+	List      string    `bson:"list"`
+	Value     string    `bson:"value"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func NewMongoPolicyStore(db *mongo.Database) *MongoPolicyStore {
+	collection := db.Collection("policy_entries")
+
+	collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "list", Value: 1}, {Key: "value", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return &MongoPolicyStore{
+		collection: collection,
+	}
+}
+
+func policyFilter(tenant domain.TenantID, list policy.List, value string) bson.M {
+	return bson.M{"tenant_id": tenant.String(), "list": string(list), "value": value}
+}
+
+func (s *MongoPolicyStore) List(ctx context.Context, tenant domain.TenantID, list policy.List) ([]policy.Entry, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	opts := options.Find().SetSort(bson.M{"value": 1})
+	cursor, err := s.collection.Find(ctx, bson.M{"tenant_id": tenant.String(), "list": string(list)}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policy entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []mongoPolicyEntry
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode policy entries: %w", err)
+	}
+
+	entries := make([]policy.Entry, len(docs))
+	for i, doc := range docs {
+		entries[i] = mongoToPolicyEntry(doc)
+	}
+	return entries, nil
+}
+
+func (s *MongoPolicyStore) Contains(ctx context.Context, tenant domain.TenantID, list policy.List, value string) (bool, error) {
+	if tenant == "" {
+		return false, domain.ErrTenantRequired
+	}
+
+	count, err := s.collection.CountDocuments(ctx, policyFilter(tenant, list, value))
+	if err != nil {
+		return false, fmt.Errorf("failed to check policy entry: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// Add inserts only when the entry is missing, so concurrent adds keep the
+// first entry.
+func (s *MongoPolicyStore) Add(ctx context.Context, tenant domain.TenantID, entry policy.Entry) (policy.Entry, bool, error) {
+	if tenant == "" {
+		return policy.Entry{}, false, domain.ErrTenantRequired
+	}
+
+	doc := mongoPolicyEntry{
+		TenantID:  tenant.String(),
+		List:      string(entry.List),
+		Value:     entry.Value,
+		CreatedAt: entry.CreatedAt,
+	}
+	result, err := s.collection.UpdateOne(ctx, policyFilter(tenant, entry.List, entry.Value),
+		bson.M{"$setOnInsert": doc}, options.Update().SetUpsert(true))
+	if err != nil {
+		return policy.Entry{}, false, fmt.Errorf("failed to add policy entry: %w", err)
+	}
+	if result.UpsertedCount > 0 {
+		return entry, true, nil
+	}
+
+	var existing mongoPolicyEntry
+	if err := s.collection.FindOne(ctx, policyFilter(tenant, entry.List, entry.Value)).Decode(&existing); err != nil {
+		return policy.Entry{}, false, fmt.Errorf("failed to get policy entry: %w", err)
+	}
+	return mongoToPolicyEntry(existing), false, nil
+}
+
+func (s *MongoPolicyStore) Remove(ctx context.Context, tenant domain.TenantID, list policy.List, value string) error {
+	if tenant == "" {
+		return domain.ErrTenantRequired
+	}
+
+	result, err := s.collection.DeleteOne(ctx, policyFilter(tenant, list, value))
+	if err != nil {
+		return fmt.Errorf("failed to remove policy entry: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return policy.ErrEntryNotFound
+	}
+
+	return nil
+}
+
+func mongoToPolicyEntry(doc mongoPolicyEntry) policy.Entry {
+	return policy.Entry{
+		List:      policy.List(doc.List),
+		Value:     doc.Value,
+		Source:    policy.SourceAdmin,
+		CreatedAt: doc.CreatedAt,
+	}
+}
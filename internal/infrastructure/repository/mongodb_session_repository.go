@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoSessionRepository struct {
+	collection *mongo.Collection
+}
+
+type mongoSession struct {
+	ID         string    `bson:"_id"`
+	UserID     string    `bson:"user_id"`
+	TenantID   string    `bson:"tenant_id"`
+	TokenHash  string    `bson:"token_hash"`
+	Scope      string    `bson:"scope"`
+	Device     string    `bson:"device,omitempty"`
+	IPAddress  string    `bson:"ip_address,omitempty"`
+	UserAgent  string    `bson:"user_agent,omitempty"`
+	CreatedAt  time.Time `bson:"created_at"`
+	LastSeenAt time.Time `bson:"last_seen_at"`
+	ExpiresAt  time.Time `bson:"expires_at"`
+}
+
+func NewMongoSessionRepository(db *mongo.Database) *MongoSessionRepository {
+	collection := db.Collection("sessions")
+
+	// Sessions past their absolute expiry are removed by MongoDB; idle ones
+	// are removed when next presented.
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.M{"token_hash": 1},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.M{"user_id": 1},
+		},
+		{
+			Keys:    bson.M{"expires_at": 1},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	collection.Indexes().CreateMany(context.Background(), indexModels)
+
+	return &MongoSessionRepository{
+		collection: collection,
+	}
+}
+
+func (r *MongoSessionRepository) Save(ctx context.Context, session *domain.Session) error {
+	doc := mongoSession{
+		ID:         session.ID.String(),
+		UserID:     session.UserID.String(),
+		TenantID:   session.TenantID.String(),
+		TokenHash:  session.TokenHash,
+		Scope:      session.Scope,
+		Device:     session.Device,
+		IPAddress:  session.IPAddress,
+		UserAgent:  session.UserAgent,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+
+	if _, err := r.collection.InsertOne(ctx, doc); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MongoSessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Session, error) {
+	var doc mongoSession
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return r.mongoSessionToDomain(&doc), nil
+}
+
+func (r *MongoSessionRepository) ListByUser(ctx context.Context, userID domain.UserID) ([]*domain.Session, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID.String()}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []mongoSession
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode sessions: %w", err)
+	}
+
+	sessions := make([]*domain.Session, len(docs))
+	for i := range docs {
+		sessions[i] = r.mongoSessionToDomain(&docs[i])
+	}
+	return sessions, nil
+}
+
+func (r *MongoSessionRepository) Touch(ctx context.Context, id domain.SessionID, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id.String()},
+		bson.M{"$set": bson.M{"last_seen_at": at}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoSessionRepository) Delete(ctx context.Context, userID domain.UserID, id domain.SessionID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id.String(), "user_id": userID.String()})
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoSessionRepository) DeleteByUser(ctx context.Context, userID domain.UserID) (int, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID.String()})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sessions: %w", err)
+	}
+
+	return int(result.DeletedCount), nil
+}
+
+func (r *MongoSessionRepository) mongoSessionToDomain(doc *mongoSession) *domain.Session {
+	// Sessions started before tenancy belong to the default tenant.
+	tenant := domain.TenantID(doc.TenantID)
+	if tenant == "" {
+		tenant = domain.DefaultTenantID
+	}
+
+	return &domain.Session{
+		ID:         domain.SessionID(doc.ID),
+		UserID:     domain.UserID(doc.UserID),
+		TenantID:   tenant,
+		TokenHash:  doc.TokenHash,
+		Scope:      doc.Scope,
+		Device:     doc.Device,
+		IPAddress:  doc.IPAddress,
+		UserAgent:  doc.UserAgent,
+		CreatedAt:  doc.CreatedAt,
+		LastSeenAt: doc.LastSeenAt,
+		ExpiresAt:  doc.ExpiresAt,
+	}
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/application/mail"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoSuppressionList struct {
+	collection *mongo.Collection
+}
+
+type mongoSuppression struct {
+	Email     string    `bson:"_id"`
+	Reason    string    `bson:"reason"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func NewMongoSuppressionList(db *mongo.Database) *MongoSuppressionList {
+	return &MongoSuppressionList{
+		collection: db.Collection("mail_suppressions"),
+	}
+}
+
+func (l *MongoSuppressionList) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	count, err := l.collection.CountDocuments(ctx, bson.M{"_id": strings.ToLower(strings.TrimSpace(email))})
+	if err != nil {
+		return false, fmt.Errorf("failed to check suppression: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (l *MongoSuppressionList) Suppress(ctx context.Context, suppression mail.Suppression) error {
+	doc := mongoSuppression{
+		Email:     strings.ToLower(strings.TrimSpace(suppression.Email)),
+		Reason:    suppression.Reason,
+		CreatedAt: suppression.CreatedAt,
+	}
+
+	_, err := l.collection.ReplaceOne(ctx, bson.M{"_id": doc.Email}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to suppress address: %w", err)
+	}
+
+	return nil
+}
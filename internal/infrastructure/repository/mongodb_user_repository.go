@@ -3,7 +3,11 @@ package repository
 import (
 	"context"
 	"ddd-user-service/internal/domain"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -15,46 +19,269 @@ type MongoUserRepository struct {
 }
 
 type mongoUser struct {
-	ID       string `bson:"_id"`
-	Name     string `bson:"name"`
-	Email    string `bson:"email"`
-	Username string `bson:"username"`
+	ID                string                    `bson:"_id"`
+	TenantID          string                    `bson:"tenant_id"`
+	Name              string                    `bson:"name"`
+	Email             string                    `bson:"email"`
+	EmailKey          string                    `bson:"email_key"`
+	Username          string                    `bson:"username"`
+	UsernameSkeleton  string                    `bson:"username_skeleton"`
+	EmailVerified     bool                      `bson:"email_verified"`
+	EmailVerifiedAt   *time.Time                `bson:"email_verified_at,omitempty"`
+	PendingEmail      string                    `bson:"pending_email,omitempty"`
+	PasswordHash      string                    `bson:"password_hash,omitempty"`
+	PasswordChangedAt *time.Time                `bson:"password_changed_at,omitempty"`
+	Role              string                    `bson:"role,omitempty"`
+	TOTP              *mongoTOTP                `bson:"totp,omitempty"`
+	RecoveryCodes     []string                  `bson:"recovery_codes,omitempty"`
+	WebAuthn          []mongoWebAuthnCredential `bson:"webauthn_credentials,omitempty"`
+	SuspendedAt       *time.Time                `bson:"suspended_at,omitempty"`
+	Profile           mongoProfile              `bson:"profile"`
+	Avatar            *mongoAvatar              `bson:"avatar,omitempty"`
+	Attributes        map[string]any            `bson:"attributes,omitempty"`
+	Tags              []string                  `bson:"tags,omitempty"`
 }
 
-func NewMongoUserRepository(db *mongo.Database) *MongoUserRepository {
+type mongoProfile struct {
+	DisplayName string `bson:"display_name,omitempty"`
+	GivenName   string `bson:"given_name,omitempty"`
+	FamilyName  string `bson:"family_name,omitempty"`
+	Phone       string `bson:"phone,omitempty"`
+	Locale      string `bson:"locale,omitempty"`
+	Timezone    string `bson:"timezone,omitempty"`
+	Bio         string `bson:"bio,omitempty"`
+	AvatarURL   string `bson:"avatar_url,omitempty"`
+}
+
+type mongoAvatar struct {
+	ID          string    `bson:"id"`
+	ContentType string    `bson:"content_type"`
+	Sizes       []int     `bson:"sizes"`
+	UploadedAt  time.Time `bson:"uploaded_at"`
+}
+
+type mongoWebAuthnCredential struct {
+	ID              []byte     `bson:"id"`
+	Name            string     `bson:"name"`
+	PublicKey       []byte     `bson:"public_key"`
+	AttestationType string     `bson:"attestation_type"`
+	Transports      []string   `bson:"transports,omitempty"`
+	AAGUID          []byte     `bson:"aaguid,omitempty"`
+	SignCount       uint32     `bson:"sign_count"`
+	BackupEligible  bool       `bson:"backup_eligible"`
+	BackupState     bool       `bson:"backup_state"`
+	CreatedAt       time.Time  `bson:"created_at"`
+	LastUsedAt      *time.Time `bson:"last_used_at,omitempty"`
+}
+
+type mongoTOTP struct {
+	Secret       string     `bson:"secret"`
+	Confirmed    bool       `bson:"confirmed"`
+	ConfirmedAt  *time.Time `bson:"confirmed_at,omitempty"`
+	LastUsedStep int64      `bson:"last_used_step"`
+}
+
+// NewMongoUserRepository migrates the stored users to the current schema
+// and indexes before returning the repository.
+func NewMongoUserRepository(db *mongo.Database) (*MongoUserRepository, error) {
 	collection := db.Collection("users")
+	ctx := context.Background()
+
+	// Users stored before tenancy belong to the default tenant. The global
+	// unique indexes they relied on would stop other tenants from reusing an
+	// email or username, so they are replaced by per-tenant ones.
+	if _, err := collection.UpdateMany(ctx,
+		bson.M{"tenant_id": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"tenant_id": domain.DefaultTenantID.String()}},
+	); err != nil {
+		return nil, fmt.Errorf("failed to assign users to the default tenant: %w", err)
+	}
+	for _, name := range []string{"email_1", "username_1"} {
+		if err := dropIndex(ctx, collection, name); err != nil {
+			return nil, err
+		}
+	}
+
+	// Emails are unique by their key, which depends on EmailRules, and
+	// usernames are matched for lookalikes by their skeleton. Both are
+	// rebuilt before the unique index needs them.
+	if err := rekeyUsers(ctx, db.Collection("schema_versions"), collection); err != nil {
+		return nil, err
+	}
 
-	// Create unique indexes for email and username
 	indexModels := []mongo.IndexModel{
 		{
-			Keys:    bson.M{"email": 1},
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "email_key", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
 		{
-			Keys:    bson.M{"username": 1},
-			Options: options.Index().SetUnique(true),
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "username", Value: 1}},
+			Options: options.Index().SetName(usernameIndexName).SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "username_skeleton", Value: 1}},
+		},
+		{
+			// Multikey: each of a user's tags is indexed.
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "tags", Value: 1}},
 		},
 	}
 
-	collection.Indexes().CreateMany(context.Background(), indexModels)
+	if _, err := collection.Indexes().CreateMany(ctx, indexModels); err != nil {
+		return nil, fmt.Errorf("failed to create user indexes: %w", err)
+	}
 
 	return &MongoUserRepository{
 		collection: collection,
+	}, nil
+}
+
+// identifierKeysVersionID is the schema_versions document recording the
+// domain.IdentifierKeysVersion the stored keys were built under.
+const identifierKeysVersionID = "users.identifier_keys"
+
+// rekeyUsers brings every stored email key and username skeleton up to
+// date when EmailRules or the skeleton mapping changed since the last
+// start. Two users whose addresses now share a key stop the start up, since
+// the service could no longer tell them apart; one of them must be changed
+// or the rules set back.
+func rekeyUsers(ctx context.Context, versions, collection *mongo.Collection) error {
+	version := domain.IdentifierKeysVersion()
+	var stored struct {
+		Version string `bson:"version"`
+	}
+	err := versions.FindOne(ctx, bson.M{"_id": identifierKeysVersionID}).Decode(&stored)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("failed to read identifier keys version: %w", err)
+	}
+	if stored.Version == version {
+		return nil
+	}
+
+	opts := options.Find().SetProjection(bson.M{"tenant_id": 1, "email": 1, "email_key": 1, "username": 1, "username_skeleton": 1})
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return fmt.Errorf("failed to read users to rekey: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID               string `bson:"_id"`
+			TenantID         string `bson:"tenant_id"`
+			Email            string `bson:"email"`
+			EmailKey         string `bson:"email_key"`
+			Username         string `bson:"username"`
+			UsernameSkeleton string `bson:"username_skeleton"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("failed to decode user to rekey: %w", err)
+		}
+		key := domain.Email(doc.Email).Key()
+		skeleton := domain.Username(doc.Username).Skeleton()
+		if key == doc.EmailKey && skeleton == doc.UsernameSkeleton {
+			continue
+		}
+		_, err := collection.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": bson.M{"email_key": key, "username_skeleton": skeleton}})
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("user %s of tenant %s shares the email key %q with another user under the configured email rules", doc.ID, doc.TenantID, key)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to rekey user %s: %w", doc.ID, err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to read users to rekey: %w", err)
 	}
+
+	if err := checkEmailKeysUnique(ctx, collection); err != nil {
+		return err
+	}
+
+	_, err = versions.UpdateOne(ctx, bson.M{"_id": identifierKeysVersionID},
+		bson.M{"$set": bson.M{"version": version}}, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to record identifier keys version: %w", err)
+	}
+	return nil
+}
+
+// checkEmailKeysUnique finds keys shared by several users, which the unique
+// index cannot catch while it is being built for the first time.
+func checkEmailKeysUnique(ctx context.Context, collection *mongo.Collection) error {
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"tenant_id": "$tenant_id", "email_key": "$email_key"}, "count": bson.M{"$sum": 1}}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+		{{Key: "$limit", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check email keys: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var shared []struct {
+		ID struct {
+			TenantID string `bson:"tenant_id"`
+			EmailKey string `bson:"email_key"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := cursor.All(ctx, &shared); err != nil {
+		return fmt.Errorf("failed to check email keys: %w", err)
+	}
+	if len(shared) > 0 {
+		return fmt.Errorf("%d users of tenant %s share the email key %q under the configured email rules",
+			shared[0].Count, shared[0].ID.TenantID, shared[0].ID.EmailKey)
+	}
+	return nil
+}
+
+// dropIndex removes a legacy index, if it is still there.
+func dropIndex(ctx context.Context, collection *mongo.Collection, name string) error {
+	_, err := collection.Indexes().DropOne(ctx, name)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Name == "IndexNotFound" || cmdErr.Name == "NamespaceNotFound") {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to drop index %s: %w", name, err)
+	}
+	return nil
 }
 
 func (r *MongoUserRepository) Save(ctx context.Context, user *domain.User) error {
+	if user.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
 	mongoUser := mongoUser{
-		ID:       user.ID.String(),
-		Name:     user.Name,
-		Email:    user.Email,
-		Username: user.Username,
+		ID:                user.ID.String(),
+		TenantID:          user.TenantID.String(),
+		Name:              user.Name,
+		Email:             user.Email.String(),
+		EmailKey:          user.Email.Key(),
+		Username:          user.Username.String(),
+		UsernameSkeleton:  user.Username.Skeleton(),
+		EmailVerified:     user.EmailVerified,
+		EmailVerifiedAt:   user.EmailVerifiedAt,
+		PendingEmail:      user.PendingEmail.String(),
+		PasswordHash:      user.PasswordHash,
+		PasswordChangedAt: user.PasswordChangedAt,
+		Role:              user.Role.String(),
+		TOTP:              totpToMongo(user.TOTP),
+		RecoveryCodes:     user.RecoveryCodes,
+		WebAuthn:          webAuthnToMongo(user.WebAuthnCredentials),
+		SuspendedAt:       user.SuspendedAt,
+		Profile:           profileToMongo(user.Profile),
+		Avatar:            avatarToMongo(user.Avatar),
+		Attributes:        user.Attributes,
+		Tags:              user.Tags,
 	}
 
 	_, err := r.collection.InsertOne(ctx, mongoUser)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
-			return domain.ErrEmailExists
+			return duplicateUserError(err)
 		}
 		return fmt.Errorf("failed to save user: %w", err)
 	}
@@ -62,9 +289,13 @@ func (r *MongoUserRepository) Save(ctx context.Context, user *domain.User) error
 	return nil
 }
 
-func (r *MongoUserRepository) GetByID(ctx context.Context, id domain.UserID) (*domain.User, error) {
+func (r *MongoUserRepository) GetByID(ctx context.Context, tenant domain.TenantID, id domain.UserID) (*domain.User, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
 	var mongoUser mongoUser
-	err := r.collection.FindOne(ctx, bson.M{"_id": id.String()}).Decode(&mongoUser)
+	err := r.collection.FindOne(ctx, tenantFilter(tenant, "_id", id.String())).Decode(&mongoUser)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrUserNotFound
@@ -75,9 +306,13 @@ func (r *MongoUserRepository) GetByID(ctx context.Context, id domain.UserID) (*d
 	return r.mongoUserToDomain(&mongoUser), nil
 }
 
-func (r *MongoUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+func (r *MongoUserRepository) GetByEmail(ctx context.Context, tenant domain.TenantID, email domain.Email) (*domain.User, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
 	var mongoUser mongoUser
-	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&mongoUser)
+	err := r.collection.FindOne(ctx, tenantFilter(tenant, "email_key", email.Key())).Decode(&mongoUser)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrUserNotFound
@@ -88,9 +323,13 @@ func (r *MongoUserRepository) GetByEmail(ctx context.Context, email string) (*do
 	return r.mongoUserToDomain(&mongoUser), nil
 }
 
-func (r *MongoUserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+func (r *MongoUserRepository) GetByUsername(ctx context.Context, tenant domain.TenantID, username domain.Username) (*domain.User, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
 	var mongoUser mongoUser
-	err := r.collection.FindOne(ctx, bson.M{"username": username}).Decode(&mongoUser)
+	err := r.collection.FindOne(ctx, tenantFilter(tenant, "username", username.String())).Decode(&mongoUser)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrUserNotFound
@@ -101,8 +340,24 @@ func (r *MongoUserRepository) GetByUsername(ctx context.Context, username string
 	return r.mongoUserToDomain(&mongoUser), nil
 }
 
-func (r *MongoUserRepository) GetAll(ctx context.Context) ([]*domain.User, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+func (r *MongoUserRepository) GetAll(ctx context.Context, tenant domain.TenantID, filter domain.UserFilter) ([]*domain.User, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	query := bson.M{"tenant_id": tenant.String()}
+	for name, value := range filter.Attributes {
+		query["attributes."+name] = value
+	}
+	if len(filter.Tags) > 0 {
+		operator := "$in"
+		if filter.TagMatch == domain.TagMatchAll {
+			operator = "$all"
+		}
+		query["tags"] = bson.M{operator: filter.Tags}
+	}
+
+	cursor, err := r.collection.Find(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all users: %w", err)
 	}
@@ -125,18 +380,38 @@ func (r *MongoUserRepository) GetAll(ctx context.Context) ([]*domain.User, error
 }
 
 func (r *MongoUserRepository) Update(ctx context.Context, user *domain.User) error {
+	if user.TenantID == "" {
+		return domain.ErrTenantRequired
+	}
+
 	update := bson.M{
 		"$set": bson.M{
-			"name":     user.Name,
-			"email":    user.Email,
-			"username": user.Username,
+			"name":                 user.Name,
+			"email":                user.Email.String(),
+			"email_key":            user.Email.Key(),
+			"username":             user.Username.String(),
+			"username_skeleton":    user.Username.Skeleton(),
+			"email_verified":       user.EmailVerified,
+			"email_verified_at":    user.EmailVerifiedAt,
+			"pending_email":        user.PendingEmail.String(),
+			"password_hash":        user.PasswordHash,
+			"password_changed_at":  user.PasswordChangedAt,
+			"role":                 user.Role.String(),
+			"totp":                 totpToMongo(user.TOTP),
+			"recovery_codes":       user.RecoveryCodes,
+			"webauthn_credentials": webAuthnToMongo(user.WebAuthnCredentials),
+			"suspended_at":         user.SuspendedAt,
+			"profile":              profileToMongo(user.Profile),
+			"avatar":               avatarToMongo(user.Avatar),
+			"attributes":           user.Attributes,
+			"tags":                 user.Tags,
 		},
 	}
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": user.ID.String()}, update)
+	result, err := r.collection.UpdateOne(ctx, tenantFilter(user.TenantID, "_id", user.ID.String()), update)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
-			return domain.ErrEmailExists
+			return duplicateUserError(err)
 		}
 		return fmt.Errorf("failed to update user: %w", err)
 	}
@@ -148,8 +423,12 @@ func (r *MongoUserRepository) Update(ctx context.Context, user *domain.User) err
 	return nil
 }
 
-func (r *MongoUserRepository) Delete(ctx context.Context, id domain.UserID) error {
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id.String()})
+func (r *MongoUserRepository) Delete(ctx context.Context, tenant domain.TenantID, id domain.UserID) error {
+	if tenant == "" {
+		return domain.ErrTenantRequired
+	}
+
+	result, err := r.collection.DeleteOne(ctx, tenantFilter(tenant, "_id", id.String()))
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -161,8 +440,12 @@ func (r *MongoUserRepository) Delete(ctx context.Context, id domain.UserID) erro
 	return nil
 }
 
-func (r *MongoUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
-	count, err := r.collection.CountDocuments(ctx, bson.M{"email": email})
+func (r *MongoUserRepository) ExistsByEmail(ctx context.Context, tenant domain.TenantID, email domain.Email) (bool, error) {
+	if tenant == "" {
+		return false, domain.ErrTenantRequired
+	}
+
+	count, err := r.collection.CountDocuments(ctx, tenantFilter(tenant, "email_key", email.Key()))
 	if err != nil {
 		return false, fmt.Errorf("failed to check email existence: %w", err)
 	}
@@ -170,8 +453,12 @@ func (r *MongoUserRepository) ExistsByEmail(ctx context.Context, email string) (
 	return count > 0, nil
 }
 
-func (r *MongoUserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
-	count, err := r.collection.CountDocuments(ctx, bson.M{"username": username})
+func (r *MongoUserRepository) ExistsByUsername(ctx context.Context, tenant domain.TenantID, username domain.Username) (bool, error) {
+	if tenant == "" {
+		return false, domain.ErrTenantRequired
+	}
+
+	count, err := r.collection.CountDocuments(ctx, tenantFilter(tenant, "username", username.String()))
 	if err != nil {
 		return false, fmt.Errorf("failed to check username existence: %w", err)
 	}
@@ -179,11 +466,223 @@ func (r *MongoUserRepository) ExistsByUsername(ctx context.Context, username str
 	return count > 0, nil
 }
 
+func (r *MongoUserRepository) FindByUsernameSkeleton(ctx context.Context, tenant domain.TenantID, skeleton string) ([]*domain.User, error) {
+	if tenant == "" {
+		return nil, domain.ErrTenantRequired
+	}
+
+	cursor, err := r.collection.Find(ctx, tenantFilter(tenant, "username_skeleton", skeleton))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users by username skeleton: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []*domain.User
+	for cursor.Next(ctx) {
+		var mongoUser mongoUser
+		if err := cursor.Decode(&mongoUser); err != nil {
+			return nil, fmt.Errorf("failed to decode user: %w", err)
+		}
+		users = append(users, r.mongoUserToDomain(&mongoUser))
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %w", err)
+	}
+
+	return users, nil
+}
+
 func (r *MongoUserRepository) mongoUserToDomain(mongoUser *mongoUser) *domain.User {
-	return &domain.User{
-		ID:       domain.UserID(mongoUser.ID),
-		Name:     mongoUser.Name,
-		Email:    mongoUser.Email,
-		Username: mongoUser.Username,
+	// Users stored before roles existed are plain users.
+	role := domain.Role(mongoUser.Role)
+	if role == "" {
+		role = domain.RoleUser
+	}
+
+	user := &domain.User{
+		ID:                domain.UserID(mongoUser.ID),
+		TenantID:          domain.TenantID(mongoUser.TenantID),
+		Name:              mongoUser.Name,
+		Email:             domain.Email(mongoUser.Email),
+		Username:          domain.Username(mongoUser.Username),
+		EmailVerified:     mongoUser.EmailVerified,
+		EmailVerifiedAt:   mongoUser.EmailVerifiedAt,
+		PendingEmail:      domain.Email(mongoUser.PendingEmail),
+		PasswordHash:      mongoUser.PasswordHash,
+		PasswordChangedAt: mongoUser.PasswordChangedAt,
+		Role:              role,
+		RecoveryCodes:     mongoUser.RecoveryCodes,
+		SuspendedAt:       mongoUser.SuspendedAt,
+		Attributes:        mongoUser.Attributes,
+		Tags:              mongoUser.Tags,
+		Profile: domain.Profile{
+			DisplayName: mongoUser.Profile.DisplayName,
+			GivenName:   mongoUser.Profile.GivenName,
+			FamilyName:  mongoUser.Profile.FamilyName,
+			Phone:       mongoUser.Profile.Phone,
+			Locale:      mongoUser.Profile.Locale,
+			Timezone:    mongoUser.Profile.Timezone,
+			Bio:         mongoUser.Profile.Bio,
+			AvatarURL:   mongoUser.Profile.AvatarURL,
+		},
+	}
+	if mongoUser.Avatar != nil {
+		user.Avatar = &domain.Avatar{
+			ID:          mongoUser.Avatar.ID,
+			ContentType: mongoUser.Avatar.ContentType,
+			Sizes:       mongoUser.Avatar.Sizes,
+			UploadedAt:  mongoUser.Avatar.UploadedAt,
+		}
+	}
+	if mongoUser.TOTP != nil {
+		user.TOTP = &domain.TOTPFactor{
+			Secret:       mongoUser.TOTP.Secret,
+			Confirmed:    mongoUser.TOTP.Confirmed,
+			ConfirmedAt:  mongoUser.TOTP.ConfirmedAt,
+			LastUsedStep: mongoUser.TOTP.LastUsedStep,
+		}
+	}
+	for _, credential := range mongoUser.WebAuthn {
+		user.WebAuthnCredentials = append(user.WebAuthnCredentials, domain.WebAuthnCredential{
+			ID:              credential.ID,
+			Name:            credential.Name,
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Transports:      credential.Transports,
+			AAGUID:          credential.AAGUID,
+			SignCount:       credential.SignCount,
+			BackupEligible:  credential.BackupEligible,
+			BackupState:     credential.BackupState,
+			CreatedAt:       credential.CreatedAt,
+			LastUsedAt:      credential.LastUsedAt,
+		})
+	}
+	return user
+}
+
+func (r *MongoUserRepository) UnsetAttribute(ctx context.Context, tenant domain.TenantID, name string) error {
+	if tenant == "" {
+		return domain.ErrTenantRequired
+	}
+
+	field := "attributes." + name
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"tenant_id": tenant.String(), field: bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{field: ""}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to unset attribute: %w", err)
+	}
+	return nil
+}
+
+// EnsureUniqueAttribute creates a unique index over the attribute for the
+// tenant's users only; other tenants may define the same name differently.
+func (r *MongoUserRepository) EnsureUniqueAttribute(ctx context.Context, tenant domain.TenantID, name string) error {
+	field := "attributes." + name
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: field, Value: 1}},
+		Options: options.Index().
+			SetName(attributeIndexName(tenant, name)).
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"tenant_id": tenant.String(), field: bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create attribute index: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoUserRepository) DropUniqueAttribute(ctx context.Context, tenant domain.TenantID, name string) error {
+	return dropIndex(ctx, r.collection, attributeIndexName(tenant, name))
+}
+
+const attributeIndexPrefix = "unique_attribute:"
+
+// attributeIndexPattern finds the attribute name in a duplicate key error
+// raised by an index from attributeIndexName.
+var attributeIndexPattern = regexp.MustCompile(regexp.QuoteMeta(attributeIndexPrefix) + `[a-z0-9-]+:([a-z0-9_]+)`)
+
+func attributeIndexName(tenant domain.TenantID, name string) string {
+	return attributeIndexPrefix + tenant.String() + ":" + name
+}
+
+// usernameIndexName is the default name Mongo gives the per-tenant username
+// index, so naming it explicitly keeps existing deployments' index.
+const usernameIndexName = "tenant_id_1_username_1"
+
+// duplicateUserError tells which unique index a write broke: a custom
+// attribute's, the username's, or otherwise the email's.
+func duplicateUserError(err error) error {
+	if match := attributeIndexPattern.FindStringSubmatch(err.Error()); match != nil {
+		return domain.AttributeValueExists(match[1])
+	}
+	if strings.Contains(err.Error(), "index: "+usernameIndexName+" ") {
+		return domain.ErrUsernameExists
+	}
+	return domain.ErrEmailExists
+}
+
+// tenantFilter matches the document with the given field value, but only
+// within tenant.
+func tenantFilter(tenant domain.TenantID, field string, value any) bson.M {
+	return bson.M{"tenant_id": tenant.String(), field: value}
+}
+
+func profileToMongo(profile domain.Profile) mongoProfile {
+	return mongoProfile{
+		DisplayName: profile.DisplayName,
+		GivenName:   profile.GivenName,
+		FamilyName:  profile.FamilyName,
+		Phone:       profile.Phone,
+		Locale:      profile.Locale,
+		Timezone:    profile.Timezone,
+		Bio:         profile.Bio,
+		AvatarURL:   profile.AvatarURL,
+	}
+}
+
+func avatarToMongo(avatar *domain.Avatar) *mongoAvatar {
+	if avatar == nil {
+		return nil
+	}
+	return &mongoAvatar{
+		ID:          avatar.ID,
+		ContentType: avatar.ContentType,
+		Sizes:       avatar.Sizes,
+		UploadedAt:  avatar.UploadedAt,
+	}
+}
+
+func webAuthnToMongo(credentials []domain.WebAuthnCredential) []mongoWebAuthnCredential {
+	docs := make([]mongoWebAuthnCredential, len(credentials))
+	for i, credential := range credentials {
+		docs[i] = mongoWebAuthnCredential{
+			ID:              credential.ID,
+			Name:            credential.Name,
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Transports:      credential.Transports,
+			AAGUID:          credential.AAGUID,
+			SignCount:       credential.SignCount,
+			BackupEligible:  credential.BackupEligible,
+			BackupState:     credential.BackupState,
+			CreatedAt:       credential.CreatedAt,
+			LastUsedAt:      credential.LastUsedAt,
+		}
+	}
+	return docs
+}
+
+func totpToMongo(totp *domain.TOTPFactor) *mongoTOTP {
+	if totp == nil {
+		return nil
+	}
+	return &mongoTOTP{
+		Secret:       totp.Secret,
+		Confirmed:    totp.Confirmed,
+		ConfirmedAt:  totp.ConfirmedAt,
+		LastUsedStep: totp.LastUsedStep,
 	}
 }
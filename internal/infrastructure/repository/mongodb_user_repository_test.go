@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"ddd-user-service/internal/domain"
+	"errors"
+	"testing"
+)
+
+func TestDuplicateUserError(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    error
+	}{
+		{
+			"email",
+			`E11000 duplicate key error collection: users.users index: tenant_id_1_email_key_1 dup key: { tenant_id: "default", email_key: "jane@example.com" }`,
+			domain.ErrEmailExists,
+		},
+		{
+			"username",
+			`E11000 duplicate key error collection: users.users index: tenant_id_1_username_1 dup key: { tenant_id: "default", username: "jane" }`,
+			domain.ErrUsernameExists,
+		},
+		{
+			"unique attribute",
+			`E11000 duplicate key error collection: users.users index: unique_attribute:default:employee_no dup key: { tenant_id: "default", attributes.employee_no: "E1" }`,
+			domain.ErrAttributeValueExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := duplicateUserError(errors.New(tt.message)); !errors.Is(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/application/passkey"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoWebAuthnCeremonyStore struct {
+	collection *mongo.Collection
+}
+
+type mongoWebAuthnCeremony struct {
+	ID        string    `bson:"_id"`
+	Kind      string    `bson:"kind"`
+	UserID    string    `bson:"user_id,omitempty"`
+	Name      string    `bson:"name,omitempty"`
+	Session   []byte    `bson:"session"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+func NewMongoWebAuthnCeremonyStore(db *mongo.Database) *MongoWebAuthnCeremonyStore {
+	collection := db.Collection("webauthn_ceremonies")
+
+	collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.M{"expires_at": 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+
+	return &MongoWebAuthnCeremonyStore{
+		collection: collection,
+	}
+}
+
+func (s *MongoWebAuthnCeremonyStore) Save(ctx context.Context, ceremony *passkey.Ceremony) error {
+	doc := mongoWebAuthnCeremony{
+		ID:        ceremony.ID,
+		Kind:      ceremony.Kind,
+		UserID:    ceremony.UserID,
+		Name:      ceremony.Name,
+		Session:   ceremony.Session,
+		ExpiresAt: ceremony.ExpiresAt,
+	}
+
+	if _, err := s.collection.InsertOne(ctx, doc); err != nil {
+		return fmt.Errorf("failed to save WebAuthn ceremony: %w", err)
+	}
+
+	return nil
+}
+
+func (s *MongoWebAuthnCeremonyStore) Take(ctx context.Context, id string) (*passkey.Ceremony, error) {
+	var doc mongoWebAuthnCeremony
+	err := s.collection.FindOneAndDelete(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, passkey.ErrCeremonyNotFound
+		}
+		return nil, fmt.Errorf("failed to take WebAuthn ceremony: %w", err)
+	}
+
+	return &passkey.Ceremony{
+		ID:        doc.ID,
+		Kind:      doc.Kind,
+		UserID:    doc.UserID,
+		Name:      doc.Name,
+		Session:   doc.Session,
+		ExpiresAt: doc.ExpiresAt,
+	}, nil
+}
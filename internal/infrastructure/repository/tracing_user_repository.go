@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const repositoryTracerName = "ddd-user-service/internal/infrastructure/repository"
+
+// TracingUserRepository decorates a UserRepository with one span per call.
+type TracingUserRepository struct {
+	next   domain.UserRepository
+	tracer trace.Tracer
+}
+
+func NewTracingUserRepository(next domain.UserRepository) *TracingUserRepository {
+	return &TracingUserRepository{
+		next:   next,
+		tracer: otel.Tracer(repositoryTracerName),
+	}
+}
+
+func (r *TracingUserRepository) start(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return r.tracer.Start(ctx, "UserRepository."+op,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func tenantAttribute(tenant domain.TenantID) attribute.KeyValue {
+	return attribute.String("tenant.id", tenant.String())
+}
+
+func endSpan(span trace.Span, err error) {
+	// Not-found is an expected outcome, not a failure of the repository.
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (r *TracingUserRepository) Save(ctx context.Context, user *domain.User) (err error) {
+	ctx, span := r.start(ctx, "Save", tenantAttribute(user.TenantID), attribute.String("user.id", user.ID.String()))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.Save(ctx, user)
+}
+
+func (r *TracingUserRepository) GetByID(ctx context.Context, tenant domain.TenantID, id domain.UserID) (_ *domain.User, err error) {
+	ctx, span := r.start(ctx, "GetByID", tenantAttribute(tenant), attribute.String("user.id", id.String()))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.GetByID(ctx, tenant, id)
+}
+
+func (r *TracingUserRepository) GetByEmail(ctx context.Context, tenant domain.TenantID, email domain.Email) (_ *domain.User, err error) {
+	ctx, span := r.start(ctx, "GetByEmail", tenantAttribute(tenant))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.GetByEmail(ctx, tenant, email)
+}
+
+func (r *TracingUserRepository) GetByUsername(ctx context.Context, tenant domain.TenantID, username domain.Username) (_ *domain.User, err error) {
+	ctx, span := r.start(ctx, "GetByUsername", tenantAttribute(tenant))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.GetByUsername(ctx, tenant, username)
+}
+
+func (r *TracingUserRepository) GetAll(ctx context.Context, tenant domain.TenantID, filter domain.UserFilter) (users []*domain.User, err error) {
+	ctx, span := r.start(ctx, "GetAll", tenantAttribute(tenant))
+	defer func() {
+		span.SetAttributes(attribute.Int("user.count", len(users)))
+		endSpan(span, err)
+	}()
+
+	return r.next.GetAll(ctx, tenant, filter)
+}
+
+func (r *TracingUserRepository) Update(ctx context.Context, user *domain.User) (err error) {
+	ctx, span := r.start(ctx, "Update", tenantAttribute(user.TenantID), attribute.String("user.id", user.ID.String()))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.Update(ctx, user)
+}
+
+func (r *TracingUserRepository) Delete(ctx context.Context, tenant domain.TenantID, id domain.UserID) (err error) {
+	ctx, span := r.start(ctx, "Delete", tenantAttribute(tenant), attribute.String("user.id", id.String()))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.Delete(ctx, tenant, id)
+}
+
+func (r *TracingUserRepository) ExistsByEmail(ctx context.Context, tenant domain.TenantID, email domain.Email) (exists bool, err error) {
+	ctx, span := r.start(ctx, "ExistsByEmail", tenantAttribute(tenant))
+	defer func() {
+		span.SetAttributes(attribute.Bool("user.exists", exists))
+		endSpan(span, err)
+	}()
+
+	return r.next.ExistsByEmail(ctx, tenant, email)
+}
+
+func (r *TracingUserRepository) ExistsByUsername(ctx context.Context, tenant domain.TenantID, username domain.Username) (exists bool, err error) {
+	ctx, span := r.start(ctx, "ExistsByUsername", tenantAttribute(tenant))
+	defer func() {
+		span.SetAttributes(attribute.Bool("user.exists", exists))
+		endSpan(span, err)
+	}()
+
+	return r.next.ExistsByUsername(ctx, tenant, username)
+}
+
+func (r *TracingUserRepository) FindByUsernameSkeleton(ctx context.Context, tenant domain.TenantID, skeleton string) (users []*domain.User, err error) {
+	ctx, span := r.start(ctx, "FindByUsernameSkeleton", tenantAttribute(tenant))
+	defer func() {
+		span.SetAttributes(attribute.Int("user.count", len(users)))
+		endSpan(span, err)
+	}()
+
+	return r.next.FindByUsernameSkeleton(ctx, tenant, skeleton)
+}
+
+func (r *TracingUserRepository) UnsetAttribute(ctx context.Context, tenant domain.TenantID, name string) (err error) {
+	ctx, span := r.start(ctx, "UnsetAttribute", tenantAttribute(tenant), attribute.String("attribute", name))
+	defer func() { endSpan(span, err) }()
+
+	return r.next.UnsetAttribute(ctx, tenant, name)
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"ddd-user-service/internal/domain"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingUserRepositorySpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(provider) })
+
+	failing := &failingSaves{UserRepository: NewMemoryUserRepository()}
+	users := NewTracingUserRepository(failing)
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+
+	if _, err := users.GetByID(ctx, domain.DefaultTenantID, "missing"); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("GetByID err = %v, want %v", err, domain.ErrUserNotFound)
+	}
+	user, err := domain.NewUser(domain.DefaultTenantID, "Jane", "jane@example.com", "jane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Save(ctx, user); !errors.Is(err, errSaveFailed) {
+		t.Fatalf("Save err = %v, want %v", err, errSaveFailed)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("%d spans ended, want 3", len(spans))
+	}
+	get, save := spans[0], spans[1]
+	for _, span := range []sdktrace.ReadOnlySpan{get, save} {
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Fatalf("%s is not a child of the request span", span.Name())
+		}
+	}
+	if get.Name() != "UserRepository.GetByID" || get.Status().Code == codes.Error {
+		t.Fatalf("%s status = %v, want a not-found lookup not to be an error", get.Name(), get.Status())
+	}
+	if save.Name() != "UserRepository.Save" || save.Status().Code != codes.Error || len(save.Events()) != 1 {
+		t.Fatalf("%s status = %v with %d events, want a recorded error", save.Name(), save.Status(), len(save.Events()))
+	}
+}
+
+var errSaveFailed = errors.New("save failed")
+
+type failingSaves struct {
+	domain.UserRepository
+}
+
+func (r *failingSaves) Save(context.Context, *domain.User) error {
+	return errSaveFailed
+}
@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const mongoTracerName = "ddd-user-service/internal/infrastructure/tracing/mongo"
+
+type spanKey struct {
+	connectionID string
+	requestID    int64
+}
+
+// NewMongoCommandMonitor returns a command monitor that records every Mongo
+// command as a client span, parented to the span carried by the operation's
+// context (normally the repository span).
+func NewMongoCommandMonitor() *event.CommandMonitor {
+	var (
+		mu    sync.Mutex
+		spans = make(map[spanKey]trace.Span)
+	)
+
+	finish := func(evt event.CommandFinishedEvent, failure string) {
+		key := spanKey{connectionID: evt.ConnectionID, requestID: evt.RequestID}
+		mu.Lock()
+		span, ok := spans[key]
+		delete(spans, key)
+		mu.Unlock()
+		if !ok {
+			return
+		}
+		if failure != "" {
+			span.SetStatus(codes.Error, failure)
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			collection := ""
+			if value, err := evt.Command.LookupErr(evt.CommandName); err == nil {
+				collection, _ = value.StringValueOK()
+			}
+			_, span := otel.Tracer(mongoTracerName).Start(ctx, "mongodb."+evt.CommandName,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.DBSystemMongoDB,
+					semconv.DBNamespace(evt.DatabaseName),
+					semconv.DBOperationName(evt.CommandName),
+					semconv.DBCollectionName(collection),
+				),
+			)
+			mu.Lock()
+			spans[spanKey{connectionID: evt.ConnectionID, requestID: evt.RequestID}] = span
+			mu.Unlock()
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			finish(evt.CommandFinishedEvent, "")
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			finish(evt.CommandFinishedEvent, evt.Failure)
+		},
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/service"
+	"ddd-user-service/internal/interfaces/http/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, err := h.apiKeyService.CreateAPIKey(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	resp, err := h.apiKeyService.ListAPIKeys(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	var req dto.RotateAPIKeyRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			problem.FromBindingError(c, err)
+			return
+		}
+	}
+
+	resp, err := h.apiKeyService.RotateAPIKey(c.Request.Context(), c.Param("id"), c.Param("kid"), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	if err := h.apiKeyService.RevokeAPIKey(c.Request.Context(), c.Param("id"), c.Param("kid")); err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/service"
+	"ddd-user-service/internal/interfaces/http/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttributeHandler struct {
+	attributeService *service.AttributeService
+}
+
+func NewAttributeHandler(attributeService *service.AttributeService) *AttributeHandler {
+	return &AttributeHandler{
+		attributeService: attributeService,
+	}
+}
+
+func (h *AttributeHandler) ListAttributes(c *gin.Context) {
+	resp, err := h.attributeService.ListAttributes(c.Request.Context())
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AttributeHandler) GetAttribute(c *gin.Context) {
+	resp, err := h.attributeService.GetAttribute(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AttributeHandler) CreateAttribute(c *gin.Context) {
+	var req dto.CreateAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, err := h.attributeService.CreateAttribute(c.Request.Context(), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *AttributeHandler) DeleteAttribute(c *gin.Context) {
+	if err := h.attributeService.DeleteAttribute(c.Request.Context(), c.Param("name")); err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/service"
+	"ddd-user-service/internal/interfaces/http/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+func (h *AuditHandler) ListEntries(c *gin.Context) {
+	query := dto.AuditQuery{
+		UserID: c.Query("user_id"),
+		Limit:  c.Query("limit"),
+		Before: c.Query("before"),
+	}
+	resp, err := h.auditService.ListAuditEntries(c.Request.Context(), query)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AuditHandler) VerifyChain(c *gin.Context) {
+	resp, err := h.auditService.VerifyAuditChain(c.Request.Context())
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/service"
+	"ddd-user-service/internal/interfaces/http/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	authService *service.AuthService
+}
+
+func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+// ForgotPassword always answers 202 so it cannot be used to find out which
+// addresses are registered.
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	if err := h.authService.ForgotPassword(c.Request.Context(), req); err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.AcceptedResponse{
+		Status:  "accepted",
+		Message: "If an account exists for this address, a password reset email is on its way.",
+	})
+}
+
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	if err := h.authService.ResetPassword(c.Request.Context(), req); err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, err := h.authService.Login(c.Request.Context(), req, clientInfo(c))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AuthHandler) CompleteMFALogin(c *gin.Context) {
+	var req dto.LoginMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, err := h.authService.CompleteMFALogin(c.Request.Context(), req, clientInfo(c))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AuthHandler) StartTOTPEnrollment(c *gin.Context) {
+	resp, err := h.authService.StartTOTPEnrollment(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AuthHandler) ConfirmTOTPEnrollment(c *gin.Context) {
+	var req dto.ConfirmTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, err := h.authService.ConfirmTOTPEnrollment(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	resp, err := h.authService.RegenerateRecoveryCodes(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AuthHandler) BeginWebAuthnRegistration(c *gin.Context) {
+	var req dto.BeginWebAuthnRegistrationRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			problem.FromBindingError(c, err)
+			return
+		}
+	}
+
+	resp, err := h.authService.BeginWebAuthnRegistration(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AuthHandler) FinishWebAuthnRegistration(c *gin.Context) {
+	var req dto.FinishWebAuthnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, err := h.authService.FinishWebAuthnRegistration(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *AuthHandler) ListWebAuthnCredentials(c *gin.Context) {
+	resp, err := h.authService.ListWebAuthnCredentials(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AuthHandler) RenameWebAuthnCredential(c *gin.Context) {
+	var req dto.RenameWebAuthnCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, err := h.authService.RenameWebAuthnCredential(c.Request.Context(), c.Param("id"), c.Param("cid"), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AuthHandler) DeleteWebAuthnCredential(c *gin.Context) {
+	if err := h.authService.DeleteWebAuthnCredential(c.Request.Context(), c.Param("id"), c.Param("cid")); err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) BeginWebAuthnLogin(c *gin.Context) {
+	var req dto.BeginWebAuthnLoginRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			problem.FromBindingError(c, err)
+			return
+		}
+	}
+
+	resp, err := h.authService.BeginWebAuthnLogin(c.Request.Context(), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AuthHandler) FinishWebAuthnLogin(c *gin.Context) {
+	var req dto.FinishWebAuthnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, err := h.authService.FinishWebAuthnLogin(c.Request.Context(), req, clientInfo(c))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AuthHandler) UnlockUser(c *gin.Context) {
+	if err := h.authService.UnlockUser(c.Request.Context(), c.Param("id")); err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"ddd-user-service/internal/application/service"
+	"ddd-user-service/internal/interfaces/http/problem"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead allows for the form framing around the uploaded file.
+const multipartOverhead = 64 << 10
+
+type AvatarHandler struct {
+	avatarService *service.AvatarService
+}
+
+func NewAvatarHandler(avatarService *service.AvatarService) *AvatarHandler {
+	return &AvatarHandler{
+		avatarService: avatarService,
+	}
+}
+
+// UploadAvatar takes the picture from the "file" field of a multipart form.
+func (h *AvatarHandler) UploadAvatar(c *gin.Context) {
+	if c.ContentType() != gin.MIMEMultipartPOSTForm {
+		problem.Write(c, http.StatusUnsupportedMediaType, problem.CodeUnsupportedMediaType,
+			"the avatar must be uploaded as "+gin.MIMEMultipartPOSTForm)
+		return
+	}
+
+	maxBytes := h.avatarService.MaxUploadBytes()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			problem.Write(c, http.StatusRequestEntityTooLarge, problem.CodePayloadTooLarge,
+				"the avatar must be at most "+strconv.FormatInt(maxBytes, 10)+" bytes")
+		case errors.Is(err, http.ErrMissingFile):
+			problem.MissingParameter(c, "file")
+		default:
+			problem.Write(c, http.StatusBadRequest, problem.CodeInvalidRequestBody, "the request body is not a valid multipart form")
+		}
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+	defer file.Close()
+
+	// One byte over the limit is enough for the service to reject it.
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	resp, err := h.avatarService.UploadAvatar(c.Request.Context(), c.Param("id"), data)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ServeAvatar serves an avatar image. The images behind a URL never change,
+// so they may be cached indefinitely and revalidation needs no lookup.
+func (h *AvatarHandler) ServeAvatar(c *gin.Context) {
+	etag := `"` + c.Param("id") + "-" + c.Param("file") + `"`
+	if match := c.GetHeader("If-None-Match"); match == "*" || strings.Contains(match, etag) {
+		c.Header("ETag", etag)
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	obj, err := h.avatarService.GetAvatarImage(c.Request.Context(), c.Param("tenant"), c.Param("id"), c.Param("file"))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("ETag", etag)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, obj.ContentType, obj.Data)
+}
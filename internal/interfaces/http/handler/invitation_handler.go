@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/service"
+	"ddd-user-service/internal/interfaces/http/middleware"
+	"ddd-user-service/internal/interfaces/http/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvitationHandler struct {
+	invitationService *service.InvitationService
+}
+
+func NewInvitationHandler(invitationService *service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+	}
+}
+
+// CreateInvitation answers 201 for a new invitation and 200 when an open
+// one for the same address was resent instead.
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	var req dto.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	var invitedBy string
+	if principal := middleware.GetPrincipal(c); principal != nil {
+		invitedBy = principal.UserID
+	}
+
+	resp, created, err := h.invitationService.CreateInvitation(c.Request.Context(), invitedBy, req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, resp)
+}
+
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	resp, err := h.invitationService.ListInvitations(c.Request.Context())
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *InvitationHandler) GetInvitation(c *gin.Context) {
+	resp, err := h.invitationService.GetInvitation(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
+	resp, err := h.invitationService.ResendInvitation(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	resp, err := h.invitationService.RevokeInvitation(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AcceptInvitation answers 201 when it creates the account and 200 when it
+// attached to an existing one or the invitation was already accepted.
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var req dto.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, created, err := h.invitationService.AcceptInvitation(c.Request.Context(), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, resp)
+}
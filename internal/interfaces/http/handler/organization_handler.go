@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/service"
+	"ddd-user-service/internal/interfaces/http/middleware"
+	"ddd-user-service/internal/interfaces/http/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHandler serves the organization routes, which all require an
+// authenticated caller.
+type OrganizationHandler struct {
+	orgService *service.OrganizationService
+}
+
+func NewOrganizationHandler(orgService *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService: orgService,
+	}
+}
+
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var req dto.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, err := h.orgService.CreateOrganization(c.Request.Context(), middleware.GetPrincipal(c), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	resp, err := h.orgService.GetOrganization(c.Request.Context(), middleware.GetPrincipal(c), c.Param("oid"))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
+	if err := h.orgService.DeleteOrganization(c.Request.Context(), middleware.GetPrincipal(c), c.Param("oid")); err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	var req dto.AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, err := h.orgService.AddMember(c.Request.Context(), middleware.GetPrincipal(c), c.Param("oid"), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *OrganizationHandler) InviteMember(c *gin.Context) {
+	var req dto.AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, err := h.orgService.InviteMember(c.Request.Context(), middleware.GetPrincipal(c), c.Param("oid"), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *OrganizationHandler) AcceptInvitation(c *gin.Context) {
+	resp, err := h.orgService.AcceptInvitation(c.Request.Context(), middleware.GetPrincipal(c), c.Param("oid"))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *OrganizationHandler) ChangeMemberRole(c *gin.Context) {
+	var req dto.ChangeMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, err := h.orgService.ChangeMemberRole(c.Request.Context(), middleware.GetPrincipal(c), c.Param("oid"), c.Param("uid"), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	if err := h.orgService.RemoveMember(c.Request.Context(), middleware.GetPrincipal(c), c.Param("oid"), c.Param("uid")); err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *OrganizationHandler) CreateTeam(c *gin.Context) {
+	var req dto.CreateTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, err := h.orgService.CreateTeam(c.Request.Context(), middleware.GetPrincipal(c), c.Param("oid"), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *OrganizationHandler) DeleteTeam(c *gin.Context) {
+	if err := h.orgService.DeleteTeam(c.Request.Context(), middleware.GetPrincipal(c), c.Param("oid"), c.Param("tid")); err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *OrganizationHandler) AddTeamMember(c *gin.Context) {
+	if err := h.orgService.AddTeamMember(c.Request.Context(), middleware.GetPrincipal(c), c.Param("oid"), c.Param("tid"), c.Param("uid")); err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *OrganizationHandler) RemoveTeamMember(c *gin.Context) {
+	if err := h.orgService.RemoveTeamMember(c.Request.Context(), middleware.GetPrincipal(c), c.Param("oid"), c.Param("tid"), c.Param("uid")); err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *OrganizationHandler) ListUserOrganizations(c *gin.Context) {
+	resp, err := h.orgService.ListUserOrganizations(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
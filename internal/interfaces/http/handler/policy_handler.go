@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/service"
+	"ddd-user-service/internal/interfaces/http/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PolicyHandler struct {
+	policyService *service.PolicyService
+}
+
+func NewPolicyHandler(policyService *service.PolicyService) *PolicyHandler {
+	return &PolicyHandler{
+		policyService: policyService,
+	}
+}
+
+func (h *PolicyHandler) ListEntries(c *gin.Context) {
+	resp, err := h.policyService.ListPolicyEntries(c.Request.Context(), c.Param("list"))
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AddEntry answers 201 for a new entry and 200 when the value was already
+// on the list.
+func (h *PolicyHandler) AddEntry(c *gin.Context) {
+	var req dto.AddPolicyEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	resp, created, err := h.policyService.AddPolicyEntry(c.Request.Context(), c.Param("list"), req)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, resp)
+}
+
+func (h *PolicyHandler) RemoveEntry(c *gin.Context) {
+	if err := h.policyService.RemovePolicyEntry(c.Request.Context(), c.Param("list"), c.Param("value")); err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
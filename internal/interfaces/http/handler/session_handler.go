@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/service"
+	"ddd-user-service/internal/interfaces/http/middleware"
+	"ddd-user-service/internal/interfaces/http/problem"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	sessionService *service.SessionService
+}
+
+func NewSessionHandler(sessionService *service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	var currentID string
+	if principal := middleware.GetPrincipal(c); principal != nil {
+		currentID = principal.SessionID
+	}
+
+	resp, err := h.sessionService.ListSessions(c.Request.Context(), c.Param("id"), currentID)
+	if err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	if err := h.sessionService.RevokeSession(c.Request.Context(), c.Param("id"), c.Param("sid")); err != nil {
+		problem.FromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// clientInfo identifies the device a sign-in comes from.
+func clientInfo(c *gin.Context) dto.ClientInfo {
+	return dto.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
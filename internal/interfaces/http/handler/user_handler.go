@@ -2,32 +2,71 @@ package handler
 
 import (
 	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/patch"
 	"ddd-user-service/internal/application/service"
-	"ddd-user-service/internal/domain"
-	"errors"
+	"ddd-user-service/internal/interfaces/http/middleware"
+	"ddd-user-service/internal/interfaces/http/problem"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UserHandler struct {
-	userService *service.UserService
+	userService         *service.UserService
+	privateRegistration bool
 }
 
-func NewUserHandler(userService *service.UserService) *UserHandler {
+// NewUserHandler builds the user handler. With privateRegistration, public
+// registration never reveals whether an email or username is taken.
+func NewUserHandler(userService *service.UserService, privateRegistration bool) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:         userService,
+		privateRegistration: privateRegistration,
 	}
 }
 
+// RegisterUser is public self-service registration. In explicit mode it
+// behaves like CreateUser, except that callers cannot choose a role.
+func (h *UserHandler) RegisterUser(c *gin.Context) {
+	var req dto.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+	if req.Role != "" {
+		h.handleError(c, service.ErrReadOnlyField.WithField("role"))
+		return
+	}
+
+	if !h.privateRegistration {
+		h.createUser(c, req)
+		return
+	}
+
+	if err := h.userService.RegisterUser(c.Request.Context(), req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.AcceptedResponse{
+		Status:  "accepted",
+		Message: "Check your email to continue.",
+	})
+}
+
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req dto.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.FromBindingError(c, err)
 		return
 	}
 
-	user, err := h.userService.CreateUser(c.Request.Context(), req)
+	h.createUser(c, req)
+}
+
+func (h *UserHandler) createUser(c *gin.Context, req dto.CreateUserRequest) {
+	user, err := h.userService.CreateUser(c.Request.Context(), middleware.GetPrincipal(c), req)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -39,7 +78,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 func (h *UserHandler) GetUser(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		problem.MissingParameter(c, "id")
 		return
 	}
 
@@ -53,7 +92,12 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 }
 
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	users, err := h.userService.GetAllUsers(c.Request.Context())
+	query := dto.ListUsersQuery{
+		Attributes: c.QueryMap("attributes"),
+		Tags:       c.QueryArray("tag"),
+		TagMatch:   c.Query("tag_match"),
+	}
+	users, err := h.userService.GetAllUsers(c.Request.Context(), query)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -62,20 +106,117 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"users": users})
 }
 
-func (h *UserHandler) UpdateUser(c *gin.Context) {
+func (h *UserHandler) ReplaceUser(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		problem.MissingParameter(c, "id")
 		return
 	}
 
-	var req dto.UpdateUserRequest
+	var req dto.ReplaceUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		problem.FromBindingError(c, err)
 		return
 	}
 
-	user, err := h.userService.UpdateUser(c.Request.Context(), id, req)
+	user, err := h.userService.ReplaceUser(c.Request.Context(), id, req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *UserHandler) PatchUser(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		problem.MissingParameter(c, "id")
+		return
+	}
+
+	format, body, ok := readPatch(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.PatchUser(c.Request.Context(), id, format, body)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// readPatch reads a merge patch or JSON patch body, answering the request
+// itself when it is neither.
+func readPatch(c *gin.Context) (patch.Format, []byte, bool) {
+	format := patch.Format(c.ContentType())
+	if format != patch.FormatMergePatch && format != patch.FormatJSONPatch {
+		c.Header("Accept-Patch", string(patch.FormatMergePatch)+", "+string(patch.FormatJSONPatch))
+		problem.Write(c, http.StatusUnsupportedMediaType, problem.CodeUnsupportedMediaType,
+			"PATCH requires "+string(patch.FormatMergePatch)+" or "+string(patch.FormatJSONPatch))
+		return "", nil, false
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		problem.FromBindingError(c, err)
+		return "", nil, false
+	}
+	return format, body, true
+}
+
+func (h *UserHandler) GetProfile(c *gin.Context) {
+	profile, err := h.userService.GetProfile(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *UserHandler) ReplaceProfile(c *gin.Context) {
+	var req dto.ProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	profile, err := h.userService.ReplaceProfile(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *UserHandler) PatchProfile(c *gin.Context) {
+	format, body, ok := readPatch(c)
+	if !ok {
+		return
+	}
+
+	profile, err := h.userService.PatchProfile(c.Request.Context(), c.Param("id"), format, body)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	user, err := h.userService.VerifyEmail(c.Request.Context(), req)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -87,7 +228,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+		problem.MissingParameter(c, "id")
 		return
 	}
 
@@ -100,21 +241,58 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
-func (h *UserHandler) handleError(c *gin.Context, err error) {
-	switch {
-	case errors.Is(err, domain.ErrUserNotFound):
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-	case errors.Is(err, domain.ErrEmailExists):
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-	case errors.Is(err, domain.ErrUsernameExists):
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-	case errors.Is(err, domain.ErrInvalidName):
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-	case errors.Is(err, domain.ErrInvalidEmail):
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-	case errors.Is(err, domain.ErrInvalidUsername):
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-	default:
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+func (h *UserHandler) SuspendUser(c *gin.Context) {
+	user, err := h.userService.SuspendUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *UserHandler) ReinstateUser(c *gin.Context) {
+	user, err := h.userService.ReinstateUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *UserHandler) UpdateTags(c *gin.Context) {
+	var req dto.UpdateTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
+	}
+
+	user, err := h.userService.UpdateTags(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *UserHandler) BulkUpdateTags(c *gin.Context) {
+	var req dto.BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.FromBindingError(c, err)
+		return
 	}
+
+	resp, err := h.userService.BulkUpdateTags(c.Request.Context(), req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *UserHandler) handleError(c *gin.Context, err error) {
+	problem.FromError(c, err)
 }
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"ddd-user-service/internal/application/audit"
+	"ddd-user-service/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditActor puts the caller, request ID and client address on the request
+// context, where the audit log finds them. It must run after RequestID and
+// Authenticate.
+func AuditActor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := domain.AuditActor{
+			RequestID: GetRequestID(c),
+			IP:        c.ClientIP(),
+		}
+		if principal := GetPrincipal(c); principal != nil {
+			actor.UserID = principal.UserID
+			actor.APIKeyID = principal.APIKeyID
+		}
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/logging"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ContextKeyPrincipal = "auth.principal"
+
+// AccessTokenVerifier resolves a bearer token to the caller it was issued
+// to.
+type AccessTokenVerifier interface {
+	VerifyAccessToken(ctx context.Context, accessToken string) (*dto.Principal, error)
+}
+
+// APIKeyVerifier resolves an API key to the user or service account that
+// owns it.
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*dto.Principal, error)
+}
+
+// Authenticate identifies the caller from an "X-API-Key" header or an
+// "Authorization: Bearer" header holding an access token or an API key. API
+// keys are told apart by their prefix. Requests without credentials pass
+// through anonymously; routes that need a caller add RequireAuth.
+func Authenticate(tokens AccessTokenVerifier, keys APIKeyVerifier, reject Rejection) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		var (
+			principal *dto.Principal
+			err       error
+		)
+		switch apiKey, bearer := c.GetHeader(HeaderAPIKey), bearerToken(c); {
+		case apiKey != "":
+			principal, err = keys.VerifyAPIKey(ctx, apiKey)
+		case strings.HasPrefix(bearer, domain.APIKeyPrefix):
+			principal, err = keys.VerifyAPIKey(ctx, bearer)
+		case bearer != "":
+			principal, err = tokens.VerifyAccessToken(ctx, bearer)
+		default:
+			c.Next()
+			return
+		}
+		if err != nil {
+			var domainErr *domain.Error
+			if errors.As(err, &domainErr) && domainErr.Kind == domain.KindUnauthenticated {
+				c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+				reject(c, http.StatusUnauthorized, string(domainErr.Code), domainErr.Message)
+				return
+			}
+			c.Error(err)
+			reject(c, http.StatusInternalServerError, "internal_error", "an unexpected error occurred")
+			return
+		}
+
+		c.Set(ContextKeyPrincipal, principal)
+		c.Set(ContextKeyUserID, principal.UserID)
+		logger := logging.FromContext(ctx).With(slog.String(logging.KeyUserID, principal.UserID))
+		c.Request = c.Request.WithContext(logging.WithContext(ctx, logger))
+		c.Next()
+	}
+}
+
+// RequireAuth rejects anonymous requests, and requests whose token scope is
+// not among scopes. With no scopes only fully authenticated callers pass.
+func RequireAuth(reject Rejection, scopes ...string) gin.HandlerFunc {
+	if len(scopes) == 0 {
+		scopes = []string{dto.ScopeFull}
+	}
+	return func(c *gin.Context) {
+		principal := GetPrincipal(c)
+		if principal == nil {
+			c.Header("WWW-Authenticate", "Bearer")
+			reject(c, http.StatusUnauthorized, "authentication_required", "this endpoint requires an access token")
+			return
+		}
+		if !slices.Contains(scopes, principal.Scope) {
+			code, detail := "insufficient_scope", "the access token does not allow this request"
+			if principal.Scope == dto.ScopeMFAEnrollment {
+				code, detail = "mfa_enrollment_required", "enroll a second factor and sign in again to continue"
+			}
+			reject(c, http.StatusForbidden, code, detail)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSelf only lets callers act on their own account, identified by the
+// named path parameter.
+func RequireSelf(param string, reject Rejection) gin.HandlerFunc {
+	return RequireSelfOrRole(param, "", reject)
+}
+
+// RequireSelfOrRole is RequireSelf that also lets callers with the given role
+// act on any account. An empty role admits nobody else.
+func RequireSelfOrRole(param, role string, reject Rejection) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := GetPrincipal(c)
+		if principal == nil || (principal.UserID != c.Param(param) && (role == "" || principal.Role != role)) {
+			reject(c, http.StatusForbidden, "forbidden", "you can only manage your own account")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRole only lets callers with the given role through. It must run
+// after RequireAuth.
+func RequireRole(role string, reject Rejection) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if principal := GetPrincipal(c); principal == nil || principal.Role != role {
+			reject(c, http.StatusForbidden, "forbidden", "this endpoint is for the "+role+" role only")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAPIKeyScope rejects API key callers whose key lacks scope. Other
+// callers pass through; RequireAuth decides whether they must sign in.
+func RequireAPIKeyScope(scope string, reject Rejection) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := GetPrincipal(c)
+		if principal != nil && principal.Scope == dto.ScopeAPIKey && !slices.Contains(principal.APIKeyScopes, scope) {
+			reject(c, http.StatusForbidden, "insufficient_scope", "the API key does not have the "+scope+" scope")
+			return
+		}
+		c.Next()
+	}
+}
+
+func GetPrincipal(c *gin.Context) *dto.Principal {
+	principal, _ := c.Get(ContextKeyPrincipal)
+	p, _ := principal.(*dto.Principal)
+	return p
+}
+
+func bearerToken(c *gin.Context) string {
+	auth := c.GetHeader("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
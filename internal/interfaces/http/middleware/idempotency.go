@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"ddd-user-service/internal/application/idempotency"
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/logging"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderReplayed       = "Idempotent-Replayed"
+	maxIdempotencyKey    = 255
+)
+
+// replayedHeaders are the response headers stored with a record and sent
+// again on replay.
+var replayedHeaders = []string{"Content-Type", "Location"}
+
+// Rejection is called when middleware refuses a request; it lets the HTTP
+// layer render its own error format.
+type Rejection func(c *gin.Context, status int, code, detail string)
+
+// Idempotency replays the stored response for a repeated Idempotency-Key and
+// rejects reuse of a key with a different request. Requests without the
+// header pass through untouched.
+func Idempotency(store idempotency.Store, ttl time.Duration, reject Rejection) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKey {
+			reject(c, http.StatusBadRequest, "idempotency_key_invalid", "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			reject(c, http.StatusBadRequest, "invalid_request_body", "the request body could not be read")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		requestFingerprint := fingerprint(c, body)
+		// Keys are chosen by clients, so two tenants may well pick the same.
+		scopedKey := tenancy.FromContext(ctx).String() + " " + c.Request.Method + " " + c.FullPath() + " " + key
+		now := time.Now()
+		existing, err := store.Reserve(ctx, &idempotency.Record{
+			Key:         scopedKey,
+			Fingerprint: requestFingerprint,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		})
+		if err != nil {
+			c.Error(err)
+			reject(c, http.StatusInternalServerError, "internal_error", "an unexpected error occurred")
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != requestFingerprint:
+				reject(c, http.StatusUnprocessableEntity, "idempotency_key_reused",
+					"Idempotency-Key was already used with a different request")
+			case !existing.Completed():
+				reject(c, http.StatusConflict, "idempotency_request_in_progress",
+					"a request with this Idempotency-Key is still being processed")
+			default:
+				replay(c, existing)
+			}
+			return
+		}
+
+		logger := logging.FromContext(ctx)
+		release := func() {
+			if err := store.Release(ctx, scopedKey); err != nil {
+				logger.Error("failed to release idempotency key", logging.KeyError, err.Error())
+			}
+		}
+		// A panic ends the request with a server error too; release the key
+		// before the recovery middleware answers it.
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				release()
+				panic(recovered)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			// Server errors are not final; let the client retry with the same key.
+			release()
+			return
+		}
+
+		header := make(http.Header)
+		for _, name := range replayedHeaders {
+			if value := recorder.Header().Get(name); value != "" {
+				header.Set(name, value)
+			}
+		}
+		if err := store.Complete(ctx, scopedKey, status, header, recorder.body.Bytes()); err != nil {
+			logger.Error("failed to store idempotent response", logging.KeyError, err.Error())
+		}
+	}
+}
+
+func fingerprint(c *gin.Context, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(c.Request.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(c.Request.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(c *gin.Context, rec *idempotency.Record) {
+	for name, values := range rec.Header {
+		for _, value := range values {
+			c.Writer.Header().Add(name, value)
+		}
+	}
+	c.Header(HeaderReplayed, "true")
+	c.Status(rec.StatusCode)
+	c.Writer.Write(rec.Body)
+	c.Abort()
+}
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
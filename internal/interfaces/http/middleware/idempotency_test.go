@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"ddd-user-service/internal/infrastructure/repository"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIdempotencyReleasesKeyAfterPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	r.POST("/users", Idempotency(repository.NewMemoryIdempotencyStore(), time.Hour, rejectWithStatus), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"Jane"}`))
+		req.Header.Set(HeaderIdempotencyKey, "key-1")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send(); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("first attempt: status = %d, want 500", rec.Code)
+	}
+	if rec := send(); rec.Code != http.StatusCreated {
+		t.Fatalf("retry: status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	rec := send()
+	if rec.Code != http.StatusCreated || rec.Header().Get(HeaderReplayed) != "true" {
+		t.Fatalf("repeat: status = %d, replayed = %q, want a replayed 201", rec.Code, rec.Header().Get(HeaderReplayed))
+	}
+	if calls != 2 {
+		t.Fatalf("handler ran %d times, want 2", calls)
+	}
+}
+
+func rejectWithStatus(c *gin.Context, status int, code, _ string) {
+	c.AbortWithStatusJSON(status, gin.H{"code": code})
+}
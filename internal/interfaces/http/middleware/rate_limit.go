@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"ddd-user-service/internal/application/ratelimit"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/logging"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+	HeaderRetryAfter         = "Retry-After"
+	HeaderAPIKey             = "X-API-Key"
+
+	// ContextKeyUserID is set by authentication middleware to the ID of the
+	// authenticated user.
+	ContextKeyUserID = "auth.user_id"
+
+	// contextKeyRateLimit holds the tightest decision taken so far for the
+	// request, so each limiter reports the tightest bucket in the headers.
+	contextKeyRateLimit = "ratelimit.tightest"
+)
+
+type RateLimitPolicies struct {
+	PerIP   ratelimit.Policy
+	PerKey  ratelimit.Policy
+	PerUser ratelimit.Policy
+	Routes  map[string]ratelimit.Policy
+}
+
+type RateLimiter struct {
+	store    ratelimit.Store
+	policies RateLimitPolicies
+	reject   Rejection
+}
+
+func NewRateLimiter(store ratelimit.Store, policies RateLimitPolicies, reject Rejection) *RateLimiter {
+	return &RateLimiter{
+		store:    store,
+		policies: policies,
+		reject:   reject,
+	}
+}
+
+type rateLimitCheck struct {
+	key    string
+	policy ratelimit.Policy
+}
+
+type rateLimitResult struct {
+	decision ratelimit.Decision
+	policy   ratelimit.Policy
+}
+
+// Client limits every request per client IP. It runs before authentication,
+// so that requests with bad credentials are throttled too.
+func (l *RateLimiter) Client() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l.enforce(c, []rateLimitCheck{{key: "ip:" + c.ClientIP(), policy: l.policies.PerIP}})
+	}
+}
+
+// Principal limits authenticated requests per API key and per user,
+// whichever apply. It runs after authentication.
+func (l *RateLimiter) Principal() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var checks []rateLimitCheck
+		if credential := apiCredential(c); credential != "" {
+			checks = append(checks, rateLimitCheck{key: "key:" + hashCredential(credential), policy: l.policies.PerKey})
+		}
+		if userID := c.GetString(ContextKeyUserID); userID != "" {
+			checks = append(checks, rateLimitCheck{key: "user:" + userID, policy: l.policies.PerUser})
+		}
+		l.enforce(c, checks)
+	}
+}
+
+// Route applies the named stricter per-IP policy, such as "register" or
+// "login". Unknown or disabled policies let every request through.
+func (l *RateLimiter) Route(name string) gin.HandlerFunc {
+	policy := l.policies.Routes[name]
+	return func(c *gin.Context) {
+		l.enforce(c, []rateLimitCheck{{key: "route:" + name + ":ip:" + c.ClientIP(), policy: policy}})
+	}
+}
+
+func (l *RateLimiter) enforce(c *gin.Context, checks []rateLimitCheck) {
+	var (
+		tightest ratelimit.Decision
+		policy   ratelimit.Policy
+		found    bool
+	)
+	if earlier, ok := c.Get(contextKeyRateLimit); ok {
+		result := earlier.(rateLimitResult)
+		tightest, policy, found = result.decision, result.policy, true
+	}
+
+	for _, check := range checks {
+		if !check.policy.Enabled() {
+			continue
+		}
+
+		decision, err := l.store.Take(c.Request.Context(), check.key, check.policy)
+		if err != nil {
+			// Fail open: an unavailable limiter must not take the API down.
+			logging.FromContext(c.Request.Context()).Error("rate limit store failed", logging.KeyError, err.Error())
+			continue
+		}
+
+		if !decision.Allowed {
+			tightest, policy, found = decision, check.policy, true
+			break
+		}
+		if !found || decision.Remaining < tightest.Remaining {
+			tightest, policy, found = decision, check.policy, true
+		}
+	}
+
+	if !found {
+		c.Next()
+		return
+	}
+
+	c.Set(contextKeyRateLimit, rateLimitResult{decision: tightest, policy: policy})
+	header := c.Writer.Header()
+	header.Set(HeaderRateLimitLimit, strconv.Itoa(tightest.Limit))
+	header.Set(HeaderRateLimitRemaining, strconv.Itoa(tightest.Remaining))
+	header.Set(HeaderRateLimitReset, seconds(tightest.Reset))
+	header.Set(HeaderRateLimitPolicy, strconv.Itoa(policy.Burst)+";w="+seconds(policy.Period))
+
+	if !tightest.Allowed {
+		header.Set(HeaderRetryAfter, seconds(tightest.RetryAfter))
+		l.reject(c, http.StatusTooManyRequests, "rate_limited", "too many requests, retry after "+seconds(tightest.RetryAfter)+"s")
+		return
+	}
+
+	c.Next()
+}
+
+// apiCredential returns the API key the request carries, if any. Session
+// access tokens are limited per user instead.
+func apiCredential(c *gin.Context) string {
+	if key := c.GetHeader(HeaderAPIKey); key != "" {
+		return key
+	}
+	if bearer := bearerToken(c); strings.HasPrefix(bearer, domain.APIKeyPrefix) {
+		return bearer
+	}
+	return ""
+}
+
+// hashCredential keeps raw secrets out of limiter keys.
+func hashCredential(credential string) string {
+	sum := sha256.Sum256([]byte(credential))
+	return hex.EncodeToString(sum[:16])
+}
+
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"ddd-user-service/internal/application/ratelimit"
+	"ddd-user-service/internal/infrastructure/repository"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimitByIPBeforeAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := NewRateLimiter(repository.NewMemoryRateLimitStore(), RateLimitPolicies{
+		PerIP:   ratelimit.Policy{Name: "ip", Burst: 3, Period: time.Minute},
+		PerUser: ratelimit.Policy{Name: "user", Burst: 10, Period: time.Minute},
+	}, rejectWithStatus)
+
+	authChecks := 0
+	r := gin.New()
+	r.Use(limiter.Client())
+	r.Use(func(c *gin.Context) {
+		authChecks++
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			c.Set(ContextKeyUserID, user)
+			return
+		}
+		c.AbortWithStatus(http.StatusUnauthorized)
+	})
+	r.Use(limiter.Principal())
+	r.GET("/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		if user != "" {
+			req.Header.Set("X-Test-User", user)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send("u-1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	// The IP bucket has 2 tokens left and the user bucket 9; the headers
+	// report the tighter one.
+	if got := rec.Header().Get(HeaderRateLimitRemaining); got != "2" {
+		t.Fatalf("%s = %q, want 2", HeaderRateLimitRemaining, got)
+	}
+	if got := rec.Header().Get(HeaderRateLimitLimit); got != "3" {
+		t.Fatalf("%s = %q, want 3", HeaderRateLimitLimit, got)
+	}
+
+	for range 2 {
+		if rec := send(""); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("bad credentials: status = %d, want 401", rec.Code)
+		}
+	}
+	if rec := send(""); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("bad credentials over the IP limit: status = %d, want 429", rec.Code)
+	}
+	if authChecks != 3 {
+		t.Fatalf("credentials checked %d times, want 3", authChecks)
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"ddd-user-service/internal/logging"
+	"log/slog"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	HeaderRequestID  = "X-Request-ID"
+	requestIDKey     = "request_id"
+	maxRequestIDSize = 128
+)
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:\-]+$`)
+
+// RequestID honours an incoming X-Request-ID (or generates one), echoes it on
+// the response and stores a request-scoped logger on the request context.
+// It must run after Tracing so the trace ID can be attached to the logger.
+func RequestID(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(HeaderRequestID)
+		if len(requestID) > maxRequestIDSize || !validRequestID.MatchString(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Set(requestIDKey, requestID)
+		c.Header(HeaderRequestID, requestID)
+
+		ctx := c.Request.Context()
+		requestLogger := logger.With(slog.String(logging.KeyRequestID, requestID))
+		if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.HasTraceID() {
+			requestLogger = requestLogger.With(slog.String(logging.KeyTraceID, spanCtx.TraceID().String()))
+		}
+
+		c.Request = c.Request.WithContext(logging.WithContext(ctx, requestLogger))
+		c.Next()
+	}
+}
+
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"ddd-user-service/internal/application/tenancy"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/logging"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const HeaderTenantID = "X-Tenant-ID"
+
+type TenantOptions struct {
+	// Default is used when the request names no tenant. Empty makes the
+	// tenant mandatory.
+	Default domain.TenantID
+	// BaseDomain enables subdomain resolution; see config.TenantConfig.
+	BaseDomain string
+	// Allowed lists the tenants served. Empty serves only Default, so
+	// callers can never reach a tenant the deployment did not name.
+	Allowed []domain.TenantID
+}
+
+func (o TenantOptions) serves(tenant domain.TenantID) bool {
+	if len(o.Allowed) == 0 {
+		return tenant == o.Default
+	}
+	return slices.Contains(o.Allowed, tenant)
+}
+
+// ResolveTenant decides which tenant the request acts in and stores it in
+// the request context for the services. The tenant comes from the caller's
+// credential, the X-Tenant-ID header or the subdomain, in that order, and
+// otherwise defaults. Sources that disagree are rejected, so a credential
+// can never be used in another tenant. It must run after Authenticate.
+func ResolveTenant(opts TenantOptions, reject Rejection) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var named []string
+		if header := c.GetHeader(HeaderTenantID); header != "" {
+			named = append(named, header)
+		}
+		if sub := tenantSubdomain(c.Request.Host, opts.BaseDomain); sub != "" {
+			named = append(named, sub)
+		}
+
+		var tenant domain.TenantID
+		for _, raw := range named {
+			parsed, err := domain.ParseTenantID(raw)
+			if err != nil {
+				reject(c, http.StatusBadRequest, string(domain.CodeTenantInvalid), "the tenant "+raw+" is not a valid tenant ID")
+				return
+			}
+			if tenant != "" && parsed != tenant {
+				reject(c, http.StatusBadRequest, "tenant_mismatch", "the request names more than one tenant")
+				return
+			}
+			tenant = parsed
+		}
+
+		if principal := GetPrincipal(c); principal != nil {
+			own := domain.TenantID(principal.TenantID)
+			if tenant != "" && tenant != own {
+				reject(c, http.StatusForbidden, "tenant_mismatch", "the credential does not belong to this tenant")
+				return
+			}
+			tenant = own
+		}
+
+		if tenant == "" {
+			tenant = opts.Default
+		}
+		if tenant == "" {
+			reject(c, http.StatusBadRequest, "tenant_required", "name the tenant in the "+HeaderTenantID+" header")
+			return
+		}
+		if !opts.serves(tenant) {
+			reject(c, http.StatusNotFound, "tenant_not_found", "tenant "+tenant.String()+" does not exist")
+			return
+		}
+
+		ctx := tenancy.WithTenant(c.Request.Context(), tenant)
+		logger := logging.FromContext(ctx).With(slog.String(logging.KeyTenantID, tenant.String()))
+		c.Request = c.Request.WithContext(logging.WithContext(ctx, logger))
+		c.Next()
+	}
+}
+
+// tenantSubdomain returns the label in front of baseDomain in host, or ""
+// when host is not a subdomain of it.
+func tenantSubdomain(host, baseDomain string) string {
+	if baseDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	sub, ok := strings.CutSuffix(strings.ToLower(host), "."+baseDomain)
+	if !ok {
+		return ""
+	}
+	return sub
+}
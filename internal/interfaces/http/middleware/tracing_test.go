@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans installs a tracer provider that keeps every ended span, and
+// restores the global provider and propagator afterwards.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+	return recorder
+}
+
+func TestTracingStartsServerSpans(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := recordSpans(t)
+
+	r := gin.New()
+	r.Use(Tracing())
+	var handlerSpan trace.SpanContext
+	r.GET("/users/:id", func(c *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+		if c.Param("id") == "boom" {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	const parentTrace = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/users/u-1", nil)
+	req.Header.Set("traceparent", "00-"+parentTrace+"-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/boom", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("%d spans ended, want 2", len(spans))
+	}
+	ok, failed := spans[0], spans[1]
+
+	if ok.Name() != "GET /users/:id" || ok.SpanKind() != trace.SpanKindServer {
+		t.Fatalf("span = %q (%v), want a server span named after the route", ok.Name(), ok.SpanKind())
+	}
+	if ok.SpanContext().TraceID().String() != parentTrace || ok.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Fatalf("span does not continue the incoming trace: %v", ok.SpanContext().TraceID())
+	}
+	if !strings.Contains(rec.Header().Get("traceparent"), ok.SpanContext().SpanID().String()) {
+		t.Fatalf("traceparent response header = %q, want the server span", rec.Header().Get("traceparent"))
+	}
+	attrs := map[string]string{}
+	for _, kv := range ok.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["http.route"] != "/users/:id" || attrs["url.path"] != "/users/u-1" || attrs["http.response.status_code"] != "200" {
+		t.Fatalf("attributes = %v", attrs)
+	}
+	if ok.Status().Code == codes.Error {
+		t.Fatal("successful request marked as an error")
+	}
+
+	if failed.Status().Code != codes.Error {
+		t.Fatalf("status = %v, want an error for a 500", failed.Status())
+	}
+	if failed.Parent().IsValid() {
+		t.Fatal("request without traceparent got a parent span")
+	}
+	if handlerSpan.SpanID() != failed.SpanContext().SpanID() {
+		t.Fatal("handler context does not carry the server span")
+	}
+}
@@ -0,0 +1,223 @@
+package problem
+
+import (
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/interfaces/http/middleware"
+	"ddd-user-service/internal/logging"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const ContentType = "application/problem+json"
+
+const (
+	CodeInvalidRequestBody   domain.ErrorCode = "invalid_request_body"
+	CodeMissingParameter     domain.ErrorCode = "missing_parameter"
+	CodeRouteNotFound        domain.ErrorCode = "route_not_found"
+	CodeUnsupportedMediaType domain.ErrorCode = "unsupported_media_type"
+	CodePayloadTooLarge      domain.ErrorCode = "payload_too_large"
+	CodeInternal             domain.ErrorCode = "internal_error"
+)
+
+const typeBase = "urn:ddd-user-service:problem:"
+
+// Write renders a problem document and aborts the request.
+func Write(c *gin.Context, status int, code domain.ErrorCode, detail string, fieldErrors ...dto.FieldError) {
+	body := dto.ProblemDetails{
+		Type:      typeBase + string(code),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      string(code),
+		RequestID: middleware.GetRequestID(c),
+		Errors:    fieldErrors,
+	}
+
+	c.Abort()
+	c.Render(status, problemRender{body: body})
+}
+
+// FromError maps an error returned by the application layer to a problem
+// document. Anything that is not a domain error becomes an opaque 500.
+func FromError(c *gin.Context, err error) {
+	var validationErrs domain.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fieldErrors := make([]dto.FieldError, len(validationErrs))
+		for i, e := range validationErrs {
+			fieldErrors[i] = toFieldError(e)
+		}
+		Write(c, http.StatusBadRequest, domain.CodeValidationFailed, "the request contains invalid fields", fieldErrors...)
+		return
+	}
+
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		status := statusForKind(domainErr.Kind)
+		if retryAfter, ok := domainErr.Params["retry_after"].(int); ok {
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+		}
+		if domainErr.Kind == domain.KindValidation && domainErr.Field != "" {
+			Write(c, status, domain.CodeValidationFailed, "the request contains invalid fields", toFieldError(domainErr))
+			return
+		}
+		var fieldErrors []dto.FieldError
+		if domainErr.Field != "" {
+			fieldErrors = append(fieldErrors, toFieldError(domainErr))
+		}
+		Write(c, status, domainErr.Code, domainErr.Message, fieldErrors...)
+		return
+	}
+
+	c.Error(err)
+	logging.FromContext(c.Request.Context()).Error("unhandled error", logging.KeyError, err.Error())
+	Write(c, http.StatusInternalServerError, CodeInternal, "an unexpected error occurred")
+}
+
+// FromBindingError reports request decoding and binding-tag failures without
+// leaking raw validator messages.
+func FromBindingError(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fieldErrors := make([]dto.FieldError, len(validationErrs))
+		for i, fe := range validationErrs {
+			fieldErrors[i] = fromValidatorError(fe)
+		}
+		Write(c, http.StatusBadRequest, domain.CodeValidationFailed, "the request contains invalid fields", fieldErrors...)
+		return
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		Write(c, http.StatusBadRequest, domain.CodeValidationFailed, "the request contains invalid fields", dto.FieldError{
+			Code:    "invalid_type",
+			Field:   typeErr.Field,
+			Message: "value has the wrong type",
+			Params:  map[string]any{"expected": typeErr.Type.String()},
+		})
+		return
+	}
+
+	detail := "the request body is not valid JSON"
+	if errors.Is(err, io.EOF) {
+		detail = "the request body is empty"
+	}
+	Write(c, http.StatusBadRequest, CodeInvalidRequestBody, detail)
+}
+
+// Reject adapts Write to the callback signature used by middleware that
+// cannot import this package.
+func Reject(c *gin.Context, status int, code, detail string) {
+	Write(c, status, domain.ErrorCode(code), detail)
+}
+
+func MissingParameter(c *gin.Context, name string) {
+	Write(c, http.StatusBadRequest, CodeMissingParameter, name+" parameter is required", dto.FieldError{
+		Code:    string(CodeMissingParameter),
+		Field:   name,
+		Message: name + " parameter is required",
+	})
+}
+
+// UseJSONFieldNames makes binding errors report JSON field names instead of
+// Go struct field names.
+func UseJSONFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+}
+
+func statusForKind(kind domain.ErrorKind) int {
+	switch kind {
+	case domain.KindValidation:
+		return http.StatusBadRequest
+	case domain.KindNotFound:
+		return http.StatusNotFound
+	case domain.KindConflict:
+		return http.StatusConflict
+	case domain.KindUnauthenticated:
+		return http.StatusUnauthorized
+	case domain.KindForbidden:
+		return http.StatusForbidden
+	case domain.KindTooManyAttempts:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toFieldError(e *domain.Error) dto.FieldError {
+	return dto.FieldError{
+		Code:    string(e.Code),
+		Field:   e.Field,
+		Message: e.Message,
+		Params:  e.Params,
+	}
+}
+
+var validatorCodes = map[string]string{
+	"required":      "field_required",
+	"email":         "email_invalid",
+	"min":           "too_short",
+	"max":           "too_long",
+	"len":           "invalid_length",
+	"oneof":         "invalid_choice",
+	"url":           "url_invalid",
+	"required_with": "field_required",
+}
+
+func fromValidatorError(fe validator.FieldError) dto.FieldError {
+	code, ok := validatorCodes[fe.Tag()]
+	if !ok {
+		code = "field_invalid"
+	}
+
+	field := fe.Namespace()
+	if _, rest, found := strings.Cut(field, "."); found {
+		field = rest
+	}
+
+	out := dto.FieldError{
+		Code:    code,
+		Field:   field,
+		Message: field + " failed the " + fe.Tag() + " rule",
+	}
+	if fe.Param() != "" {
+		out.Params = map[string]any{fe.Tag(): fe.Param()}
+	}
+	return out
+}
+
+type problemRender struct {
+	body dto.ProblemDetails
+}
+
+func (r problemRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return json.NewEncoder(w).Encode(r.body)
+}
+
+func (r problemRender) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ContentType)
+}
@@ -0,0 +1,134 @@
+package problem
+
+import (
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// render runs write on a test request and decodes the problem document.
+func render(t *testing.T, write func(*gin.Context)) (*httptest.ResponseRecorder, dto.ProblemDetails) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/users", nil)
+	write(c)
+
+	if got := rec.Header().Get("Content-Type"); got != ContentType {
+		t.Fatalf("Content-Type = %q, want %q", got, ContentType)
+	}
+	var body dto.ProblemDetails
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Status != rec.Code || body.Instance != "/api/v1/users" || body.Type != typeBase+body.Code {
+		t.Fatalf("inconsistent problem document: %+v", body)
+	}
+	return rec, body
+}
+
+func TestFromError(t *testing.T) {
+	tooMany := domain.NewTooManyAttemptsError("account_locked", "try again later").
+		WithParams(map[string]any{"retry_after": 30})
+	var invalid domain.ValidationErrors
+	invalid.Add(domain.NewValidationError("too_short", "name", "name is too short"))
+	invalid.Add(domain.NewValidationError("email_invalid", "email", "email is invalid"))
+
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		code       string
+		fields     []string
+		retryAfter string
+	}{
+		{"validation errors", invalid, http.StatusBadRequest, "validation_failed", []string{"name", "email"}, ""},
+		{"single validation error", domain.ErrTooManyTags, http.StatusBadRequest, "validation_failed", []string{"tags"}, ""},
+		{"validation error without field", domain.NewValidationError("filter_required", "", "filter needed"), http.StatusBadRequest, "filter_required", nil, ""},
+		{"conflict with field", domain.ErrMemberExists, http.StatusConflict, "member_exists", []string{"user_id"}, ""},
+		{"wrapped not found", fmt.Errorf("failed to load: %w", domain.ErrUserNotFound), http.StatusNotFound, "user_not_found", nil, ""},
+		{"unauthenticated", domain.NewUnauthenticatedError("token_invalid", "bad token"), http.StatusUnauthorized, "token_invalid", nil, ""},
+		{"forbidden", domain.ErrOrganizationForbidden, http.StatusForbidden, "organization_forbidden", nil, ""},
+		{"too many attempts", tooMany, http.StatusTooManyRequests, "account_locked", nil, "30"},
+		{"unknown error", errors.New("mongo: connection refused"), http.StatusInternalServerError, "internal_error", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, body := render(t, func(c *gin.Context) { FromError(c, tt.err) })
+
+			if rec.Code != tt.status || body.Code != tt.code {
+				t.Fatalf("got %d %s, want %d %s", rec.Code, body.Code, tt.status, tt.code)
+			}
+			var fields []string
+			for _, fe := range body.Errors {
+				fields = append(fields, fe.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+				t.Fatalf("error fields = %v, want %v", fields, tt.fields)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Fatalf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+			if strings.Contains(rec.Body.String(), "mongo") {
+				t.Fatalf("internal error leaked: %s", rec.Body)
+			}
+		})
+	}
+}
+
+func TestFromBindingError(t *testing.T) {
+	type request struct {
+		Email string `json:"email" binding:"required,email"`
+		Age   int    `json:"age"`
+	}
+	UseJSONFieldNames()
+
+	tests := []struct {
+		name   string
+		body   string
+		code   string
+		detail string
+		field  string
+		rule   string
+	}{
+		{"empty body", "", "invalid_request_body", "the request body is empty", "", ""},
+		{"malformed json", "{", "invalid_request_body", "the request body is not valid JSON", "", ""},
+		{"wrong type", `{"email": "a@example.com", "age": "ten"}`, "validation_failed", "", "age", "invalid_type"},
+		{"binding rule", `{"email": "nope"}`, "validation_failed", "", "email", "email_invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, body := render(t, func(c *gin.Context) {
+				c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(tt.body))
+				c.Request.Header.Set("Content-Type", "application/json")
+				var req request
+				FromBindingError(c, c.ShouldBindJSON(&req))
+			})
+
+			if rec.Code != http.StatusBadRequest || body.Code != tt.code {
+				t.Fatalf("got %d %s, want 400 %s", rec.Code, body.Code, tt.code)
+			}
+			if tt.detail != "" && body.Detail != tt.detail {
+				t.Fatalf("detail = %q, want %q", body.Detail, tt.detail)
+			}
+			if tt.field == "" {
+				if len(body.Errors) != 0 {
+					t.Fatalf("errors = %+v, want none", body.Errors)
+				}
+				return
+			}
+			if len(body.Errors) != 1 || body.Errors[0].Field != tt.field || body.Errors[0].Code != tt.rule {
+				t.Fatalf("errors = %+v, want %s on %s", body.Errors, tt.rule, tt.field)
+			}
+		})
+	}
+}
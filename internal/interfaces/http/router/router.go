@@ -1,30 +1,223 @@
 package router
 
 import (
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/application/idempotency"
+	"ddd-user-service/internal/domain"
 	"ddd-user-service/internal/interfaces/http/handler"
+	"ddd-user-service/internal/interfaces/http/middleware"
+	"ddd-user-service/internal/interfaces/http/problem"
+	"ddd-user-service/internal/logging"
+	"log/slog"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(userHandler *handler.UserHandler) *gin.Engine {
+type Dependencies struct {
+	UserHandler      *handler.UserHandler
+	AuthHandler      *handler.AuthHandler
+	SessionHandler   *handler.SessionHandler
+	APIKeyHandler    *handler.APIKeyHandler
+	OrgHandler       *handler.OrganizationHandler
+	InviteHandler    *handler.InvitationHandler
+	PolicyHandler    *handler.PolicyHandler
+	AvatarHandler    *handler.AvatarHandler
+	AttributeHandler *handler.AttributeHandler
+	AuditHandler     *handler.AuditHandler
+	// AccessTokenVerifier authenticates bearer tokens.
+	AccessTokenVerifier middleware.AccessTokenVerifier
+	APIKeyVerifier      middleware.APIKeyVerifier
+	Logger              *slog.Logger
+	IdempotencyStore    idempotency.Store
+	IdempotencyTTL      time.Duration
+	RateLimiter         *middleware.RateLimiter
+	Tenants             middleware.TenantOptions
+	// TrustedProxies are the proxies whose forwarding headers set the
+	// client IP. Nil trusts none.
+	TrustedProxies []string
+}
+
+func SetupRouter(deps Dependencies) *gin.Engine {
+	userHandler := deps.UserHandler
+	authHandler := deps.AuthHandler
+	sessionHandler := deps.SessionHandler
+	apiKeyHandler := deps.APIKeyHandler
+	orgHandler := deps.OrgHandler
+	inviteHandler := deps.InviteHandler
+	policyHandler := deps.PolicyHandler
+	avatarHandler := deps.AvatarHandler
+	attributeHandler := deps.AttributeHandler
+	auditHandler := deps.AuditHandler
+	logger := deps.Logger
+
 	gin.SetMode(gin.ReleaseMode)
-	r := gin.Default()
+	problem.UseJSONFieldNames()
+
+	r := gin.New()
+	// The client IP keys rate limits, lockouts, sessions and the audit log,
+	// so forwarding headers are only believed from configured proxies.
+	if err := r.SetTrustedProxies(deps.TrustedProxies); err != nil {
+		logger.Error("invalid trusted proxies, trusting none", logging.KeyError, err.Error())
+		_ = r.SetTrustedProxies(nil)
+	}
 
-	r.Use(gin.Logger())
-	r.Use(gin.Recovery())
+	r.Use(gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		logger.Error("panic recovered", "panic", recovered, "path", c.Request.URL.Path)
+		problem.Write(c, http.StatusInternalServerError, problem.CodeInternal, "an unexpected error occurred")
+	}))
+	r.Use(middleware.Tracing())
+	r.Use(middleware.RequestID(logger))
+	r.Use(middleware.Logger())
 
 	api := r.Group("/api/v1")
+	// The per-IP limit runs before authentication, so failed credential
+	// checks are throttled too; the per-user limit needs the principal.
+	if deps.RateLimiter != nil {
+		api.Use(deps.RateLimiter.Client())
+	}
+	api.Use(middleware.Authenticate(deps.AccessTokenVerifier, deps.APIKeyVerifier, problem.Reject))
+	api.Use(middleware.ResolveTenant(deps.Tenants, problem.Reject))
+	api.Use(middleware.AuditActor())
+	if deps.RateLimiter != nil {
+		api.Use(deps.RateLimiter.Principal())
+	}
 	{
 		users := api.Group("/users")
 		{
-			users.POST("", userHandler.CreateUser)
-			users.GET("", userHandler.GetAllUsers)
-			users.GET("/:id", userHandler.GetUser)
-			users.PUT("/:id", userHandler.UpdateUser)
-			users.DELETE("/:id", userHandler.DeleteUser)
+			// API keys only reach the user resource within their scopes.
+			read := middleware.RequireAPIKeyScope(domain.APIScopeUsersRead, problem.Reject)
+			write := middleware.RequireAPIKeyScope(domain.APIScopeUsersWrite, problem.Reject)
+			users.POST("",
+				write,
+				routeLimit(deps.RateLimiter, "register"),
+				middleware.Idempotency(deps.IdempotencyStore, deps.IdempotencyTTL, problem.Reject),
+				userHandler.RegisterUser,
+			)
+			users.POST("/verify-email", userHandler.VerifyEmail)
+
+			// Signed-in users and API keys act on their own account;
+			// administrators on any.
+			signedIn := middleware.RequireAuth(problem.Reject, dto.ScopeFull, dto.ScopeAPIKey)
+			selfOrAdmin := middleware.RequireSelfOrRole("id", domain.RoleAdmin.String(), problem.Reject)
+			users.GET("", signedIn, middleware.RequireRole(domain.RoleAdmin.String(), problem.Reject), read, userHandler.GetAllUsers)
+			users.GET("/:id", signedIn, selfOrAdmin, read, userHandler.GetUser)
+			users.PUT("/:id", signedIn, selfOrAdmin, write, userHandler.ReplaceUser)
+			users.PATCH("/:id", signedIn, selfOrAdmin, write, userHandler.PatchUser)
+			users.DELETE("/:id", signedIn, selfOrAdmin, write, userHandler.DeleteUser)
+			users.GET("/:id/profile", signedIn, selfOrAdmin, read, userHandler.GetProfile)
+			users.PUT("/:id/profile", signedIn, selfOrAdmin, write, userHandler.ReplaceProfile)
+			users.PATCH("/:id/profile", signedIn, selfOrAdmin, write, userHandler.PatchProfile)
+			users.PUT("/:id/avatar", signedIn, selfOrAdmin, write, avatarHandler.UploadAvatar)
+			users.POST("/:id/tags", signedIn, selfOrAdmin, write, userHandler.UpdateTags)
+
+			// MFA enrollment is also open to callers who signed in with an
+			// enrollment-only token.
+			enrollment := middleware.RequireAuth(problem.Reject, dto.ScopeFull, dto.ScopeMFAEnrollment)
+			self := middleware.RequireSelf("id", problem.Reject)
+			users.POST("/:id/mfa/totp", enrollment, self, authHandler.StartTOTPEnrollment)
+			users.POST("/:id/mfa/totp/confirm", enrollment, self, authHandler.ConfirmTOTPEnrollment)
+			users.POST("/:id/mfa/recovery-codes", middleware.RequireAuth(problem.Reject), self, authHandler.RegenerateRecoveryCodes)
+
+			// A user-verifying passkey satisfies the MFA requirement, so it
+			// can be registered during enrollment too.
+			users.POST("/:id/webauthn/registration/begin", enrollment, self, authHandler.BeginWebAuthnRegistration)
+			users.POST("/:id/webauthn/registration/finish", enrollment, self, authHandler.FinishWebAuthnRegistration)
+			users.GET("/:id/webauthn/credentials", middleware.RequireAuth(problem.Reject), self, authHandler.ListWebAuthnCredentials)
+			users.PATCH("/:id/webauthn/credentials/:cid", middleware.RequireAuth(problem.Reject), self, authHandler.RenameWebAuthnCredential)
+			users.DELETE("/:id/webauthn/credentials/:cid", middleware.RequireAuth(problem.Reject), self, authHandler.DeleteWebAuthnCredential)
+
+			// Any session may sign itself out, including an enrollment-only
+			// one.
+			users.GET("/:id/sessions", middleware.RequireAuth(problem.Reject), self, sessionHandler.ListSessions)
+			users.DELETE("/:id/sessions/:sid", enrollment, self, sessionHandler.RevokeSession)
+
+			// Administrators manage the keys of service accounts, which
+			// cannot sign in themselves.
+			users.GET("/:id/api-keys", middleware.RequireAuth(problem.Reject), selfOrAdmin, apiKeyHandler.ListAPIKeys)
+			users.POST("/:id/api-keys", middleware.RequireAuth(problem.Reject), selfOrAdmin, apiKeyHandler.CreateAPIKey)
+			users.POST("/:id/api-keys/:kid/rotate", middleware.RequireAuth(problem.Reject), selfOrAdmin, apiKeyHandler.RotateAPIKey)
+			users.DELETE("/:id/api-keys/:kid", middleware.RequireAuth(problem.Reject), selfOrAdmin, apiKeyHandler.RevokeAPIKey)
+
+			users.GET("/:id/organizations", middleware.RequireAuth(problem.Reject), selfOrAdmin, orgHandler.ListUserOrganizations)
+		}
+
+		// Permissions within an organization depend on the caller's role in
+		// it, which the service checks.
+		orgs := api.Group("/organizations", middleware.RequireAuth(problem.Reject))
+		{
+			orgs.POST("", orgHandler.CreateOrganization)
+			orgs.GET("/:oid", orgHandler.GetOrganization)
+			orgs.DELETE("/:oid", orgHandler.DeleteOrganization)
+			orgs.POST("/:oid/members", orgHandler.AddMember)
+			orgs.PATCH("/:oid/members/:uid", orgHandler.ChangeMemberRole)
+			orgs.DELETE("/:oid/members/:uid", orgHandler.RemoveMember)
+			orgs.POST("/:oid/invitations", orgHandler.InviteMember)
+			orgs.POST("/:oid/invitations/accept", orgHandler.AcceptInvitation)
+			orgs.POST("/:oid/teams", orgHandler.CreateTeam)
+			orgs.DELETE("/:oid/teams/:tid", orgHandler.DeleteTeam)
+			orgs.PUT("/:oid/teams/:tid/members/:uid", orgHandler.AddTeamMember)
+			orgs.DELETE("/:oid/teams/:tid/members/:uid", orgHandler.RemoveTeamMember)
+		}
+
+		// Accepting needs only the mailed token; it creates the account.
+		api.POST("/invitations/accept", routeLimit(deps.RateLimiter, "register"), inviteHandler.AcceptInvitation)
+
+		auth := api.Group("/auth")
+		{
+			auth.POST("/login", routeLimit(deps.RateLimiter, "login"), authHandler.Login)
+			auth.POST("/login/mfa", routeLimit(deps.RateLimiter, "login"), authHandler.CompleteMFALogin)
+			auth.POST("/webauthn/login/begin", routeLimit(deps.RateLimiter, "login"), authHandler.BeginWebAuthnLogin)
+			auth.POST("/webauthn/login/finish", routeLimit(deps.RateLimiter, "login"), authHandler.FinishWebAuthnLogin)
+			auth.POST("/password/forgot", routeLimit(deps.RateLimiter, "password_reset"), authHandler.ForgotPassword)
+			auth.POST("/password/reset", routeLimit(deps.RateLimiter, "password_reset"), authHandler.ResetPassword)
+		}
+
+		// The audit log is only for signed-in administrators.
+		audit := api.Group("/audit",
+			middleware.RequireAuth(problem.Reject),
+			middleware.RequireRole(domain.RoleAdmin.String(), problem.Reject),
+		)
+		{
+			audit.GET("", auditHandler.ListEntries)
+			audit.GET("/verify", auditHandler.VerifyChain)
+		}
+
+		// Administration is likewise only for signed-in administrators.
+		admin := api.Group("/admin",
+			middleware.RequireAuth(problem.Reject),
+			middleware.RequireRole(domain.RoleAdmin.String(), problem.Reject),
+		)
+		{
+			admin.POST("/users", userHandler.CreateUser)
+			admin.POST("/users/tags", userHandler.BulkUpdateTags)
+			admin.POST("/users/:id/suspend", userHandler.SuspendUser)
+			admin.POST("/users/:id/reinstate", userHandler.ReinstateUser)
+			admin.POST("/users/:id/unlock", authHandler.UnlockUser)
+			admin.POST("/invitations", inviteHandler.CreateInvitation)
+			admin.GET("/invitations", inviteHandler.ListInvitations)
+			admin.GET("/invitations/:id", inviteHandler.GetInvitation)
+			admin.POST("/invitations/:id/resend", inviteHandler.ResendInvitation)
+			admin.POST("/invitations/:id/revoke", inviteHandler.RevokeInvitation)
+			admin.GET("/policies/:list", policyHandler.ListEntries)
+			admin.POST("/policies/:list", policyHandler.AddEntry)
+			admin.DELETE("/policies/:list/:value", policyHandler.RemoveEntry)
+			admin.GET("/attributes", attributeHandler.ListAttributes)
+			admin.POST("/attributes", attributeHandler.CreateAttribute)
+			admin.GET("/attributes/:name", attributeHandler.GetAttribute)
+			admin.DELETE("/attributes/:name", attributeHandler.DeleteAttribute)
 		}
 	}
 
+	// Avatar images are public and name their tenant in the path, so they
+	// can be embedded anywhere without credentials or tenant headers.
+	r.GET("/avatars/:tenant/:id/:file", avatarHandler.ServeAvatar)
+
+	r.NoRoute(func(c *gin.Context) {
+		problem.Write(c, http.StatusNotFound, problem.CodeRouteNotFound, "no route matches "+c.Request.URL.Path)
+	})
+
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"status": "ok",
@@ -33,3 +226,12 @@ func SetupRouter(userHandler *handler.UserHandler) *gin.Engine {
 
 	return r
 }
+
+// routeLimit returns the named per-route rate limit, or a no-op when rate
+// limiting is disabled.
+func routeLimit(limiter *middleware.RateLimiter, name string) gin.HandlerFunc {
+	if limiter == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return limiter.Route(name)
+}
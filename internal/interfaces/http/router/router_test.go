@@ -0,0 +1,189 @@
+package router
+
+import (
+	"context"
+	"ddd-user-service/internal/application/dto"
+	"ddd-user-service/internal/domain"
+	"ddd-user-service/internal/interfaces/http/middleware"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubTokens accepts the tokens it knows, each standing for a principal.
+type stubTokens map[string]*dto.Principal
+
+func (s stubTokens) VerifyAccessToken(_ context.Context, token string) (*dto.Principal, error) {
+	if principal, ok := s[token]; ok {
+		return principal, nil
+	}
+	return nil, domain.NewUnauthenticatedError("invalid_token", "the access token is invalid")
+}
+
+func (s stubTokens) VerifyAPIKey(ctx context.Context, key string) (*dto.Principal, error) {
+	return s.VerifyAccessToken(ctx, key)
+}
+
+// newTestRouter builds the router without handlers; requests that get past
+// the guards would panic, so the tests only send ones that must not.
+func newTestRouter(trustedProxies ...string) *gin.Engine {
+	tenant := domain.DefaultTenantID.String()
+	return SetupRouter(Dependencies{
+		TrustedProxies: trustedProxies,
+		AccessTokenVerifier: stubTokens{
+			"member": {UserID: "u-member", TenantID: tenant, Role: domain.RoleUser.String(), Scope: dto.ScopeFull},
+			"admin":  {UserID: "u-admin", TenantID: tenant, Role: domain.RoleAdmin.String(), Scope: dto.ScopeFull},
+			"member-key": {
+				UserID: "u-member", TenantID: tenant, Role: domain.RoleUser.String(), Scope: dto.ScopeAPIKey,
+				APIKeyScopes: []string{domain.APIScopeUsersRead, domain.APIScopeUsersWrite},
+			},
+			"enrolling-admin": {
+				UserID: "u-admin", TenantID: tenant, Role: domain.RoleAdmin.String(), Scope: dto.ScopeMFAEnrollment,
+			},
+		},
+		APIKeyVerifier: stubTokens{},
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Tenants:        middleware.TenantOptions{Default: domain.DefaultTenantID},
+	})
+}
+
+type guardedRoute struct {
+	method, path string
+}
+
+var adminRoutes = []guardedRoute{
+	{http.MethodPost, "/api/v1/admin/users"},
+	{http.MethodPost, "/api/v1/admin/users/u-member/suspend"},
+	{http.MethodPost, "/api/v1/admin/users/u-member/reinstate"},
+	{http.MethodPost, "/api/v1/admin/users/u-member/unlock"},
+	{http.MethodGet, "/api/v1/admin/policies/blocked_domains"},
+	{http.MethodPost, "/api/v1/admin/policies/blocked_domains"},
+	{http.MethodDelete, "/api/v1/admin/policies/blocked_domains/example.com"},
+	{http.MethodGet, "/api/v1/admin/attributes"},
+	{http.MethodPost, "/api/v1/admin/attributes"},
+	{http.MethodGet, "/api/v1/admin/attributes/plan"},
+	{http.MethodDelete, "/api/v1/admin/attributes/plan"},
+	{http.MethodPost, "/api/v1/admin/users/tags"},
+	{http.MethodPost, "/api/v1/admin/invitations"},
+	{http.MethodGet, "/api/v1/admin/invitations"},
+	{http.MethodGet, "/api/v1/admin/invitations/inv-1"},
+	{http.MethodPost, "/api/v1/admin/invitations/inv-1/resend"},
+	{http.MethodPost, "/api/v1/admin/invitations/inv-1/revoke"},
+}
+
+func TestAdminRoutesRequireAdmin(t *testing.T) {
+	r := newTestRouter()
+	callers := []struct {
+		name, token string
+		status      int
+	}{
+		{"anonymous", "", http.StatusUnauthorized},
+		{"non-admin", "member", http.StatusForbidden},
+		{"enrollment-only admin", "enrolling-admin", http.StatusForbidden},
+	}
+
+	for _, route := range adminRoutes {
+		for _, caller := range callers {
+			t.Run(route.method+" "+route.path+"/"+caller.name, func(t *testing.T) {
+				rec := serve(r, route, caller.token)
+				if rec.Code != caller.status {
+					t.Fatalf("status = %d, want %d: %s", rec.Code, caller.status, rec.Body)
+				}
+			})
+		}
+	}
+}
+
+var userRoutes = []guardedRoute{
+	{http.MethodGet, "/api/v1/users/u-other"},
+	{http.MethodPut, "/api/v1/users/u-other"},
+	{http.MethodPatch, "/api/v1/users/u-other"},
+	{http.MethodDelete, "/api/v1/users/u-other"},
+	{http.MethodGet, "/api/v1/users/u-other/profile"},
+	{http.MethodPut, "/api/v1/users/u-other/profile"},
+	{http.MethodPatch, "/api/v1/users/u-other/profile"},
+	{http.MethodPut, "/api/v1/users/u-other/avatar"},
+	{http.MethodPost, "/api/v1/users/u-other/tags"},
+	// Listing reaches every account, so it is for administrators only.
+	{http.MethodGet, "/api/v1/users"},
+}
+
+func TestUserRoutesRequireSelfOrAdmin(t *testing.T) {
+	r := newTestRouter()
+	callers := []struct {
+		name, token string
+		status      int
+	}{
+		{"anonymous", "", http.StatusUnauthorized},
+		{"another user", "member", http.StatusForbidden},
+		{"another user's API key", "member-key", http.StatusForbidden},
+	}
+
+	for _, route := range userRoutes {
+		for _, caller := range callers {
+			t.Run(route.method+" "+route.path+"/"+caller.name, func(t *testing.T) {
+				rec := serve(r, route, caller.token)
+				if rec.Code != caller.status {
+					t.Fatalf("status = %d, want %d: %s", rec.Code, caller.status, rec.Body)
+				}
+			})
+		}
+	}
+}
+
+func TestUnconfiguredTenantsAreNotServed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.HeaderTenantID, "acme")
+	rec := httptest.NewRecorder()
+	newTestRouter().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "tenant_not_found") {
+		t.Fatalf("status = %d, want 404 tenant_not_found: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestForwardedForIsOnlyTrustedFromProxies(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		want    string
+	}{
+		{"no trusted proxies", nil, "192.0.2.10"},
+		{"from a trusted proxy", []string{"192.0.2.0/24"}, "203.0.113.7"},
+		{"from another proxy", []string{"198.51.100.1"}, "192.0.2.10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRouter(tt.proxies...)
+			r.GET("/client-ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+			req := httptest.NewRequest(http.MethodGet, "/client-ip", nil)
+			req.RemoteAddr = "192.0.2.10:4711"
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if got := rec.Body.String(); got != tt.want {
+				t.Fatalf("client IP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func serve(r http.Handler, route guardedRoute, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(route.method, route.path, strings.NewReader(`{"role":"admin"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	KeyComponent = "component"
+	KeyRequestID = "request_id"
+	KeyTraceID   = "trace_id"
+	KeyOperation = "op"
+	KeyUserID    = "user_id"
+	KeyTenantID  = "tenant_id"
+	KeyDuration  = "duration_ms"
+	KeyError     = "error"
+)
+
+type Options struct {
+	Level           string
+	Format          string
+	RedactPII       bool
+	ComponentLevels map[string]string
+}
+
+// New builds the root logger. Component loggers derived with
+// logger.With(KeyComponent, name) pick up their level from ComponentLevels.
+func New(w io.Writer, opts Options) (*slog.Logger, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	componentLevels := make(map[string]slog.Level, len(opts.ComponentLevels))
+	for component, raw := range opts.ComponentLevels {
+		l, err := ParseLevel(raw)
+		if err != nil {
+			return nil, fmt.Errorf("component %q: %w", component, err)
+		}
+		componentLevels[component] = l
+	}
+
+	handlerOpts := &slog.HandlerOptions{
+		// The base handler lets everything through; levelHandler decides.
+		Level: slog.Level(-100),
+	}
+	if opts.RedactPII {
+		handlerOpts.ReplaceAttr = redactAttr
+	}
+
+	var base slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "json", "":
+		base = slog.NewJSONHandler(w, handlerOpts)
+	case "text":
+		base = slog.NewTextHandler(w, handlerOpts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", opts.Format)
+	}
+
+	return slog.New(&levelHandler{
+		next:            base,
+		level:           level,
+		defaultLevel:    level,
+		componentLevels: componentLevels,
+	}), nil
+}
+
+func ParseLevel(raw string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(raw)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q", raw)
+	}
+	return level, nil
+}
+
+type contextKey struct{}
+
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger, or slog.Default() when the
+// context carries none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// levelHandler filters records by a minimum level that can be overridden per
+// component.
+type levelHandler struct {
+	next            slog.Handler
+	level           slog.Level
+	defaultLevel    slog.Level
+	componentLevels map[string]slog.Level
+}
+
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *levelHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.next.Handle(ctx, record)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithAttrs(attrs)
+	for _, attr := range attrs {
+		if attr.Key != KeyComponent {
+			continue
+		}
+		if level, ok := h.componentLevels[attr.Value.String()]; ok {
+			clone.level = level
+		} else {
+			clone.level = h.defaultLevel
+		}
+	}
+	return &clone
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	return &clone
+}
@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+var emailKeys = map[string]bool{
+	"email":     true,
+	"new_email": true,
+	"old_email": true,
+}
+
+var nameKeys = map[string]bool{
+	"name":         true,
+	"display_name": true,
+	"given_name":   true,
+	"family_name":  true,
+	"username":     true,
+}
+
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() != slog.KindString {
+		return attr
+	}
+	switch {
+	case emailKeys[attr.Key]:
+		return slog.String(attr.Key, MaskEmail(attr.Value.String()))
+	case nameKeys[attr.Key]:
+		return slog.String(attr.Key, MaskName(attr.Value.String()))
+	}
+	return attr
+}
+
+// MaskEmail keeps the first character of the local part and the domain:
+// "john@example.com" becomes "j***@example.com".
+func MaskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return MaskName(email)
+	}
+	return maskWord(local) + "@" + domain
+}
+
+// MaskName keeps the first character of each word: "John Doe" becomes
+// "J*** D***".
+func MaskName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		words[i] = maskWord(word)
+	}
+	return strings.Join(words, " ")
+}
+
+func maskWord(word string) string {
+	if word == "" {
+		return ""
+	}
+	r, _ := utf8.DecodeRuneInString(word)
+	return string(r) + "***"
+}
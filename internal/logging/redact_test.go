@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name string
+		mask func(string) string
+		in   string
+		want string
+	}{
+		{"email", MaskEmail, "john@example.com", "j***@example.com"},
+		{"email without at", MaskEmail, "john doe", "j*** d***"},
+		{"empty email", MaskEmail, "", ""},
+		{"name", MaskName, "John  Doe", "J*** D***"},
+		{"multibyte name", MaskName, "Élodie Ünal", "É*** Ü***"},
+		{"empty name", MaskName, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mask(tt.in); got != tt.want {
+				t.Fatalf("mask(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactPII(t *testing.T) {
+	attrs := []any{
+		slog.String("email", "jane@example.com"),
+		slog.String("new_email", "jd@example.org"),
+		slog.String("name", "Jane Doe"),
+		slog.String("username", "janed"),
+		slog.String(KeyUserID, "u-1"),
+	}
+
+	for _, tt := range []struct {
+		redact bool
+		want   map[string]any
+	}{
+		{true, map[string]any{"email": "j***@example.com", "new_email": "j***@example.org", "name": "J*** D***", "username": "j***", KeyUserID: "u-1"}},
+		{false, map[string]any{"email": "jane@example.com", "new_email": "jd@example.org", "name": "Jane Doe", "username": "janed", KeyUserID: "u-1"}},
+	} {
+		var out bytes.Buffer
+		logger, err := New(&out, Options{Level: "info", RedactPII: tt.redact})
+		if err != nil {
+			t.Fatal(err)
+		}
+		logger.Info("user created", attrs...)
+
+		var line map[string]any
+		if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+			t.Fatal(err)
+		}
+		for key, want := range tt.want {
+			if line[key] != want {
+				t.Errorf("redact=%t: %s = %v, want %v", tt.redact, key, line[key], want)
+			}
+		}
+
+		out.Reset()
+		logger.Info("count", slog.Int("name", 7))
+		if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+			t.Fatal(err)
+		}
+		if line["name"] != float64(7) {
+			t.Errorf("redact=%t: non-string name = %v, want 7", tt.redact, line["name"])
+		}
+	}
+}